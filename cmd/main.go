@@ -2,20 +2,52 @@ package main
 
 import (
 	"context"
+	adapterCategory "marketplace/internal/adapter/category"
+	adapterConversation "marketplace/internal/adapter/conversation"
+	adapterExchangeRate "marketplace/internal/adapter/exchangerate"
+	adapterImage "marketplace/internal/adapter/image"
+	adapterNotification "marketplace/internal/adapter/notification"
 	adapterPost "marketplace/internal/adapter/post"
+	adapterRealtime "marketplace/internal/adapter/realtime"
+	adapterSavedSearch "marketplace/internal/adapter/savedsearch"
+	adapterSession "marketplace/internal/adapter/session"
 	adapterUser "marketplace/internal/adapter/user"
+	"marketplace/internal/entity"
 	"marketplace/internal/handler"
 	handlerAuth "marketplace/internal/handler/auth"
+	handlerCategory "marketplace/internal/handler/category"
+	handlerConversation "marketplace/internal/handler/conversation"
+	handlerExchangeRate "marketplace/internal/handler/exchangerate"
+	handlerImage "marketplace/internal/handler/image"
+	handlerNotification "marketplace/internal/handler/notification"
 	handlerPost "marketplace/internal/handler/post"
+	handlerRealtime "marketplace/internal/handler/realtime"
+	handlerSavedSearch "marketplace/internal/handler/savedsearch"
 	handlerUser "marketplace/internal/handler/user"
 	serviceAuth "marketplace/internal/service/auth"
+	serviceCategory "marketplace/internal/service/category"
+	serviceConversation "marketplace/internal/service/conversation"
+	serviceExchangeRate "marketplace/internal/service/exchangerate"
+	serviceImage "marketplace/internal/service/image"
+	serviceNotification "marketplace/internal/service/notification"
 	servicePost "marketplace/internal/service/post"
+	serviceRealtime "marketplace/internal/service/realtime"
+	serviceSavedSearch "marketplace/internal/service/savedsearch"
 	serviceUser "marketplace/internal/service/user"
 	usecaseAuth "marketplace/internal/usecase/auth"
+	usecaseCategory "marketplace/internal/usecase/category"
+	usecaseConversation "marketplace/internal/usecase/conversation"
+	usecaseExchangeRate "marketplace/internal/usecase/exchangerate"
+	usecaseImage "marketplace/internal/usecase/image"
+	usecaseNotification "marketplace/internal/usecase/notification"
 	usecasePost "marketplace/internal/usecase/post"
+	usecasePurge "marketplace/internal/usecase/purge"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	usecaseSavedSearch "marketplace/internal/usecase/savedsearch"
 	usecaseUser "marketplace/internal/usecase/user"
 	"marketplace/pkg/config"
 	"marketplace/pkg/logger"
+	"marketplace/pkg/storage"
 
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -44,26 +76,104 @@ func main() {
 	// Инициализация адаптеров
 	postAdapter := adapterPost.NewPostAdapter(dbPool, log)
 	userAdapter := adapterUser.NewUserAdaper(dbPool, log)
+	sessionAdapter := adapterSession.NewSessionAdapter(dbPool, log)
+	categoryAdapter := adapterCategory.NewCategoryAdapter(dbPool, log)
+	imageAdapter := adapterImage.NewImageAdapter(dbPool, log)
+	rateAdapter := adapterExchangeRate.NewExchangeRateAdapter(dbPool, log)
+	searchAdapter := adapterSavedSearch.NewSavedSearchAdapter(dbPool, log)
+	notificationAdapter := adapterNotification.NewNotificationAdapter(dbPool, log)
+	conversationAdapter := adapterConversation.NewConversationAdapter(dbPool, log)
+	eventAdapter := adapterRealtime.NewEventAdapter(dbPool, log)
+
+	// Инициализация хранилища изображений
+	var imageStorage usecaseImage.ImageStorage
+	switch cfg.Images.Storage.Driver {
+	case "s3":
+		imageStorage, err = storage.NewS3Storage(cfg.Images.Storage.S3, nil)
+	default:
+		imageStorage, err = storage.NewLocalStorage(cfg.Images.Storage.LocalDir)
+	}
+	if err != nil {
+		log.WithError(err).Fatal("Failed to init image storage")
+	}
+
+	// Инициализация ключей подписи JWT
+	keySet := usecaseAuth.NewHMACKeySet(cfg.JWT.SecretKey)
+	if cfg.JWT.KeysDir != "" {
+		keySet, err = usecaseAuth.LoadKeySet(cfg.JWT.KeysDir, cfg.JWT.SigningKeyID)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load JWT keys")
+		}
+	}
 
 	// Инициализация AuthService
-	authImpl := usecaseAuth.NewAuthImpl(cfg.JWT.SecretKey)
+	authImpl := usecaseAuth.NewAuthImpl(keySet, cfg.JWT.AccessTTL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Доставка событий подключённым клиентам через LISTEN/NOTIFY
+	postFeed := usecaseRealtime.NewPostFeed(postAdapter, rateAdapter, cfg.PostStream.ReplaySize, cfg.PostStream.MaxSubscribers, log)
+	hub := usecaseRealtime.NewHub(eventAdapter, postAdapter, conversationAdapter, notificationAdapter, postFeed, log)
+	go postFeed.Run(ctx)
+	go hub.Run(ctx)
+
+	// Уведомления создают другие сценарии и фоновые задачи
+	notificationUsecase := usecaseNotification.NewNotificationUsecase(notificationAdapter, hub, log)
+
+	// Фоновая генерация уменьшенных копий изображений
+	variantWorker := usecaseImage.NewVariantWorker(imageAdapter, imageStorage, cfg.Images.Variants.Widths, cfg.Images.Variants.PollInterval, log)
+	go variantWorker.Run(ctx)
+
+	// Окончательное удаление постов и пользователей после срока хранения
+	purgeWorker := usecasePurge.NewPurgeWorker(postAdapter, userAdapter, cfg.Purge.Retention, cfg.Purge.Interval, log)
+	go purgeWorker.Run(ctx)
+
+	// Сверка новых постов с сохранёнными поисками
+	matchWorker := usecaseSavedSearch.NewMatchWorker(searchAdapter, postAdapter, notificationUsecase, cfg.SavedSearches.PollInterval, log)
+	go matchWorker.Run(ctx)
+
+	defaultCurrency, err := entity.ParseCurrency(cfg.Currency.Default)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid currency.default")
+	}
 
 	// Инициализация usecases
-	userUsecase := usecaseUser.NewUserUseCase(userAdapter, authImpl, log)
-	postUsecase := usecasePost.NewPostUsecase(postAdapter, userAdapter, authImpl, log)
+	sessionUsecase := usecaseAuth.NewSessionUseCase(sessionAdapter, userAdapter, authImpl, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL, log)
+	userUsecase := usecaseUser.NewUserUseCase(userAdapter, authImpl, sessionUsecase, log)
+	postUsecase := usecasePost.NewPostUsecase(postAdapter, userAdapter, categoryAdapter, imageAdapter, variantWorker, matchWorker, notificationUsecase, hub, postFeed, rateAdapter, authImpl, defaultCurrency, log)
+	categoryUsecase := usecaseCategory.NewCategoryUsecase(categoryAdapter, log)
+	rateUsecase := usecaseExchangeRate.NewExchangeRateUsecase(rateAdapter, log)
+	imageUsecase := usecaseImage.NewImageUsecase(imageAdapter, imageStorage, cfg.Images.MaxSize, cfg.Images.MaxPixels, log)
+	searchUsecase := usecaseSavedSearch.NewSavedSearchUsecase(searchAdapter, postAdapter, rateAdapter, defaultCurrency, log)
+	conversationUsecase := usecaseConversation.NewConversationUsecase(conversationAdapter, postAdapter, notificationUsecase, hub, log)
 
 	// Инициализация сервисов
-	authService := serviceAuth.NewAuthService(authImpl, log)
+	authService := serviceAuth.NewAuthService(authImpl, sessionUsecase, log)
 	userService := serviceUser.NewUserService(userUsecase, log)
 	postService := servicePost.NewPostService(postUsecase, log)
+	categoryService := serviceCategory.NewCategoryService(categoryUsecase, log)
+	imageService := serviceImage.NewImageService(imageUsecase, log)
+	rateService := serviceExchangeRate.NewExchangeRateService(rateUsecase, log)
+	searchService := serviceSavedSearch.NewSavedSearchService(searchUsecase, log)
+	notificationService := serviceNotification.NewNotificationService(notificationUsecase, log)
+	conversationService := serviceConversation.NewConversationService(conversationUsecase, log)
+	realtimeService := serviceRealtime.NewRealtimeService(hub, log)
 
 	// Инициализация обработчиков
 	authHandler := handlerAuth.NewAuthHandler(authService, log)
 	userHandler := handlerUser.NewUserHandler(userService, log)
 	postHandler := handlerPost.NewPostHandler(postService, userService, log)
+	categoryHandler := handlerCategory.NewCategoryHandler(categoryService, log)
+	imageHandler := handlerImage.NewImageHandler(imageService, cfg.Images.MaxSize, log)
+	rateHandler := handlerExchangeRate.NewExchangeRateHandler(rateService, log)
+	searchHandler := handlerSavedSearch.NewSavedSearchHandler(searchService, log)
+	notificationHandler := handlerNotification.NewNotificationHandler(notificationService, log)
+	conversationHandler := handlerConversation.NewConversationHandler(conversationService, log)
+	realtimeHandler := handlerRealtime.NewRealtimeHandler(authService, realtimeService, log)
 
 	// Настройка маршрутов
-	router := handler.NewRouter(userHandler, postHandler, authHandler)
+	router := handler.NewRouter(userHandler, postHandler, authHandler, categoryHandler, imageHandler, rateHandler, searchHandler, notificationHandler, conversationHandler, realtimeHandler, log)
 	ginRouter := router.SetupRoutes()
 
 	// Запуск сервера
@@ -0,0 +1,16 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type CategoryAdapterInterface interface {
+	Create(ctx context.Context, category *entity.Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error)
+	List(ctx context.Context) ([]*entity.Category, error)
+	Update(ctx context.Context, category *entity.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
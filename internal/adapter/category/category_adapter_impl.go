@@ -0,0 +1,231 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewCategoryAdapter(db *pgxpool.Pool, logger *logrus.Logger) *CategoryAdapter {
+	return &CategoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *CategoryAdapter) Create(ctx context.Context, category *entity.Category) error {
+	query, args, err := squirrel.Insert("categories").
+		Columns("id", "parent_id", "name", "slug", "attributes", "created_at").
+		Values(category.ID, category.ParentID, category.Name, category.Slug, categoryAttributes(category), category.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create category query")
+		return fmt.Errorf("create category query: %w", err)
+	}
+
+	_, err = a.db.Exec(ctx, query, args...)
+	if err != nil {
+		if pgerror.IsUniqueViolation(err) {
+			return apperror.Conflict("category with slug %s already exists", category.Slug)
+		}
+		if pgerror.IsForeignKeyViolation(err) {
+			return apperror.InvalidField("parent_id", "parent category not found")
+		}
+		a.logger.WithError(err).Error("Failed to create category")
+		return fmt.Errorf("create category: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"category_id": category.ID,
+		"slug":        category.Slug,
+	}).Info("Category created in database")
+	return nil
+}
+
+func (a *CategoryAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	query, args, err := squirrel.Select("id", "parent_id", "name", "slug", "attributes", "created_at").
+		From("categories").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get category by ID query")
+		return nil, fmt.Errorf("get category by ID query: %w", err)
+	}
+
+	var category entity.Category
+	err = a.db.QueryRow(ctx, query, args...).Scan(&category.ID, &category.ParentID, &category.Name, &category.Slug, &category.Attributes, &category.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("category not found")
+		}
+		a.logger.WithError(err).Error("Failed to get category by ID")
+		return nil, fmt.Errorf("get category by id: %w", err)
+	}
+	return &category, nil
+}
+
+func (a *CategoryAdapter) List(ctx context.Context) ([]*entity.Category, error) {
+	query, args, err := squirrel.Select("id", "parent_id", "name", "slug", "attributes", "created_at").
+		From("categories").
+		OrderBy("name ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list categories query")
+		return nil, fmt.Errorf("list categories query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list categories")
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		var category entity.Category
+		if err := rows.Scan(&category.ID, &category.ParentID, &category.Name, &category.Slug, &category.Attributes, &category.CreatedAt); err != nil {
+			a.logger.WithError(err).Error("Failed to scan category row")
+			return nil, fmt.Errorf("scan category: %w", err)
+		}
+		categories = append(categories, &category)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating category rows")
+		return nil, fmt.Errorf("iterate categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (a *CategoryAdapter) Update(ctx context.Context, category *entity.Category) error {
+	query, args, err := squirrel.Update("categories").
+		Set("parent_id", category.ParentID).
+		Set("name", category.Name).
+		Set("slug", category.Slug).
+		Set("attributes", categoryAttributes(category)).
+		Where(squirrel.Eq{"id": category.ID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build update category query")
+		return fmt.Errorf("update category query: %w", err)
+	}
+
+	err = pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		if category.ParentID != nil {
+			if err := checkParent(ctx, tx, category.ID, *category.ParentID); err != nil {
+				return err
+			}
+		}
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return apperror.NotFound("category not found")
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) || errors.Is(err, apperror.ErrValidation) {
+			return err
+		}
+		if pgerror.IsUniqueViolation(err) {
+			return apperror.Conflict("category with slug %s already exists", category.Slug)
+		}
+		if pgerror.IsForeignKeyViolation(err) {
+			return apperror.InvalidField("parent_id", "parent category not found")
+		}
+		a.logger.WithError(err).Error("Failed to update category")
+		return fmt.Errorf("update category: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"category_id": category.ID,
+	}).Info("Category updated in database")
+	return nil
+}
+
+// checkParent не даёт перенести категорию внутрь её собственного поддерева.
+// Перед проверкой блокирует все категории: иначе два одновременных переноса
+// могут по отдельности пройти проверку и вместе замкнуть цикл.
+func checkParent(ctx context.Context, tx pgx.Tx, id, parentID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, "SELECT id FROM categories ORDER BY id FOR UPDATE"); err != nil {
+		return err
+	}
+
+	var parentExists, isDescendant bool
+	err := tx.QueryRow(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id = $1
+			UNION
+			SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT COUNT(*) > 0, COALESCE(bool_or(id = $2), false) FROM ancestors`,
+		parentID, id).Scan(&parentExists, &isDescendant)
+	if err != nil {
+		return err
+	}
+	if !parentExists {
+		return apperror.InvalidField("parent_id", "parent category not found")
+	}
+	if isDescendant {
+		return apperror.InvalidField("parent_id", "category can't be moved into its own subcategory")
+	}
+	return nil
+}
+
+func (a *CategoryAdapter) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Delete("categories").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build delete category query")
+		return fmt.Errorf("delete category query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		if pgerror.IsForeignKeyViolation(err) {
+			return apperror.Conflict("category has subcategories or posts")
+		}
+		a.logger.WithError(err).Error("Failed to delete category")
+		return fmt.Errorf("delete category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("category not found")
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category deleted from database")
+	return nil
+}
+
+// categoryAttributes не даёт записать NULL в колонку attributes.
+func categoryAttributes(category *entity.Category) []entity.Attribute {
+	if category.Attributes == nil {
+		return []entity.Attribute{}
+	}
+	return category.Attributes
+}
@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Block добавляет пользователя в чёрный список. Повторная блокировка ничего
+// не меняет.
+func (a *ConversationAdapter) Block(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	query, args, err := squirrel.Insert("user_blocks").
+		Columns("user_id", "blocked_user_id", "created_at").
+		Values(userID, blockedUserID, time.Now()).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build block user query")
+		return fmt.Errorf("block user query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		if pgerror.IsForeignKeyViolation(err) {
+			return apperror.NotFound("user not found")
+		}
+		a.logger.WithError(err).Error("Failed to block user")
+		return fmt.Errorf("block user: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"user_id":         userID,
+		"blocked_user_id": blockedUserID,
+	}).Info("User blocked in database")
+	return nil
+}
+
+// Unblock убирает пользователя из чёрного списка. Если его там не было,
+// ничего не меняется.
+func (a *ConversationAdapter) Unblock(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	query, args, err := squirrel.Delete("user_blocks").
+		Where(squirrel.Eq{"user_id": userID, "blocked_user_id": blockedUserID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build unblock user query")
+		return fmt.Errorf("unblock user query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to unblock user")
+		return fmt.Errorf("unblock user: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"user_id":         userID,
+		"blocked_user_id": blockedUserID,
+	}).Info("User unblocked in database")
+	return nil
+}
+
+// IsBlocked сообщает, есть ли blockedUserID в чёрном списке userID.
+func (a *ConversationAdapter) IsBlocked(ctx context.Context, userID, blockedUserID uuid.UUID) (bool, error) {
+	query, args, err := squirrel.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("user_blocks").
+		Where(squirrel.Eq{"user_id": userID, "blocked_user_id": blockedUserID}).
+		Suffix(")").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build is blocked query")
+		return false, fmt.Errorf("is blocked query: %w", err)
+	}
+
+	var blocked bool
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&blocked); err != nil {
+		a.logger.WithError(err).Error("Failed to check block")
+		return false, fmt.Errorf("check block: %w", err)
+	}
+	return blocked, nil
+}
+
+// ListBlocked возвращает чёрный список пользователя, начиная с последних
+// заблокированных.
+func (a *ConversationAdapter) ListBlocked(ctx context.Context, userID uuid.UUID) ([]*entity.BlockedUser, error) {
+	query, args, err := squirrel.Select("b.blocked_user_id", "u.username", "b.created_at").
+		From("user_blocks b").
+		Join("users u ON b.blocked_user_id = u.id").
+		Where(squirrel.Eq{"b.user_id": userID}).
+		OrderBy("b.created_at DESC", "b.blocked_user_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list blocked users query")
+		return nil, fmt.Errorf("list blocked users query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list blocked users")
+		return nil, fmt.Errorf("list blocked users: %w", err)
+	}
+	defer rows.Close()
+
+	var blocked []*entity.BlockedUser
+	for rows.Next() {
+		var user entity.BlockedUser
+		if err := rows.Scan(&user.UserID, &user.Username, &user.CreatedAt); err != nil {
+			a.logger.WithError(err).Error("Failed to scan blocked user row")
+			return nil, fmt.Errorf("scan blocked user: %w", err)
+		}
+		blocked = append(blocked, &user)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating blocked user rows")
+		return nil, fmt.Errorf("iterate blocked users: %w", err)
+	}
+
+	return blocked, nil
+}
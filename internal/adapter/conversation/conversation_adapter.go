@@ -0,0 +1,23 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type ConversationAdapterInterface interface {
+	Create(ctx context.Context, conversation *entity.Conversation) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*entity.Conversation, error)
+	GetByPostAndBuyer(ctx context.Context, postID, buyerID uuid.UUID) (*entity.Conversation, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Conversation, int, error)
+	AddMessage(ctx context.Context, message *entity.Message) error
+	GetMessage(ctx context.Context, id uuid.UUID) (*entity.Message, error)
+	ListMessages(ctx context.Context, conversationID uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, error)
+	MarkRead(ctx context.Context, conversationID, readerID uuid.UUID) (int64, error)
+	Block(ctx context.Context, userID, blockedUserID uuid.UUID) error
+	Unblock(ctx context.Context, userID, blockedUserID uuid.UUID) error
+	IsBlocked(ctx context.Context, userID, blockedUserID uuid.UUID) (bool, error)
+	ListBlocked(ctx context.Context, userID uuid.UUID) ([]*entity.BlockedUser, error)
+}
@@ -0,0 +1,294 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// unreadCount — число непрочитанных пользователем сообщений в переписке c.
+const unreadCount = "(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id AND m.sender_id <> ? AND m.read_at IS NULL)"
+
+// selectConversations выбирает переписки с заголовком поста и числом
+// сообщений, которые не прочитал пользователь userID.
+func selectConversations(userID uuid.UUID) squirrel.SelectBuilder {
+	return squirrel.Select("c.id", "c.post_id", "p.header", "c.buyer_id", "c.seller_id", "c.created_at", "c.last_message_at").
+		Column(squirrel.Expr(unreadCount, userID)).
+		From("conversations c").
+		Join("posts p ON c.post_id = p.id")
+}
+
+func conversationDest(conversation *entity.Conversation) []interface{} {
+	return []interface{}{&conversation.ID, &conversation.PostID, &conversation.PostHeader, &conversation.BuyerID, &conversation.SellerID, &conversation.CreatedAt, &conversation.LastMessageAt, &conversation.UnreadCount}
+}
+
+type ConversationAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewConversationAdapter(db *pgxpool.Pool, logger *logrus.Logger) *ConversationAdapter {
+	return &ConversationAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет новую переписку. Если у покупателя уже есть переписка по
+// этому посту, возвращает apperror.Conflict.
+func (a *ConversationAdapter) Create(ctx context.Context, conversation *entity.Conversation) error {
+	query, args, err := squirrel.Insert("conversations").
+		Columns("id", "post_id", "buyer_id", "seller_id", "created_at", "last_message_at").
+		Values(conversation.ID, conversation.PostID, conversation.BuyerID, conversation.SellerID, conversation.CreatedAt, conversation.LastMessageAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create conversation query")
+		return fmt.Errorf("create conversation query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		switch {
+		case pgerror.IsUniqueViolation(err):
+			return apperror.Conflict("conversation already exists")
+		case pgerror.IsForeignKeyViolation(err):
+			return apperror.NotFound("post not found")
+		}
+		a.logger.WithError(err).Error("Failed to create conversation")
+		return fmt.Errorf("create conversation: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"conversation_id": conversation.ID,
+		"post_id":         conversation.PostID,
+		"buyer_id":        conversation.BuyerID,
+	}).Info("Conversation created in database")
+	return nil
+}
+
+// GetByID возвращает переписку с числом сообщений, не прочитанных
+// пользователем userID.
+func (a *ConversationAdapter) GetByID(ctx context.Context, id, userID uuid.UUID) (*entity.Conversation, error) {
+	query, args, err := selectConversations(userID).
+		Where(squirrel.Eq{"c.id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get conversation by ID query")
+		return nil, fmt.Errorf("get conversation by ID query: %w", err)
+	}
+
+	return a.queryOne(ctx, query, args...)
+}
+
+// GetByPostAndBuyer возвращает переписку покупателя по посту.
+func (a *ConversationAdapter) GetByPostAndBuyer(ctx context.Context, postID, buyerID uuid.UUID) (*entity.Conversation, error) {
+	query, args, err := selectConversations(buyerID).
+		Where(squirrel.Eq{"c.post_id": postID, "c.buyer_id": buyerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get conversation by post query")
+		return nil, fmt.Errorf("get conversation by post query: %w", err)
+	}
+
+	return a.queryOne(ctx, query, args...)
+}
+
+func (a *ConversationAdapter) queryOne(ctx context.Context, query string, args ...interface{}) (*entity.Conversation, error) {
+	var conversation entity.Conversation
+	if err := a.db.QueryRow(ctx, query, args...).Scan(conversationDest(&conversation)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("conversation not found")
+		}
+		a.logger.WithError(err).Error("Failed to get conversation")
+		return nil, fmt.Errorf("get conversation: %w", err)
+	}
+	return &conversation, nil
+}
+
+// ListByUserID выбирает страницу переписок, в которых участвует пользователь,
+// начиная с последних по времени сообщения.
+func (a *ConversationAdapter) ListByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Conversation, int, error) {
+	participant := squirrel.Or{squirrel.Eq{"c.buyer_id": userID}, squirrel.Eq{"c.seller_id": userID}}
+
+	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
+		From("conversations c").
+		Where(participant).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count query for conversations")
+		return nil, 0, fmt.Errorf("count conversations query: %w", err)
+	}
+	var total int
+	if err := a.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		a.logger.WithError(err).Error("Failed to count conversations")
+		return nil, 0, fmt.Errorf("count conversations: %w", err)
+	}
+
+	query, args, err := selectConversations(userID).
+		Where(participant).
+		OrderBy("c.last_message_at DESC", "c.id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((page - 1) * pageSize)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list conversations query")
+		return nil, 0, fmt.Errorf("list conversations query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list conversations")
+		return nil, 0, fmt.Errorf("list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []*entity.Conversation
+	for rows.Next() {
+		var conversation entity.Conversation
+		if err := rows.Scan(conversationDest(&conversation)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan conversation row")
+			return nil, 0, fmt.Errorf("scan conversation: %w", err)
+		}
+		conversations = append(conversations, &conversation)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating conversation rows")
+		return nil, 0, fmt.Errorf("iterate conversations: %w", err)
+	}
+
+	return conversations, total, nil
+}
+
+// AddMessage сохраняет сообщение и поднимает переписку в начало списка.
+func (a *ConversationAdapter) AddMessage(ctx context.Context, message *entity.Message) error {
+	query, args, err := squirrel.Insert("messages").
+		Prefix("WITH touched AS (UPDATE conversations SET last_message_at = ? WHERE id = ?)", message.CreatedAt, message.ConversationID).
+		Columns("id", "conversation_id", "sender_id", "body", "created_at").
+		Values(message.ID, message.ConversationID, message.SenderID, message.Body, message.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build add message query")
+		return fmt.Errorf("add message query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		if pgerror.IsForeignKeyViolation(err) {
+			return apperror.NotFound("conversation not found")
+		}
+		a.logger.WithError(err).Error("Failed to add message")
+		return fmt.Errorf("add message: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"message_id":      message.ID,
+		"conversation_id": message.ConversationID,
+		"sender_id":       message.SenderID,
+	}).Info("Message added in database")
+	return nil
+}
+
+// ListMessages выбирает до limit сообщений переписки, начиная с последних.
+// С курсором выбираются сообщения, отправленные раньше него.
+func (a *ConversationAdapter) ListMessages(ctx context.Context, conversationID uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, error) {
+	builder := squirrel.Select("id", "conversation_id", "sender_id", "body", "created_at", "read_at").
+		From("messages").
+		Where(squirrel.Eq{"conversation_id": conversationID})
+	if cursor != nil {
+		builder = builder.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	query, args, err := builder.
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list messages query")
+		return nil, fmt.Errorf("list messages query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list messages")
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*entity.Message
+	for rows.Next() {
+		var message entity.Message
+		if err := rows.Scan(&message.ID, &message.ConversationID, &message.SenderID, &message.Body, &message.CreatedAt, &message.ReadAt); err != nil {
+			a.logger.WithError(err).Error("Failed to scan message row")
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, &message)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating message rows")
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// GetMessage возвращает сообщение по ID без проверки участия в переписке:
+// по нему хаб событий загружает содержимое нового сообщения.
+func (a *ConversationAdapter) GetMessage(ctx context.Context, id uuid.UUID) (*entity.Message, error) {
+	query, args, err := squirrel.Select("id", "conversation_id", "sender_id", "body", "created_at", "read_at").
+		From("messages").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get message query")
+		return nil, fmt.Errorf("get message query: %w", err)
+	}
+
+	var message entity.Message
+	err = a.db.QueryRow(ctx, query, args...).Scan(&message.ID, &message.ConversationID, &message.SenderID, &message.Body, &message.CreatedAt, &message.ReadAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("message not found")
+		}
+		a.logger.WithError(err).Error("Failed to get message")
+		return nil, fmt.Errorf("get message: %w", err)
+	}
+	return &message, nil
+}
+
+// MarkRead отмечает прочитанными все сообщения собеседника в переписке и
+// возвращает их число.
+func (a *ConversationAdapter) MarkRead(ctx context.Context, conversationID, readerID uuid.UUID) (int64, error) {
+	query, args, err := squirrel.Update("messages").
+		Set("read_at", time.Now()).
+		Where(squirrel.Eq{"conversation_id": conversationID, "read_at": nil}).
+		Where(squirrel.NotEq{"sender_id": readerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark messages read query")
+		return 0, fmt.Errorf("mark messages read query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to mark messages read")
+		return 0, fmt.Errorf("mark messages read: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,117 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type ExchangeRateAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewExchangeRateAdapter(db *pgxpool.Pool, logger *logrus.Logger) *ExchangeRateAdapter {
+	return &ExchangeRateAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *ExchangeRateAdapter) List(ctx context.Context) ([]*entity.ExchangeRate, error) {
+	query, args, err := squirrel.Select("base", "quote", "TRIM_SCALE(rate)::TEXT", "updated_at").
+		From("exchange_rates").
+		OrderBy("base ASC", "quote ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list exchange rates query")
+		return nil, fmt.Errorf("list exchange rates query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list exchange rates")
+		return nil, fmt.Errorf("list exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make([]*entity.ExchangeRate, 0)
+	for rows.Next() {
+		var rate entity.ExchangeRate
+		if err := rows.Scan(&rate.Base, &rate.Quote, &rate.Rate, &rate.UpdatedAt); err != nil {
+			a.logger.WithError(err).Error("Failed to scan exchange rate row")
+			return nil, fmt.Errorf("scan exchange rate: %w", err)
+		}
+		rates = append(rates, &rate)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating exchange rate rows")
+		return nil, fmt.Errorf("iterate exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Upsert сохраняет курсы одной транзакцией: при импорте либо применяются
+// все строки, либо ни одной.
+func (a *ExchangeRateAdapter) Upsert(ctx context.Context, rates []*entity.ExchangeRate) error {
+	builder := squirrel.Insert("exchange_rates").
+		Columns("base", "quote", "rate", "updated_at").
+		Suffix("ON CONFLICT (base, quote) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at").
+		PlaceholderFormat(squirrel.Dollar)
+
+	err := pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		for _, rate := range rates {
+			query, args, err := builder.Values(rate.Base, rate.Quote, squirrel.Expr("?::TEXT::NUMERIC", rate.Rate), rate.UpdatedAt).ToSql()
+			if err != nil {
+				return fmt.Errorf("upsert exchange rate query: %w", err)
+			}
+			if _, err := tx.Exec(ctx, query, args...); err != nil {
+				return fmt.Errorf("upsert exchange rate %s/%s: %w", rate.Base, rate.Quote, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to upsert exchange rates")
+		return err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"count": len(rates),
+	}).Info("Exchange rates saved in database")
+	return nil
+}
+
+func (a *ExchangeRateAdapter) Delete(ctx context.Context, base, quote entity.Currency) error {
+	query, args, err := squirrel.Delete("exchange_rates").
+		Where(squirrel.Eq{"base": base, "quote": quote}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build delete exchange rate query")
+		return fmt.Errorf("delete exchange rate query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to delete exchange rate")
+		return fmt.Errorf("delete exchange rate: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("exchange rate %s/%s not found", base, quote)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"base":  base,
+		"quote": quote,
+	}).Info("Exchange rate deleted from database")
+	return nil
+}
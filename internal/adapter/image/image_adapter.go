@@ -0,0 +1,21 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImageAdapterInterface interface {
+	Create(ctx context.Context, image *entity.Image) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Image, error)
+	ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Image, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListPendingVariants(ctx context.Context, limit int) ([]*entity.Image, error)
+	MarkVariantsGenerated(ctx context.Context, id uuid.UUID) error
+	DeferVariants(ctx context.Context, id uuid.UUID, backoff time.Duration) (int, error)
+	SaveVariant(ctx context.Context, variant *entity.ImageVariant) error
+	GetVariant(ctx context.Context, imageID uuid.UUID, width int, format string) (*entity.ImageVariant, error)
+}
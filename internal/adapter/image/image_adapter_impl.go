@@ -0,0 +1,262 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type ImageAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewImageAdapter(db *pgxpool.Pool, logger *logrus.Logger) *ImageAdapter {
+	return &ImageAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+var imageColumns = []string{"id", "owner_id", "storage_key", "content_type", "size", "width", "height", "created_at"}
+
+func imageDest(image *entity.Image) []interface{} {
+	return []interface{}{&image.ID, &image.OwnerID, &image.StorageKey, &image.ContentType, &image.Size, &image.Width, &image.Height, &image.CreatedAt}
+}
+
+func (a *ImageAdapter) Create(ctx context.Context, image *entity.Image) error {
+	query, args, err := squirrel.Insert("images").
+		Columns("id", "owner_id", "storage_key", "content_type", "size", "width", "height", "created_at").
+		Values(image.ID, image.OwnerID, image.StorageKey, image.ContentType, image.Size, image.Width, image.Height, image.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create image query")
+		return fmt.Errorf("create image query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to create image")
+		return fmt.Errorf("create image: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"image_id": image.ID,
+		"owner_id": image.OwnerID,
+	}).Info("Image created in database")
+	return nil
+}
+
+func (a *ImageAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.Image, error) {
+	query, args, err := squirrel.Select(imageColumns...).
+		From("images").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get image by ID query")
+		return nil, fmt.Errorf("get image by ID query: %w", err)
+	}
+
+	var image entity.Image
+	err = a.db.QueryRow(ctx, query, args...).Scan(imageDest(&image)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("image not found")
+		}
+		a.logger.WithError(err).Error("Failed to get image by ID")
+		return nil, fmt.Errorf("get image by id: %w", err)
+	}
+	return &image, nil
+}
+
+func (a *ImageAdapter) ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Image, error) {
+	if len(ids) == 0 {
+		return []*entity.Image{}, nil
+	}
+
+	query, args, err := squirrel.Select(imageColumns...).
+		From("images").
+		Where(squirrel.Eq{"id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list images query")
+		return nil, fmt.Errorf("list images query: %w", err)
+	}
+
+	return a.queryImages(ctx, query, args...)
+}
+
+func (a *ImageAdapter) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Delete("images").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build delete image query")
+		return fmt.Errorf("delete image query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to delete image")
+		return fmt.Errorf("delete image: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("image not found")
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"image_id": id,
+	}).Info("Image deleted from database")
+	return nil
+}
+
+// ListPendingVariants возвращает изображения из галерей постов, для которых
+// ещё не построены уменьшенные копии. Отложенные после неудачи изображения
+// возвращаются, когда подходит время повтора.
+func (a *ImageAdapter) ListPendingVariants(ctx context.Context, limit int) ([]*entity.Image, error) {
+	query, args, err := squirrel.Select(imageColumns...).
+		From("images i").
+		Where("i.variants_generated_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM post_images pi WHERE pi.image_id = i.id)").
+		Where("(i.variants_retry_at IS NULL OR i.variants_retry_at <= ?)", time.Now()).
+		OrderBy("i.created_at").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build pending variants query")
+		return nil, fmt.Errorf("pending variants query: %w", err)
+	}
+
+	return a.queryImages(ctx, query, args...)
+}
+
+func (a *ImageAdapter) MarkVariantsGenerated(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Update("images").
+		Set("variants_generated_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark variants generated query")
+		return fmt.Errorf("mark variants generated query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to mark variants generated")
+		return fmt.Errorf("mark variants generated: %w", err)
+	}
+	return nil
+}
+
+// DeferVariants откладывает генерацию копий после неудачной попытки на
+// backoff, удвоенный за каждую предыдущую неудачу. Возвращает число неудачных
+// попыток.
+func (a *ImageAdapter) DeferVariants(ctx context.Context, id uuid.UUID, backoff time.Duration) (int, error) {
+	query, args, err := squirrel.Update("images").
+		Set("variant_attempts", squirrel.Expr("variant_attempts + 1")).
+		Set("variants_retry_at", squirrel.Expr("?::timestamptz + make_interval(secs => ? * power(2, variant_attempts))", time.Now(), backoff.Seconds())).
+		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING variant_attempts").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build defer variants query")
+		return 0, fmt.Errorf("defer variants query: %w", err)
+	}
+
+	var attempts int
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&attempts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.NotFound("image not found")
+		}
+		a.logger.WithError(err).Error("Failed to defer variants")
+		return 0, fmt.Errorf("defer variants: %w", err)
+	}
+	return attempts, nil
+}
+
+func (a *ImageAdapter) SaveVariant(ctx context.Context, variant *entity.ImageVariant) error {
+	query, args, err := squirrel.Insert("image_variants").
+		Columns("image_id", "width", "format", "height", "content_type", "size", "storage_key", "created_at").
+		Values(variant.ImageID, variant.Width, variant.Format, variant.Height, variant.ContentType, variant.Size, variant.StorageKey, variant.CreatedAt).
+		Suffix("ON CONFLICT (image_id, width, format) DO UPDATE SET height = EXCLUDED.height, size = EXCLUDED.size, created_at = EXCLUDED.created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build save image variant query")
+		return fmt.Errorf("save image variant query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to save image variant")
+		return fmt.Errorf("save image variant: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"image_id": variant.ImageID,
+		"width":    variant.Width,
+		"format":   variant.Format,
+	}).Info("Image variant saved in database")
+	return nil
+}
+
+func (a *ImageAdapter) GetVariant(ctx context.Context, imageID uuid.UUID, width int, format string) (*entity.ImageVariant, error) {
+	query, args, err := squirrel.Select("image_id", "width", "format", "height", "content_type", "size", "storage_key", "created_at").
+		From("image_variants").
+		Where(squirrel.Eq{"image_id": imageID, "width": width, "format": format}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get image variant query")
+		return nil, fmt.Errorf("get image variant query: %w", err)
+	}
+
+	var variant entity.ImageVariant
+	err = a.db.QueryRow(ctx, query, args...).Scan(&variant.ImageID, &variant.Width, &variant.Format, &variant.Height, &variant.ContentType, &variant.Size, &variant.StorageKey, &variant.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("image variant not found")
+		}
+		a.logger.WithError(err).Error("Failed to get image variant")
+		return nil, fmt.Errorf("get image variant: %w", err)
+	}
+	return &variant, nil
+}
+
+func (a *ImageAdapter) queryImages(ctx context.Context, query string, args ...interface{}) ([]*entity.Image, error) {
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list images")
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+	defer rows.Close()
+
+	images := make([]*entity.Image, 0)
+	for rows.Next() {
+		var image entity.Image
+		if err := rows.Scan(imageDest(&image)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan image row")
+			return nil, fmt.Errorf("scan image: %w", err)
+		}
+		images = append(images, &image)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating image rows")
+		return nil, fmt.Errorf("iterate images: %w", err)
+	}
+
+	return images, nil
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type NotificationAdapterInterface interface {
+	Create(ctx context.Context, notification *entity.Notification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (entity.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID uuid.UUID, preferences entity.NotificationPreferences) error
+}
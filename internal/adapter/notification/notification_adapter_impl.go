@@ -0,0 +1,274 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewNotificationAdapter(db *pgxpool.Pool, logger *logrus.Logger) *NotificationAdapter {
+	return &NotificationAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *NotificationAdapter) Create(ctx context.Context, notification *entity.Notification) error {
+	data := notification.Data
+	if data == nil {
+		data = entity.NotificationData{}
+	}
+
+	query, args, err := squirrel.Insert("notifications").
+		Columns("id", "user_id", "kind", "data", "created_at").
+		Values(notification.ID, notification.UserID, notification.Kind, data, notification.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create notification query")
+		return fmt.Errorf("create notification query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to create notification")
+		return fmt.Errorf("create notification: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"notification_id": notification.ID,
+		"user_id":         notification.UserID,
+		"kind":            notification.Kind,
+	}).Info("Notification created in database")
+	return nil
+}
+
+// List выбирает страницу уведомлений пользователя, начиная с последних.
+func (a *NotificationAdapter) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, error) {
+	conditions := squirrel.And{squirrel.Eq{"user_id": userID}}
+	if unreadOnly {
+		conditions = append(conditions, squirrel.Eq{"read_at": nil})
+	}
+
+	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
+		From("notifications").
+		Where(conditions).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count query for notifications")
+		return nil, 0, fmt.Errorf("count notifications query: %w", err)
+	}
+	var total int
+	if err := a.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		a.logger.WithError(err).Error("Failed to count notifications")
+		return nil, 0, fmt.Errorf("count notifications: %w", err)
+	}
+
+	query, args, err := squirrel.Select("id", "user_id", "kind", "data", "created_at", "read_at").
+		From("notifications").
+		Where(conditions).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((page - 1) * pageSize)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list notifications query")
+		return nil, 0, fmt.Errorf("list notifications query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list notifications")
+		return nil, 0, fmt.Errorf("list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*entity.Notification
+	for rows.Next() {
+		var notification entity.Notification
+		if err := rows.Scan(&notification.ID, &notification.UserID, &notification.Kind, &notification.Data, &notification.CreatedAt, &notification.ReadAt); err != nil {
+			a.logger.WithError(err).Error("Failed to scan notification row")
+			return nil, 0, fmt.Errorf("scan notification: %w", err)
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating notification rows")
+		return nil, 0, fmt.Errorf("iterate notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// GetByID возвращает уведомление по ID без проверки владельца: по нему хаб
+// событий загружает содержимое нового уведомления.
+func (a *NotificationAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	query, args, err := squirrel.Select("id", "user_id", "kind", "data", "created_at", "read_at").
+		From("notifications").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get notification query")
+		return nil, fmt.Errorf("get notification query: %w", err)
+	}
+
+	var notification entity.Notification
+	err = a.db.QueryRow(ctx, query, args...).Scan(&notification.ID, &notification.UserID, &notification.Kind, &notification.Data, &notification.CreatedAt, &notification.ReadAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("notification not found")
+		}
+		a.logger.WithError(err).Error("Failed to get notification")
+		return nil, fmt.Errorf("get notification: %w", err)
+	}
+	return &notification, nil
+}
+
+func (a *NotificationAdapter) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query, args, err := squirrel.Select("COUNT(*)").
+		From("notifications").
+		Where(squirrel.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count unread notifications query")
+		return 0, fmt.Errorf("count unread notifications query: %w", err)
+	}
+
+	var count int
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		a.logger.WithError(err).Error("Failed to count unread notifications")
+		return 0, fmt.Errorf("count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным. Повторная отметка
+// не меняет время прочтения. Чужое уведомление считается не найденным.
+func (a *NotificationAdapter) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	query, args, err := squirrel.Update("notifications").
+		Set("read_at", squirrel.Expr("COALESCE(read_at, ?)", time.Now())).
+		Where(squirrel.Eq{"id": id, "user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark notification read query")
+		return fmt.Errorf("mark notification read query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to mark notification read")
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("notification not found")
+	}
+	return nil
+}
+
+func (a *NotificationAdapter) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query, args, err := squirrel.Update("notifications").
+		Set("read_at", time.Now()).
+		Where(squirrel.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark all notifications read query")
+		return 0, fmt.Errorf("mark all notifications read query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to mark all notifications read")
+		return 0, fmt.Errorf("mark all notifications read: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// GetPreferences возвращает только явно заданные настройки пользователя.
+func (a *NotificationAdapter) GetPreferences(ctx context.Context, userID uuid.UUID) (entity.NotificationPreferences, error) {
+	query, args, err := squirrel.Select("kind", "enabled").
+		From("notification_preferences").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get notification preferences query")
+		return nil, fmt.Errorf("get notification preferences query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get notification preferences")
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make(entity.NotificationPreferences)
+	for rows.Next() {
+		var kind entity.NotificationKind
+		var enabled bool
+		if err := rows.Scan(&kind, &enabled); err != nil {
+			a.logger.WithError(err).Error("Failed to scan notification preference row")
+			return nil, fmt.Errorf("scan notification preference: %w", err)
+		}
+		preferences[kind] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating notification preference rows")
+		return nil, fmt.Errorf("iterate notification preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// SetPreferences сохраняет переданные настройки, не трогая остальные.
+func (a *NotificationAdapter) SetPreferences(ctx context.Context, userID uuid.UUID, preferences entity.NotificationPreferences) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(preferences))
+	for kind := range preferences {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+
+	builder := squirrel.Insert("notification_preferences").
+		Columns("user_id", "kind", "enabled").
+		Suffix("ON CONFLICT (user_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, kind := range kinds {
+		builder = builder.Values(userID, kind, preferences[entity.NotificationKind(kind)])
+	}
+	query, args, err := builder.ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build set notification preferences query")
+		return fmt.Errorf("set notification preferences query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to set notification preferences")
+		return fmt.Errorf("set notification preferences: %w", err)
+	}
+
+	a.logger.WithField("user_id", userID).Info("Notification preferences saved in database")
+	return nil
+}
@@ -0,0 +1,211 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// favoritesCount — число пользователей, добавивших пост в избранное.
+const favoritesCount = "(SELECT COUNT(*) FROM favorites fc WHERE fc.post_id = p.id)"
+
+// AddFavorite добавляет пост в избранное пользователя и возвращает, сколько
+// раз пост добавлен в избранное и был ли он добавлен сейчас. Повторное
+// добавление ничего не меняет.
+func (a *PostAdapter) AddFavorite(ctx context.Context, userID, postID uuid.UUID) (int, bool, error) {
+	// Основной запрос не видит строку, вставленную в WITH, поэтому её
+	// добавляем к количеству отдельно.
+	query, args, err := squirrel.Select("COUNT(*) + (SELECT COUNT(*) FROM added)", "EXISTS (SELECT 1 FROM added)").
+		Prefix("WITH added AS (INSERT INTO favorites (user_id, post_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT DO NOTHING RETURNING post_id)", userID, postID).
+		From("favorites").
+		Where(squirrel.Eq{"post_id": postID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build add favorite query")
+		return 0, false, fmt.Errorf("add favorite query: %w", err)
+	}
+
+	var count int
+	var added bool
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&count, &added); err != nil {
+		if pgerror.IsForeignKeyViolation(err) {
+			return 0, false, apperror.NotFound("post not found")
+		}
+		a.logger.WithError(err).Error("Failed to add favorite")
+		return 0, false, fmt.Errorf("add favorite: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"post_id": postID,
+	}).Info("Favorite added in database")
+	return count, added, nil
+}
+
+// RemoveFavorite убирает пост из избранного пользователя и возвращает,
+// сколько раз пост остался в избранном. Если поста в избранном не было,
+// ничего не меняется.
+func (a *PostAdapter) RemoveFavorite(ctx context.Context, userID, postID uuid.UUID) (int, error) {
+	query, args, err := squirrel.Select("COUNT(*) - (SELECT COUNT(*) FROM removed)").
+		Prefix("WITH removed AS (DELETE FROM favorites WHERE user_id = ? AND post_id = ? RETURNING post_id)", userID, postID).
+		From("favorites").
+		Where(squirrel.Eq{"post_id": postID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build remove favorite query")
+		return 0, fmt.Errorf("remove favorite query: %w", err)
+	}
+
+	var count int
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		a.logger.WithError(err).Error("Failed to remove favorite")
+		return 0, fmt.Errorf("remove favorite: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"post_id": postID,
+	}).Info("Favorite removed from database")
+	return count, nil
+}
+
+// FavoritePostIDs возвращает посты из postIDs, которые пользователь добавил в
+// избранное.
+func (a *PostAdapter) FavoritePostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	favorites := make(map[uuid.UUID]bool)
+	if len(postIDs) == 0 {
+		return favorites, nil
+	}
+
+	query, args, err := squirrel.Select("post_id").
+		From("favorites").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where("post_id = ANY(?)", postIDs).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build favorite post IDs query")
+		return nil, fmt.Errorf("favorite post IDs query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get favorite post IDs")
+		return nil, fmt.Errorf("get favorite post IDs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		if err := rows.Scan(&postID); err != nil {
+			a.logger.WithError(err).Error("Failed to scan favorite row")
+			return nil, fmt.Errorf("scan favorite: %w", err)
+		}
+		favorites[postID] = true
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating favorite rows")
+		return nil, fmt.Errorf("iterate favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+// FavoritedBy возвращает пользователей из userIDs, у которых пост в
+// избранном.
+func (a *PostAdapter) FavoritedBy(ctx context.Context, postID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := squirrel.Select("user_id").
+		From("favorites").
+		Where(squirrel.Eq{"post_id": postID}).
+		Where("user_id = ANY(?)", userIDs).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build favorited by query")
+		return nil, fmt.Errorf("favorited by query: %w", err)
+	}
+
+	return a.queryIDs(ctx, query, args...)
+}
+
+// ListFavorites выбирает страницу избранного пользователя, начиная с
+// последних добавленных. Удалённые посты и посты, скрытые автором в черновики
+// или архив, не показываются.
+func (a *PostAdapter) ListFavorites(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error) {
+	conditions := squirrel.And{
+		squirrel.Eq{"f.user_id": userID},
+		squirrel.Eq{"p.status": entity.PublicPostStatuses()},
+		notDeleted,
+	}
+
+	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
+		From("favorites f").
+		Join("posts p ON f.post_id = p.id").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count query for favorites")
+		return nil, 0, fmt.Errorf("count favorites query: %w", err)
+	}
+	var total int
+	if err := a.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		a.logger.WithError(err).Error("Failed to count favorites")
+		return nil, 0, fmt.Errorf("count favorites: %w", err)
+	}
+
+	query, args, err := squirrel.Select(postColumns...).
+		From("favorites f").
+		Join("posts p ON f.post_id = p.id").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		OrderBy("f.created_at DESC", "p.id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((page - 1) * pageSize)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list favorites query")
+		return nil, 0, fmt.Errorf("list favorites query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list favorites")
+		return nil, 0, fmt.Errorf("list favorites: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		if err := rows.Scan(postDest(&post)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan favorite post row")
+			return nil, 0, fmt.Errorf("scan favorite post: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating favorite post rows")
+		return nil, 0, fmt.Errorf("iterate favorite posts: %w", err)
+	}
+	rows.Close()
+
+	if err := a.attachImages(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
@@ -0,0 +1,380 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Границы подсветки в ts_headline. Используются символы из области частного
+// использования, чтобы после экранирования HTML заменить их на <mark>.
+const (
+	snippetStart = "\uE000"
+	snippetStop  = "\uE001"
+)
+
+// earthRadiusKm — средний радиус Земли. maxRadiusKm — половина длины
+// экватора: дальше друг от друга точки на Земле не бывают.
+const (
+	earthRadiusKm = 6371.0
+	maxRadiusKm   = math.Pi * earthRadiusKm
+)
+
+const snippetOptions = "StartSel=" + snippetStart + ", StopSel=" + snippetStop + ", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// postColumns — колонки поста вместе с именем автора; порядок совпадает с postDest.
+var postColumns = []string{"p.id", "p.header", "p.content", "p.image", "p.price", "p.currency", "p.category_id", "p.tags", "p.attributes", "p.lat", "p.lng", "p.city", "p.author_id", "u.username", "p.created_at", "p.status", "p.version", favoritesCount}
+
+func postDest(post *entity.Post) []interface{} {
+	return []interface{}{&post.ID, &post.Header, &post.Content, &post.Image, &post.Price.Amount, &post.Price.Currency, &post.CategoryID, &post.Tags, &post.Attributes, &post.Lat, &post.Lng, &post.City, &post.AuthorID, &post.AuthorUsername, &post.CreatedAt, &post.Status, &post.Version, &post.FavoritesCount}
+}
+
+// notDeleted скрывает удалённые посты и посты удалённых пользователей.
+// Запрос должен соединять posts p и users u.
+var notDeleted = squirrel.Eq{"p.deleted_at": nil, "u.deleted_at": nil}
+
+// postTags не даёт записать NULL в колонку tags.
+func postTags(post *entity.Post) []string {
+	if post.Tags == nil {
+		return []string{}
+	}
+	return post.Tags
+}
+
+// postAttributes не даёт записать NULL в колонку attributes.
+func postAttributes(post *entity.Post) entity.Attributes {
+	if post.Attributes == nil {
+		return entity.Attributes{}
+	}
+	return post.Attributes
+}
+
+// postSortColumns — допустимые поля сортировки и соответствующие им колонки.
+var postSortColumns = map[string]string{
+	"created_at": "p.created_at",
+	"price":      "p.price",
+	"rank":       "rank",
+	"distance":   "distance_km",
+}
+
+// postFilters переводит фильтры из запроса в условия выборки постов.
+func postFilters(filter map[string]string) (squirrel.And, error) {
+	conditions := squirrel.And{}
+
+	// Границы цены задаются в основных единицах валюты currency и
+	// сравниваются с ценами постов, пересчитанными по таблице курсов.
+	currency, err := filterCurrency(filter)
+	if err != nil {
+		return nil, err
+	}
+	if minPrice, ok := filter["min_price"]; ok {
+		price, err := priceBound("min_price", minPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		sql, args := convertedPrice(currency)
+		conditions = append(conditions, squirrel.Expr(sql+" >= ?", append(args, price)...))
+	}
+	if maxPrice, ok := filter["max_price"]; ok {
+		price, err := priceBound("max_price", maxPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		sql, args := convertedPrice(currency)
+		conditions = append(conditions, squirrel.Expr(sql+" <= ?", append(args, price)...))
+	}
+	if category := filter["category"]; category != "" {
+		// Категория вместе со всеми её подкатегориями. CYCLE обрывает обход,
+		// если в дереве всё же оказался цикл.
+		conditions = append(conditions, squirrel.Expr(`p.category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = ?
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			) CYCLE id SET is_cycle USING path
+			SELECT id FROM tree
+		)`, category))
+	}
+	if tags := filter["tag"]; tags != "" {
+		conditions = append(conditions, squirrel.Expr("p.tags @> ?", strings.Split(tags, ",")))
+	}
+	if status := filter["status"]; status != "" {
+		conditions = append(conditions, squirrel.Eq{"p.status": status})
+	}
+	if search := filter["q"]; search != "" {
+		conditions = append(conditions, squirrel.Expr("p.search_vector @@ websearch_to_tsquery('simple', ?)", search))
+	}
+
+	conditions = append(conditions, attributeFilters(filter)...)
+
+	geo, err := geoFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, geo...)
+
+	return conditions, nil
+}
+
+// attributeFilterPrefix — префикс ключей фильтра по характеристикам:
+// attr.condition=new.
+const attributeFilterPrefix = "attr."
+
+// attributeFilters переводит фильтры attr.<name> в условия на вхождение
+// документа в характеристики поста. Тип характеристики адаптеру неизвестен,
+// поэтому значение, похожее на число, сравнивается и как строка, и как число.
+func attributeFilters(filter map[string]string) squirrel.And {
+	var names []string
+	for key := range filter {
+		if name, ok := strings.CutPrefix(key, attributeFilterPrefix); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	conditions := squirrel.And{}
+	for _, name := range names {
+		value := filter[attributeFilterPrefix+name]
+		variants := squirrel.Or{attributeContains(name, value)}
+		if number, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+			variants = append(variants, attributeContains(name, number))
+		}
+		conditions = append(conditions, variants)
+	}
+	return conditions
+}
+
+func attributeContains(name string, value interface{}) squirrel.Sqlizer {
+	doc, _ := json.Marshal(map[string]interface{}{name: value})
+	return squirrel.Expr("p.attributes @> ?::JSONB", string(doc))
+}
+
+// geoOrigin — точка, от которой считается расстояние до постов.
+type geoOrigin struct {
+	lat, lng float64
+}
+
+// filterOrigin возвращает точку из параметров lat и lng или nil, если они не
+// заданы.
+func filterOrigin(filter map[string]string) (*geoOrigin, error) {
+	latValue, hasLat := filter["lat"]
+	lngValue, hasLng := filter["lng"]
+	if !hasLat && !hasLng {
+		return nil, nil
+	}
+	if !hasLat || !hasLng {
+		return nil, apperror.InvalidField("lat", "lat and lng must be set together")
+	}
+
+	var v apperror.Violations
+	lat, err := strconv.ParseFloat(latValue, 64)
+	if err != nil || !entity.ValidLatitude(lat) {
+		v.Add("lat", "lat must be a number between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(lngValue, 64)
+	if err != nil || !entity.ValidLongitude(lng) {
+		v.Add("lng", "lng must be a number between -180 and 180")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return &geoOrigin{lat: lat, lng: lng}, nil
+}
+
+// distanceSQL возвращает выражение для расстояния в километрах от origin до
+// поста по формуле гаверсинусов. У постов без координат оно равно NULL.
+func distanceSQL(origin geoOrigin) (string, []interface{}) {
+	return `(2 * ? * ASIN(SQRT(LEAST(1,
+			POWER(SIN(RADIANS(p.lat - ?) / 2), 2) +
+			COS(RADIANS(?)) * COS(RADIANS(p.lat)) * POWER(SIN(RADIANS(p.lng - ?) / 2), 2)
+		))))`, []interface{}{earthRadiusKm, origin.lat, origin.lat, origin.lng}
+}
+
+// distanceColumn — столбец distance_km списка постов: расстояние от origin,
+// округлённое до сотых километра.
+func distanceColumn(origin geoOrigin) squirrel.Sqlizer {
+	sql, args := distanceSQL(origin)
+	return squirrel.Expr("ROUND(("+sql+")::NUMERIC, 2)::FLOAT8 AS distance_km", args...)
+}
+
+// geoFilters переводит параметры radius_km (вместе с lat и lng) и bbox в
+// условия выборки. Посты без координат под них не попадают.
+func geoFilters(filter map[string]string) (squirrel.And, error) {
+	conditions := squirrel.And{}
+
+	origin, err := filterOrigin(filter)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := filter["radius_km"]; ok {
+		if origin == nil {
+			return nil, apperror.InvalidField("radius_km", "radius_km requires the lat and lng parameters")
+		}
+		radius, err := strconv.ParseFloat(value, 64)
+		if err != nil || !(radius > 0 && radius <= maxRadiusKm) {
+			return nil, apperror.InvalidField("radius_km", "radius_km must be a positive number not greater than %.0f", maxRadiusKm)
+		}
+		// Диапазон широт отсекает далёкие посты по индексу до точного расчёта.
+		delta := radius / earthRadiusKm * 180 / math.Pi
+		sql, args := distanceSQL(*origin)
+		conditions = append(conditions,
+			squirrel.Expr("p.lat BETWEEN ? AND ?", origin.lat-delta, origin.lat+delta),
+			squirrel.Expr(sql+" <= ?", append(args, radius)...),
+		)
+	}
+
+	if value, ok := filter["bbox"]; ok {
+		box, err := parseBBox(value)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, squirrel.Expr("p.lat BETWEEN ? AND ?", box[1], box[3]))
+		if box[0] <= box[2] {
+			conditions = append(conditions, squirrel.Expr("p.lng BETWEEN ? AND ?", box[0], box[2]))
+		} else {
+			// Прямоугольник пересекает 180-й меридиан.
+			conditions = append(conditions, squirrel.Expr("(p.lng >= ? OR p.lng <= ?)", box[0], box[2]))
+		}
+	}
+
+	return conditions, nil
+}
+
+// parseBBox разбирает прямоугольник «min_lng,min_lat,max_lng,max_lat».
+// min_lng может быть больше max_lng, если прямоугольник пересекает
+// 180-й меридиан.
+func parseBBox(value string) ([4]float64, error) {
+	var box [4]float64
+	invalid := apperror.InvalidField("bbox", "bbox must be min_lng,min_lat,max_lng,max_lat")
+
+	parts := strings.Split(value, ",")
+	if len(parts) != len(box) {
+		return box, invalid
+	}
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return box, invalid
+		}
+		box[i] = number
+	}
+	if !entity.ValidLongitude(box[0]) || !entity.ValidLongitude(box[2]) || !entity.ValidLatitude(box[1]) || !entity.ValidLatitude(box[3]) || box[1] > box[3] {
+		return box, invalid
+	}
+	return box, nil
+}
+
+// filterCurrency возвращает валюту, в которой заданы границы цены и идёт
+// сортировка по цене.
+func filterCurrency(filter map[string]string) (entity.Currency, error) {
+	code, ok := filter["currency"]
+	if !ok {
+		return "", nil
+	}
+	currency, err := entity.ParseCurrency(code)
+	if err != nil {
+		return "", apperror.InvalidField("currency", "unsupported currency %q", code)
+	}
+	return currency, nil
+}
+
+// convertedPrice возвращает выражение для цены поста в минимальных единицах
+// валюты currency: по прямому курсу из exchange_rates, а если его нет — по
+// обратному. Если курса нет совсем, выражение равно NULL и пост не проходит
+// фильтр по цене. Округление совпадает с entity.ExchangeRates.Convert.
+func convertedPrice(currency entity.Currency) (string, []interface{}) {
+	return `ROUND(CASE WHEN p.currency = ? THEN p.price ELSE COALESCE(
+			p.price * (SELECT r.rate FROM exchange_rates r WHERE r.base = p.currency AND r.quote = ?),
+			p.price / (SELECT r.rate FROM exchange_rates r WHERE r.base = ? AND r.quote = p.currency)
+		) * ? / ` + minorUnitsSQL + ` END)`, []interface{}{currency, currency, currency, currency.MinorUnits()}
+}
+
+// minorUnitsSQL — число минимальных единиц в основной для валюты поста.
+var minorUnitsSQL = func() string {
+	var b strings.Builder
+	b.WriteString("CASE p.currency")
+	for _, currency := range entity.Currencies() {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", currency, currency.MinorUnits())
+	}
+	b.WriteString(" END")
+	return b.String()
+}()
+
+// priceBound переводит границу цены из основных единиц валюты в минимальные.
+func priceBound(field, value string, currency entity.Currency) (int64, error) {
+	if currency == "" {
+		return 0, apperror.InvalidField(field, "%s requires the currency parameter", field)
+	}
+	price, err := entity.ParseAmount(value, currency)
+	if err != nil {
+		return 0, apperror.InvalidField(field, "%s must be an amount in %s", field, currency)
+	}
+	return price, nil
+}
+
+// postOrderBy проверяет параметр sortBy и возвращает выражение ORDER BY.
+// По умолчанию результаты поиска сортируются по релевантности, остальные
+// списки — по дате создания. Цены сравниваются в валюте из фильтра currency,
+// расстояние считается от точки lat и lng; посты без курса или без координат
+// идут в конце.
+func postOrderBy(sortBy string, filter map[string]string) (squirrel.Sqlizer, error) {
+	search := filter["q"] != ""
+	currency, err := filterCurrency(filter)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := filterOrigin(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if sortBy == "" {
+		sortBy = "created_at DESC"
+		if search {
+			sortBy = "rank DESC"
+		}
+	}
+
+	parts := strings.Split(sortBy, " ")
+	if len(parts) != 2 || (parts[1] != "ASC" && parts[1] != "DESC") {
+		return nil, apperror.InvalidField("sortBy", "invalid sortBy parameter")
+	}
+	column, ok := postSortColumns[parts[0]]
+	if !ok {
+		return nil, apperror.InvalidField("sortBy", "invalid sortBy parameter")
+	}
+	if parts[0] == "rank" && !search {
+		return nil, apperror.InvalidField("sortBy", "sorting by rank requires the q parameter")
+	}
+
+	if parts[0] == "distance" && origin == nil {
+		return nil, apperror.InvalidField("sortBy", "sorting by distance requires the lat and lng parameters")
+	}
+
+	if parts[0] == "price" && currency != "" {
+		sql, args := convertedPrice(currency)
+		return squirrel.Expr(sql+" "+parts[1]+" NULLS LAST, p.id "+parts[1], args...), nil
+	}
+	if parts[0] == "distance" {
+		sql, args := distanceSQL(*origin)
+		return squirrel.Expr(sql+" "+parts[1]+" NULLS LAST, p.id "+parts[1], args...), nil
+	}
+	return squirrel.Expr(column + " " + parts[1] + ", p.id " + parts[1]), nil
+}
+
+// highlightSnippet экранирует фрагмент текста поста и размечает найденные
+// слова тегом <mark>.
+func highlightSnippet(snippet string) string {
+	if snippet == "" {
+		return ""
+	}
+	return strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>").Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,140 @@
+package adapter
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostFilters(t *testing.T) {
+	conditions, err := postFilters(map[string]string{"currency": "rub", "min_price": "10", "max_price": "99.5", "q": "red bike"})
+	assert.NoError(t, err)
+
+	sql, args, err := squirrel.Select("p.id").From("posts p").Where(conditions).PlaceholderFormat(squirrel.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "END) >= $5 AND ROUND(")
+	assert.Contains(t, sql, "WHEN 'JPY' THEN 1")
+	assert.Contains(t, sql, "END) <= $10 AND p.search_vector @@ websearch_to_tsquery('simple', $11)")
+	rub := entity.Currency("RUB")
+	assert.Equal(t, []interface{}{rub, rub, rub, int64(100), int64(1000), rub, rub, rub, int64(100), int64(9950), "red bike"}, args)
+
+	conditions, err = postFilters(map[string]string{"category": "bicycles", "tag": "red,kids", "status": "published"})
+	assert.NoError(t, err)
+	sql, args, err = squirrel.Select("p.id").From("posts p").Where(conditions).PlaceholderFormat(squirrel.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "SELECT id FROM categories WHERE slug = $1")
+	assert.Contains(t, sql, "p.tags @> $2")
+	assert.Contains(t, sql, "p.status = $3")
+	assert.Equal(t, []interface{}{"bicycles", []string{"red", "kids"}, "published"}, args)
+
+	_, err = postFilters(map[string]string{"currency": "RUB", "min_price": "cheap"})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	_, err = postFilters(map[string]string{"min_price": "10"})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	_, err = postFilters(map[string]string{"currency": "XXX"})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func TestPostOrderBy(t *testing.T) {
+	tests := []struct {
+		sortBy  string
+		search  bool
+		orderBy string
+		wantErr bool
+	}{
+		{"", false, "p.created_at DESC, p.id DESC", false},
+		{"", true, "rank DESC, p.id DESC", false},
+		{"price ASC", true, "p.price ASC, p.id ASC", false},
+		{"rank DESC", false, "", true},
+		{"price; DROP TABLE posts", false, "", true},
+		{"author_id ASC", false, "", true},
+		{"distance ASC", false, "", true},
+	}
+
+	for _, tt := range tests {
+		filter := map[string]string{}
+		if tt.search {
+			filter["q"] = "bike"
+		}
+		orderBy, err := postOrderBy(tt.sortBy, filter)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, apperror.ErrValidation, tt.sortBy)
+			continue
+		}
+		assert.NoError(t, err)
+		sql, _, err := orderBy.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.orderBy, sql)
+	}
+
+	orderBy, err := postOrderBy("price DESC", map[string]string{"currency": "USD"})
+	assert.NoError(t, err)
+	sql, args, err := orderBy.ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "FROM exchange_rates r")
+	assert.True(t, strings.HasSuffix(sql, "DESC NULLS LAST, p.id DESC"), sql)
+	assert.Equal(t, []interface{}{entity.Currency("USD"), entity.Currency("USD"), entity.Currency("USD"), int64(100)}, args)
+
+	orderBy, err = postOrderBy("distance ASC", map[string]string{"lat": "55.75", "lng": "37.62"})
+	assert.NoError(t, err)
+	sql, args, err = orderBy.ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "ASIN(SQRT(")
+	assert.True(t, strings.HasSuffix(sql, "ASC NULLS LAST, p.id ASC"), sql)
+	assert.Equal(t, []interface{}{earthRadiusKm, 55.75, 55.75, 37.62}, args)
+}
+
+func TestAttributeFilters(t *testing.T) {
+	conditions, err := postFilters(map[string]string{"attr.size": "42", "attr.condition": "new"})
+	assert.NoError(t, err)
+	sql, args, err := squirrel.Select("p.id").From("posts p").Where(conditions).PlaceholderFormat(squirrel.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "(p.attributes @> $1::JSONB) AND (p.attributes @> $2::JSONB OR p.attributes @> $3::JSONB)")
+	assert.Equal(t, []interface{}{`{"condition":"new"}`, `{"size":"42"}`, `{"size":42}`}, args)
+}
+
+func TestGeoFilters(t *testing.T) {
+	conditions, err := postFilters(map[string]string{"lat": "55.75", "lng": "37.62", "radius_km": "10"})
+	assert.NoError(t, err)
+	sql, args, err := squirrel.Select("p.id").From("posts p").Where(conditions).PlaceholderFormat(squirrel.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "p.lat BETWEEN $1 AND $2 AND (2 * $3 * ASIN(")
+	assert.Contains(t, sql, ")))) <= $7")
+	assert.InDelta(t, 55.75-10/earthRadiusKm*180/math.Pi, args[0], 1e-9)
+	assert.Equal(t, []interface{}{earthRadiusKm, 55.75, 55.75, 37.62, 10.0}, args[2:])
+
+	conditions, err = postFilters(map[string]string{"bbox": "179,-10,-179,10"})
+	assert.NoError(t, err)
+	sql, args, err = squirrel.Select("p.id").From("posts p").Where(conditions).PlaceholderFormat(squirrel.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "p.lat BETWEEN $1 AND $2 AND (p.lng >= $3 OR p.lng <= $4)")
+	assert.Equal(t, []interface{}{-10.0, 10.0, 179.0, -179.0}, args)
+
+	invalid := []map[string]string{
+		{"radius_km": "10"},
+		{"lat": "55.75"},
+		{"lat": "91", "lng": "0"},
+		{"lat": "55.75", "lng": "37.62", "radius_km": "-1"},
+		{"lat": "55.75", "lng": "37.62", "radius_km": "NaN"},
+		{"bbox": "1,2,3"},
+		{"bbox": "0,10,1,-10"},
+	}
+	for _, filter := range invalid {
+		_, err := postFilters(filter)
+		assert.ErrorIs(t, err, apperror.ErrValidation, filter)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	snippet := "a <b>" + snippetStart + "red" + snippetStop + "</b> bike"
+	assert.Equal(t, "a &lt;b&gt;<mark>red</mark>&lt;/b&gt; bike", highlightSnippet(snippet))
+	assert.Equal(t, "", highlightSnippet(""))
+}
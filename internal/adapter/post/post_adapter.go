@@ -3,16 +3,35 @@ package adapter
 import (
 	"context"
 	"marketplace/internal/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PostAdapterInterface interface {
-	Create(ctx context.Context, post *entity.Post) error
+	Create(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
 	ListByAuthorID(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	GetByHeaderAndContent(ctx context.Context, header, content string) (*entity.Post, error)
-	Update(ctx context.Context, post *entity.Post) error
+	Update(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.PostStatus) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID uuid.UUID, number int) (*entity.PostRevision, error)
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	AddFavorite(ctx context.Context, userID, postID uuid.UUID) (int, bool, error)
+	RemoveFavorite(ctx context.Context, userID, postID uuid.UUID) (int, error)
+	FavoritePostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	FavoritedBy(ctx context.Context, postID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+	ListFavorites(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error)
+	CheckFilter(filter map[string]string) error
+	ListUnmatched(ctx context.Context, limit int) ([]uuid.UUID, error)
+	MatchFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) ([]uuid.UUID, error)
+	MatchFeedFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) (map[uuid.UUID]*float64, error)
+	PostAuthors(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	MarkSearchesMatched(ctx context.Context, postIDs []uuid.UUID) error
+	ListSavedSearchMatches(ctx context.Context, searchID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
@@ -3,12 +3,16 @@ package adapter
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
-	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 )
@@ -25,14 +29,15 @@ func NewPostAdapter(db *pgxpool.Pool, logger *logrus.Logger) *PostAdapter {
 	}
 }
 
-func (a *PostAdapter) Create(ctx context.Context, post *entity.Post) error {
+func (a *PostAdapter) Create(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error {
 	// Проверка на уникальность поста
 	queryCheck, argsCheck, err := squirrel.Select("id").
 		From("posts").
 		Where(squirrel.Eq{
-			"header":    post.Header,
-			"content":   post.Content,
-			"author_id": post.AuthorID,
+			"header":     post.Header,
+			"content":    post.Content,
+			"author_id":  post.AuthorID,
+			"deleted_at": nil,
 		}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
@@ -47,25 +52,36 @@ func (a *PostAdapter) Create(ctx context.Context, post *entity.Post) error {
 			"header":    post.Header,
 			"author_id": post.AuthorID,
 		}).Warn("Post with the same header, content, and author already exists")
-		return fmt.Errorf("post with the same header, content, and author already exists")
+		return apperror.Conflict("post with the same header, content, and author already exists")
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, sql.ErrNoRows) {
 		a.logger.WithError(err).Error("Failed to check post existence")
 		return fmt.Errorf("check post existence: %w", err)
 	}
 
 	// Создание поста
 	query, args, err := squirrel.Insert("posts").
-		Columns("id", "header", "content", "image", "price", "author_id", "created_at").
-		Values(post.ID, post.Header, post.Content, post.Image, post.Price, post.AuthorID, post.CreatedAt).
+		Columns("id", "header", "content", "image", "price", "currency", "category_id", "tags", "attributes", "lat", "lng", "city", "author_id", "created_at", "status", "version").
+		Values(post.ID, post.Header, post.Content, post.Image, post.Price.Amount, post.Price.Currency, post.CategoryID, postTags(post), postAttributes(post), post.Lat, post.Lng, post.City, post.AuthorID, post.CreatedAt, post.Status, post.Version).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to build create post query")
 		return fmt.Errorf("create post query: %w", err)
 	}
-	_, err = a.db.Exec(ctx, query, args...)
+	err = pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+		if err := a.saveImages(ctx, tx, post); err != nil {
+			return err
+		}
+		return a.saveRevision(ctx, tx, revision)
+	})
 	if err != nil {
+		if pgerror.IsForeignKeyViolation(err) {
+			return foreignKeyViolation(err)
+		}
 		a.logger.WithError(err).Error("Failed to create post")
 		return fmt.Errorf("create post: %w", err)
 	}
@@ -77,10 +93,11 @@ func (a *PostAdapter) Create(ctx context.Context, post *entity.Post) error {
 }
 
 func (a *PostAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
-	query, args, err := squirrel.Select("p.id", "p.header", "p.content", "p.image", "p.price", "p.author_id", "u.username", "p.created_at").
+	query, args, err := squirrel.Select(postColumns...).
 		From("posts p").
 		Join("users u ON p.author_id = u.id").
 		Where(squirrel.Eq{"p.id": id}).
+		Where(notDeleted).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -88,135 +105,135 @@ func (a *PostAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post,
 		return nil, fmt.Errorf("get post by ID query: %w", err)
 	}
 	var post entity.Post
-	var username string
-	err = a.db.QueryRow(ctx, query, args...).Scan(&post.ID, &post.Header, &post.Content, &post.Image, &post.Price, &post.AuthorID, &username, &post.CreatedAt)
+	err = a.db.QueryRow(ctx, query, args...).Scan(postDest(&post)...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("post not found: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("post not found")
 		}
 		a.logger.WithError(err).Error("Failed to get post by ID")
 		return nil, fmt.Errorf("get post by id: %w", err)
 	}
-	post.AuthorUsername = username
+	if err := a.attachImages(ctx, []*entity.Post{&post}); err != nil {
+		return nil, err
+	}
 	return &post, nil
 }
 
 func (a *PostAdapter) ListByAuthorID(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
-	queryBuilder := squirrel.Select("p.id", "p.header", "p.content", "p.image", "p.price", "p.author_id", "u.username", "p.created_at").
-		From("posts p").
-		Join("users u ON p.author_id = u.id").
-		Where(squirrel.Eq{"p.author_id": authorID}).
-		PlaceholderFormat(squirrel.Dollar)
-
-	if minPrice, ok := filter["min_price"]; ok {
-		queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"p.price": minPrice})
-	}
-	if maxPrice, ok := filter["max_price"]; ok {
-		queryBuilder = queryBuilder.Where(squirrel.LtOrEq{"p.price": maxPrice})
+	posts, total, err := a.list(ctx, squirrel.Eq{"p.author_id": authorID}, page, pageSize, sortBy, filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if sortBy == "" {
-		sortBy = "created_at DESC"
-	} else {
-		validSortFields := map[string]bool{"created_at": true, "price": true}
-		parts := strings.Split(sortBy, " ")
-		if len(parts) != 2 || !validSortFields[parts[0]] || (parts[1] != "ASC" && parts[1] != "DESC") {
-			return nil, 0, fmt.Errorf("invalid sortBy parameter")
-		}
+	a.logger.WithFields(logrus.Fields{
+		"author_id":   authorID,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_posts": total,
+	}).Info("Posts by author listed from database")
+	return posts, total, nil
+}
+
+func (a *PostAdapter) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
+	posts, total, err := a.list(ctx, nil, page, pageSize, sortBy, filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	queryBuilder = queryBuilder.OrderBy(sortBy)
+	a.logger.WithFields(logrus.Fields{
+		"page":        page,
+		"page_size":   pageSize,
+		"total_posts": total,
+	}).Info("Posts listed from database")
+	return posts, total, nil
+}
+
+func (a *PostAdapter) CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	return a.categoryFacets(ctx, nil, filter)
+}
 
-	// Запрос для подсчёта общего количества
-	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
-		From("posts p").
-		Where(squirrel.Eq{"p.author_id": authorID}).
-		PlaceholderFormat(squirrel.Dollar).
-		ToSql()
+func (a *PostAdapter) CategoryFacetsByAuthorID(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	return a.categoryFacets(ctx, squirrel.Eq{"p.author_id": authorID}, filter)
+}
+
+// categoryFacets считает посты по категориям с теми же условиями, что и list.
+func (a *PostAdapter) categoryFacets(ctx context.Context, where squirrel.Sqlizer, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	conditions, err := postFilters(filter)
 	if err != nil {
-		a.logger.WithError(err).Error("Failed to build count query for posts by author")
-		return nil, 0, fmt.Errorf("count query: %w", err)
+		return nil, err
 	}
-	var total int
-	err = a.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, 0, fmt.Errorf("no posts found for author")
-		}
-		a.logger.WithError(err).Error("Failed to count posts by author")
-		return nil, 0, fmt.Errorf("count posts: %w", err)
+	conditions = append(squirrel.And{notDeleted}, conditions...)
+	if where != nil {
+		conditions = append(squirrel.And{where}, conditions...)
 	}
 
-	// Пагинация
-	offset := (page - 1) * pageSize
-	queryBuilder = queryBuilder.Limit(uint64(pageSize)).Offset(uint64(offset))
-
-	query, args, err := queryBuilder.ToSql()
+	query, args, err := squirrel.Select("c.id", "c.slug", "c.name", "COUNT(*)").
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Join("categories c ON c.id = p.category_id").
+		Where(conditions).
+		GroupBy("c.id", "c.slug", "c.name").
+		OrderBy("COUNT(*) DESC", "c.name ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
 	if err != nil {
-		a.logger.WithError(err).Error("Failed to build list posts by author query")
-		return nil, 0, fmt.Errorf("list posts query: %w", err)
+		a.logger.WithError(err).Error("Failed to build category facets query")
+		return nil, fmt.Errorf("category facets query: %w", err)
 	}
 
 	rows, err := a.db.Query(ctx, query, args...)
 	if err != nil {
-		a.logger.WithError(err).Error("Failed to list posts by author")
-		return nil, 0, fmt.Errorf("list posts: %w", err)
+		a.logger.WithError(err).Error("Failed to count posts by category")
+		return nil, fmt.Errorf("category facets: %w", err)
 	}
 	defer rows.Close()
 
-	var posts []*entity.Post
+	facets := make([]*entity.CategoryFacet, 0)
 	for rows.Next() {
-		var post entity.Post
-		var username string
-		err := rows.Scan(&post.ID, &post.Header, &post.Content, &post.Image, &post.Price, &post.AuthorID, &username, &post.CreatedAt)
-		if err != nil {
-			a.logger.WithError(err).Error("Failed to scan post row")
-			return nil, 0, fmt.Errorf("scan post: %w", err)
+		var facet entity.CategoryFacet
+		if err := rows.Scan(&facet.CategoryID, &facet.Slug, &facet.Name, &facet.Count); err != nil {
+			a.logger.WithError(err).Error("Failed to scan category facet row")
+			return nil, fmt.Errorf("scan category facet: %w", err)
 		}
-		post.AuthorUsername = username
-		posts = append(posts, &post)
+		facets = append(facets, &facet)
 	}
 	if err := rows.Err(); err != nil {
-		a.logger.WithError(err).Error("Error iterating post rows")
-		return nil, 0, fmt.Errorf("iterate posts: %w", err)
+		a.logger.WithError(err).Error("Error iterating category facet rows")
+		return nil, fmt.Errorf("iterate category facets: %w", err)
 	}
 
-	a.logger.WithFields(logrus.Fields{
-		"author_id":   authorID,
-		"page":        page,
-		"page_size":   pageSize,
-		"total_posts": total,
-	}).Info("Posts by author listed from database")
-	return posts, total, nil
+	return facets, nil
 }
 
-func (a *PostAdapter) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
-	queryBuilder := squirrel.Select("p.id", "p.header", "p.content", "p.image", "p.price", "p.author_id", "u.username", "p.created_at").
-		From("posts p").
-		Join("users u ON p.author_id = u.id").
-		PlaceholderFormat(squirrel.Dollar)
-
-	if minPrice, ok := filter["min_price"]; ok {
-		queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"p.price": minPrice})
+// list выбирает страницу постов. Условие where и фильтры применяются и к
+// выборке, и к подсчёту общего количества.
+func (a *PostAdapter) list(ctx context.Context, where squirrel.Sqlizer, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
+	conditions, err := postFilters(filter)
+	if err != nil {
+		return nil, 0, err
 	}
-	if maxPrice, ok := filter["max_price"]; ok {
-		queryBuilder = queryBuilder.Where(squirrel.LtOrEq{"p.price": maxPrice})
+	conditions = append(squirrel.And{notDeleted}, conditions...)
+	if where != nil {
+		conditions = append(squirrel.And{where}, conditions...)
 	}
 
-	if sortBy == "" {
-		sortBy = "created_at DESC"
-	} else {
-		validSortFields := map[string]bool{"created_at": true, "price": true}
-		parts := strings.Split(sortBy, " ")
-		if len(parts) != 2 || !validSortFields[parts[0]] || (parts[1] != "ASC" && parts[1] != "DESC") {
-			return nil, 0, fmt.Errorf("invalid sortBy parameter")
-		}
+	search := filter["q"]
+	orderBy, err := postOrderBy(sortBy, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	origin, err := filterOrigin(filter)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	queryBuilder = queryBuilder.OrderBy(sortBy)
 
 	// Запрос для подсчёта общего количества
-	countQuery, countArgs, err := squirrel.Select("COUNT(*)").From("posts p").PlaceholderFormat(squirrel.Dollar).ToSql()
+	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to build count query for posts")
 		return nil, 0, fmt.Errorf("count query: %w", err)
@@ -228,6 +245,22 @@ func (a *PostAdapter) ListPosts(ctx context.Context, page, pageSize int, sortBy
 		return nil, 0, fmt.Errorf("count posts: %w", err)
 	}
 
+	queryBuilder := squirrel.Select(postColumns...).
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		OrderByClause(orderBy).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if search != "" {
+		queryBuilder = queryBuilder.
+			Column(squirrel.Expr("ts_rank(p.search_vector, websearch_to_tsquery('simple', ?)) AS rank", search)).
+			Column(squirrel.Expr("ts_headline('simple', p.content, websearch_to_tsquery('simple', ?), ?) AS snippet", search, snippetOptions))
+	}
+	if origin != nil {
+		queryBuilder = queryBuilder.Column(distanceColumn(*origin))
+	}
+
 	// Пагинация
 	offset := (page - 1) * pageSize
 	queryBuilder = queryBuilder.Limit(uint64(pageSize)).Offset(uint64(offset))
@@ -248,32 +281,37 @@ func (a *PostAdapter) ListPosts(ctx context.Context, page, pageSize int, sortBy
 	var posts []*entity.Post
 	for rows.Next() {
 		var post entity.Post
-		var username string
-		err := rows.Scan(&post.ID, &post.Header, &post.Content, &post.Image, &post.Price, &post.AuthorID, &username, &post.CreatedAt)
-		if err != nil {
+		dest := postDest(&post)
+		if search != "" {
+			dest = append(dest, &post.Rank, &post.Snippet)
+		}
+		if origin != nil {
+			dest = append(dest, &post.Distance)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			a.logger.WithError(err).Error("Failed to scan post row")
 			return nil, 0, fmt.Errorf("scan post: %w", err)
 		}
-		post.AuthorUsername = username
+		post.Snippet = highlightSnippet(post.Snippet)
 		posts = append(posts, &post)
 	}
 	if err := rows.Err(); err != nil {
 		a.logger.WithError(err).Error("Error iterating post rows")
 		return nil, 0, fmt.Errorf("iterate posts: %w", err)
 	}
+	rows.Close()
+
+	if err := a.attachImages(ctx, posts); err != nil {
+		return nil, 0, err
+	}
 
-	a.logger.WithFields(logrus.Fields{
-		"page":        page,
-		"page_size":   pageSize,
-		"total_posts": total,
-	}).Info("Posts listed from database")
 	return posts, total, nil
 }
 
 func (a *PostAdapter) GetByHeaderAndContent(ctx context.Context, header, content string) (*entity.Post, error) {
-	query, args, err := squirrel.Select("id", "header", "content", "image", "price", "author_id", "created_at").
+	query, args, err := squirrel.Select("id", "header", "content", "image", "price", "currency", "author_id", "created_at").
 		From("posts").
-		Where(squirrel.Eq{"header": header, "content": content}).
+		Where(squirrel.Eq{"header": header, "content": content, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -281,10 +319,10 @@ func (a *PostAdapter) GetByHeaderAndContent(ctx context.Context, header, content
 		return nil, fmt.Errorf("get post by header and content: %w", err)
 	}
 	var post entity.Post
-	err = a.db.QueryRow(ctx, query, args...).Scan(&post.ID, &post.Header, &post.Content, &post.Image, &post.Price, &post.AuthorID, &post.CreatedAt)
+	err = a.db.QueryRow(ctx, query, args...).Scan(&post.ID, &post.Header, &post.Content, &post.Image, &post.Price.Amount, &post.Price.Currency, &post.AuthorID, &post.CreatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, sql.ErrNoRows
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("post not found")
 		}
 		a.logger.WithError(err).Error("Failed to get post by header and content")
 		return nil, fmt.Errorf("get post by header and content: %w", err)
@@ -292,14 +330,15 @@ func (a *PostAdapter) GetByHeaderAndContent(ctx context.Context, header, content
 	return &post, nil
 }
 
-func (a *PostAdapter) Update(ctx context.Context, post *entity.Post) error {
+func (a *PostAdapter) Update(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error {
 	// Проверка на уникальность с исключением текущего поста
 	queryCheck, argsCheck, err := squirrel.Select("id").
 		From("posts").
 		Where(squirrel.Eq{
-			"header":    post.Header,
-			"content":   post.Content,
-			"author_id": post.AuthorID,
+			"header":     post.Header,
+			"content":    post.Content,
+			"author_id":  post.AuthorID,
+			"deleted_at": nil,
 		}).
 		Where(squirrel.NotEq{"id": post.ID}).
 		PlaceholderFormat(squirrel.Dollar).
@@ -315,44 +354,99 @@ func (a *PostAdapter) Update(ctx context.Context, post *entity.Post) error {
 			"header":    post.Header,
 			"author_id": post.AuthorID,
 		}).Warn("Post with the same header, content, and author already exists")
-		return fmt.Errorf("post with the same header, content, and author already exists")
+		return apperror.Conflict("post with the same header, content, and author already exists")
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, sql.ErrNoRows) {
 		a.logger.WithError(err).Error("Failed to check post existence")
 		return fmt.Errorf("check post existence: %w", err)
 	}
 
-	// Обновление поста
+	// Обновление поста, только если его никто не изменил после чтения
 	query, args, err := squirrel.Update("posts").
 		Set("header", post.Header).
 		Set("content", post.Content).
 		Set("image", post.Image).
-		Set("price", post.Price).
-		Where(squirrel.Eq{"id": post.ID, "author_id": post.AuthorID}).
+		Set("price", post.Price.Amount).
+		Set("currency", post.Price.Currency).
+		Set("category_id", post.CategoryID).
+		Set("tags", postTags(post)).
+		Set("attributes", postAttributes(post)).
+		Set("lat", post.Lat).
+		Set("lng", post.Lng).
+		Set("city", post.City).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": post.ID, "author_id": post.AuthorID, "version": post.Version, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to build update post query")
 		return fmt.Errorf("update post query: %w", err)
 	}
-	result, err := a.db.Exec(ctx, query, args...)
+	err = pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return apperror.PreconditionFailed("post has been modified by another request")
+		}
+		if err := a.saveImages(ctx, tx, post); err != nil {
+			return err
+		}
+		return a.saveRevision(ctx, tx, revision)
+	})
 	if err != nil {
+		if errors.Is(err, apperror.ErrPreconditionFailed) {
+			return err
+		}
+		if pgerror.IsForeignKeyViolation(err) {
+			return foreignKeyViolation(err)
+		}
 		a.logger.WithError(err).Error("Failed to update post")
 		return fmt.Errorf("update post: %w", err)
 	}
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("post not found or not owned by user")
-	}
+	post.Version++
 	a.logger.WithFields(logrus.Fields{
 		"post_id": post.ID,
 	}).Info("Post updated in database")
 	return nil
 }
 
+// UpdateStatus меняет статус, только если пост всё ещё в статусе from,
+// чтобы два одновременных перехода не перезаписали друг друга. Версия поста
+// при этом тоже увеличивается.
+func (a *PostAdapter) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.PostStatus) error {
+	query, args, err := squirrel.Update("posts").
+		Set("status", to).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": id, "status": from, "deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build update post status query")
+		return fmt.Errorf("update post status query: %w", err)
+	}
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update post status")
+		return fmt.Errorf("update post status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.Conflict("post status has been changed by another request")
+	}
+	a.logger.WithFields(logrus.Fields{
+		"post_id": id,
+		"from":    from,
+		"to":      to,
+	}).Info("Post status updated in database")
+	return nil
+}
+
+// Delete помечает пост удалённым. Окончательно его удаляет PurgeDeleted.
 func (a *PostAdapter) Delete(ctx context.Context, id uuid.UUID) error {
-	query, args, err := squirrel.Delete("posts").
-		Where(squirrel.Eq{"id": id}).
+	query, args, err := squirrel.Update("posts").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -366,10 +460,212 @@ func (a *PostAdapter) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("post not found")
+		return apperror.NotFound("post not found")
 	}
 	a.logger.WithFields(logrus.Fields{
 		"post_id": id,
 	}).Info("Post deleted from database")
 	return nil
 }
+
+// GetDeletedByID возвращает удалённый пост, который ещё можно восстановить.
+func (a *PostAdapter) GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	query, args, err := squirrel.Select(postColumns...).
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where(squirrel.Eq{"p.id": id, "u.deleted_at": nil}).
+		Where(squirrel.NotEq{"p.deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get deleted post query")
+		return nil, fmt.Errorf("get deleted post query: %w", err)
+	}
+	var post entity.Post
+	err = a.db.QueryRow(ctx, query, args...).Scan(postDest(&post)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("deleted post not found")
+		}
+		a.logger.WithError(err).Error("Failed to get deleted post")
+		return nil, fmt.Errorf("get deleted post: %w", err)
+	}
+	if err := a.attachImages(ctx, []*entity.Post{&post}); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (a *PostAdapter) Restore(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Update("posts").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build restore post query")
+		return fmt.Errorf("restore post query: %w", err)
+	}
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to restore post")
+		return fmt.Errorf("restore post: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("deleted post not found")
+	}
+	a.logger.WithFields(logrus.Fields{
+		"post_id": id,
+	}).Info("Post restored in database")
+	return nil
+}
+
+// PurgeDeleted окончательно удаляет посты, удалённые раньше before.
+func (a *PostAdapter) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := squirrel.Delete("posts").
+		Where(squirrel.Lt{"deleted_at": before}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build purge posts query")
+		return 0, fmt.Errorf("purge posts query: %w", err)
+	}
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to purge deleted posts")
+		return 0, fmt.Errorf("purge posts: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// saveImages заменяет галерею поста внутри транзакции.
+func (a *PostAdapter) saveImages(ctx context.Context, tx pgx.Tx, post *entity.Post) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM post_images WHERE post_id = $1", post.ID); err != nil {
+		return err
+	}
+	if len(post.Images) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("post_images").
+		Columns("post_id", "image_id", "position", "is_cover").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, image := range post.Images {
+		insert = insert.Values(post.ID, image.ImageID, image.Position, image.IsCover)
+	}
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("save post images query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+// attachImages загружает галереи всех постов одним запросом.
+func (a *PostAdapter) attachImages(ctx context.Context, posts []*entity.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(posts))
+	byID := make(map[uuid.UUID]*entity.Post, len(posts))
+	for _, post := range posts {
+		post.Images = []entity.PostImage{}
+		ids = append(ids, post.ID)
+		byID[post.ID] = post
+	}
+
+	query, args, err := squirrel.Select("post_id", "image_id", "position", "is_cover").
+		From("post_images").
+		Where(squirrel.Eq{"post_id": ids}).
+		OrderBy("post_id", "position").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build post images query")
+		return fmt.Errorf("post images query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get post images")
+		return fmt.Errorf("get post images: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		var image entity.PostImage
+		if err := rows.Scan(&postID, &image.ImageID, &image.Position, &image.IsCover); err != nil {
+			a.logger.WithError(err).Error("Failed to scan post image row")
+			return fmt.Errorf("scan post image: %w", err)
+		}
+		image.URL = entity.ImageURL(image.ImageID)
+		byID[postID].Images = append(byID[postID].Images, image)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating post image rows")
+		return fmt.Errorf("iterate post images: %w", err)
+	}
+	rows.Close()
+
+	return a.attachVariants(ctx, posts)
+}
+
+// attachVariants заполняет srcset у изображений галерей одним запросом.
+func (a *PostAdapter) attachVariants(ctx context.Context, posts []*entity.Post) error {
+	var imageIDs []uuid.UUID
+	for _, post := range posts {
+		imageIDs = append(imageIDs, post.ImageIDs()...)
+	}
+	if len(imageIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := squirrel.Select("image_id", "width", "format").
+		From("image_variants").
+		Where(squirrel.Eq{"image_id": imageIDs}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build image variants query")
+		return fmt.Errorf("image variants query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get image variants")
+		return fmt.Errorf("get image variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := make(map[uuid.UUID][]*entity.ImageVariant)
+	for rows.Next() {
+		var variant entity.ImageVariant
+		if err := rows.Scan(&variant.ImageID, &variant.Width, &variant.Format); err != nil {
+			a.logger.WithError(err).Error("Failed to scan image variant row")
+			return fmt.Errorf("scan image variant: %w", err)
+		}
+		variants[variant.ImageID] = append(variants[variant.ImageID], &variant)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating image variant rows")
+		return fmt.Errorf("iterate image variants: %w", err)
+	}
+
+	for _, post := range posts {
+		for i := range post.Images {
+			post.Images[i].Srcset = entity.BuildSrcset(variants[post.Images[i].ImageID])
+		}
+	}
+	return nil
+}
+
+// foreignKeyViolation переводит нарушение внешнего ключа в ошибку поля запроса.
+func foreignKeyViolation(err error) error {
+	if pgerror.Constraint(err) == "post_images_image_id_fkey" {
+		return apperror.InvalidField("image_ids", "image not found")
+	}
+	return apperror.InvalidField("category_id", "category not found")
+}
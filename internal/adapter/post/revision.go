@@ -0,0 +1,115 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// revisionColumns — колонки ревизии вместе с именем редактора; порядок совпадает с revisionDest.
+var revisionColumns = []string{"r.id", "r.post_id", "r.revision", "r.editor_id", "COALESCE(u.username, '')", "r.header", "r.content", "COALESCE(r.image, '')", "r.price", "r.currency", "r.category_id", "r.tags", "r.attributes", "r.lat", "r.lng", "r.city", "r.image_ids", "r.created_at"}
+
+func revisionDest(revision *entity.PostRevision) []interface{} {
+	return []interface{}{&revision.ID, &revision.PostID, &revision.Revision, &revision.EditorID, &revision.EditorUsername, &revision.Header, &revision.Content, &revision.Image, &revision.Price.Amount, &revision.Price.Currency, &revision.CategoryID, &revision.Tags, &revision.Attributes, &revision.Lat, &revision.Lng, &revision.City, &revision.ImageIDs, &revision.CreatedAt}
+}
+
+// saveRevision сохраняет ревизию в транзакции изменения поста и назначает ей
+// следующий номер.
+func (a *PostAdapter) saveRevision(ctx context.Context, tx pgx.Tx, revision *entity.PostRevision) error {
+	query, args, err := squirrel.Insert("post_revisions").
+		Columns("id", "post_id", "revision", "editor_id", "header", "content", "image", "price", "currency", "category_id", "tags", "attributes", "lat", "lng", "city", "image_ids", "created_at").
+		Values(
+			revision.ID,
+			revision.PostID,
+			squirrel.Expr("(SELECT COALESCE(MAX(revision), 0) + 1 FROM post_revisions WHERE post_id = ?)", revision.PostID),
+			revision.EditorID,
+			revision.Header,
+			revision.Content,
+			revision.Image,
+			revision.Price.Amount,
+			revision.Price.Currency,
+			revision.CategoryID,
+			revision.Tags,
+			revision.Attributes,
+			revision.Lat,
+			revision.Lng,
+			revision.City,
+			revision.ImageIDs,
+			revision.CreatedAt,
+		).
+		Suffix("RETURNING revision").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("save post revision query: %w", err)
+	}
+	return tx.QueryRow(ctx, query, args...).Scan(&revision.Revision)
+}
+
+func (a *PostAdapter) ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error) {
+	query, args, err := squirrel.Select(revisionColumns...).
+		From("post_revisions r").
+		LeftJoin("users u ON r.editor_id = u.id").
+		Where(squirrel.Eq{"r.post_id": postID}).
+		OrderBy("r.revision DESC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list post revisions query")
+		return nil, fmt.Errorf("list post revisions query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list post revisions")
+		return nil, fmt.Errorf("list post revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*entity.PostRevision, 0)
+	for rows.Next() {
+		var revision entity.PostRevision
+		if err := rows.Scan(revisionDest(&revision)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan post revision row")
+			return nil, fmt.Errorf("scan post revision: %w", err)
+		}
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating post revision rows")
+		return nil, fmt.Errorf("iterate post revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (a *PostAdapter) GetRevision(ctx context.Context, postID uuid.UUID, number int) (*entity.PostRevision, error) {
+	query, args, err := squirrel.Select(revisionColumns...).
+		From("post_revisions r").
+		LeftJoin("users u ON r.editor_id = u.id").
+		Where(squirrel.Eq{"r.post_id": postID, "r.revision": number}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get post revision query")
+		return nil, fmt.Errorf("get post revision query: %w", err)
+	}
+
+	var revision entity.PostRevision
+	err = a.db.QueryRow(ctx, query, args...).Scan(revisionDest(&revision)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("revision %d not found", number)
+		}
+		a.logger.WithError(err).Error("Failed to get post revision")
+		return nil, fmt.Errorf("get post revision: %w", err)
+	}
+	return &revision, nil
+}
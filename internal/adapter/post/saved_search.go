@@ -0,0 +1,278 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// CheckFilter проверяет фильтры сохранённого поиска так же, как их проверил
+// бы список постов.
+func (a *PostAdapter) CheckFilter(filter map[string]string) error {
+	_, err := postFilters(filter)
+	return err
+}
+
+// ListUnmatched возвращает опубликованные посты, которые ещё не сверены с
+// сохранёнными поисками, начиная с самых старых. Черновики ждут публикации.
+func (a *PostAdapter) ListUnmatched(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	query, args, err := squirrel.Select("p.id").
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where("p.searches_matched_at IS NULL").
+		Where(squirrel.Eq{"p.status": entity.PostPublished}).
+		Where(notDeleted).
+		OrderBy("p.created_at").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build unmatched posts query")
+		return nil, fmt.Errorf("unmatched posts query: %w", err)
+	}
+
+	return a.queryIDs(ctx, query, args...)
+}
+
+// MatchFilter возвращает посты из postIDs, которые подходят под фильтры
+// сохранённого поиска.
+func (a *PostAdapter) MatchFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) ([]uuid.UUID, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	conditions, err := postFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := squirrel.Select("p.id").
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where("p.id = ANY(?)", postIDs).
+		Where(squirrel.Eq{"p.status": entity.PostPublished}).
+		Where(notDeleted).
+		Where(conditions).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build match filter query")
+		return nil, fmt.Errorf("match filter query: %w", err)
+	}
+
+	return a.queryIDs(ctx, query, args...)
+}
+
+// PostAuthors возвращает авторов постов postIDs: свой пост владельцу поиска
+// в совпадения не попадает.
+func (a *PostAdapter) PostAuthors(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	query, args, err := squirrel.Select("id", "author_id").
+		From("posts").
+		Where("id = ANY(?)", postIDs).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build post authors query")
+		return nil, fmt.Errorf("post authors query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get post authors")
+		return nil, fmt.Errorf("get post authors: %w", err)
+	}
+	defer rows.Close()
+
+	authors := make(map[uuid.UUID]uuid.UUID, len(postIDs))
+	for rows.Next() {
+		var postID, authorID uuid.UUID
+		if err := rows.Scan(&postID, &authorID); err != nil {
+			a.logger.WithError(err).Error("Failed to scan post author")
+			return nil, fmt.Errorf("scan post author: %w", err)
+		}
+		authors[postID] = authorID
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating post authors")
+		return nil, fmt.Errorf("iterate post authors: %w", err)
+	}
+	return authors, nil
+}
+
+// MatchFeedFilter возвращает посты из postIDs, которые подходят под фильтры
+// ленты новых постов, и расстояние до каждого, если в фильтре задана точка
+// lat и lng.
+func (a *PostAdapter) MatchFeedFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) (map[uuid.UUID]*float64, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	conditions, err := postFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := filterOrigin(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := squirrel.Select("p.id").
+		From("posts p").
+		Join("users u ON p.author_id = u.id").
+		Where("p.id = ANY(?)", postIDs).
+		Where(squirrel.Eq{"p.status": entity.PostPublished}).
+		Where(notDeleted).
+		Where(conditions)
+	if origin != nil {
+		builder = builder.Column(distanceColumn(*origin))
+	}
+	query, args, err := builder.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build match feed filter query")
+		return nil, fmt.Errorf("match feed filter query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to match feed filter")
+		return nil, fmt.Errorf("match feed filter: %w", err)
+	}
+	defer rows.Close()
+
+	matched := make(map[uuid.UUID]*float64, len(postIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var distance *float64
+		dest := []interface{}{&id}
+		if origin != nil {
+			dest = append(dest, &distance)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan matched post")
+			return nil, fmt.Errorf("scan matched post: %w", err)
+		}
+		matched[id] = distance
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating matched posts")
+		return nil, fmt.Errorf("iterate matched posts: %w", err)
+	}
+	return matched, nil
+}
+
+func (a *PostAdapter) MarkSearchesMatched(ctx context.Context, postIDs []uuid.UUID) error {
+	query, args, err := squirrel.Update("posts").
+		Set("searches_matched_at", time.Now()).
+		Where("id = ANY(?)", postIDs).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark searches matched query")
+		return fmt.Errorf("mark searches matched query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to mark searches matched")
+		return fmt.Errorf("mark searches matched: %w", err)
+	}
+	return nil
+}
+
+// ListSavedSearchMatches выбирает страницу непросмотренных совпадений
+// сохранённого поиска, начиная с последних. Посты, которые с тех пор удалили
+// или скрыли, не показываются.
+func (a *PostAdapter) ListSavedSearchMatches(ctx context.Context, searchID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error) {
+	conditions := squirrel.And{
+		squirrel.Eq{"m.saved_search_id": searchID, "m.seen_at": nil},
+		squirrel.Eq{"p.status": entity.PublicPostStatuses()},
+		notDeleted,
+	}
+
+	countQuery, countArgs, err := squirrel.Select("COUNT(*)").
+		From("saved_search_matches m").
+		Join("posts p ON m.post_id = p.id").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count query for saved search matches")
+		return nil, 0, fmt.Errorf("count saved search matches query: %w", err)
+	}
+	var total int
+	if err := a.db.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		a.logger.WithError(err).Error("Failed to count saved search matches")
+		return nil, 0, fmt.Errorf("count saved search matches: %w", err)
+	}
+
+	query, args, err := squirrel.Select(postColumns...).
+		From("saved_search_matches m").
+		Join("posts p ON m.post_id = p.id").
+		Join("users u ON p.author_id = u.id").
+		Where(conditions).
+		OrderBy("m.created_at DESC", "p.id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((page - 1) * pageSize)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list saved search matches query")
+		return nil, 0, fmt.Errorf("list saved search matches query: %w", err)
+	}
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list saved search matches")
+		return nil, 0, fmt.Errorf("list saved search matches: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		if err := rows.Scan(postDest(&post)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan saved search match row")
+			return nil, 0, fmt.Errorf("scan saved search match: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating saved search match rows")
+		return nil, 0, fmt.Errorf("iterate saved search matches: %w", err)
+	}
+	rows.Close()
+
+	if err := a.attachImages(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
+
+func (a *PostAdapter) queryIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to query IDs")
+		return nil, fmt.Errorf("query IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			a.logger.WithError(err).Error("Failed to scan ID")
+			return nil, fmt.Errorf("scan ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating IDs")
+		return nil, fmt.Errorf("iterate IDs: %w", err)
+	}
+	return ids, nil
+}
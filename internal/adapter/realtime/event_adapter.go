@@ -0,0 +1,11 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+)
+
+type EventAdapterInterface interface {
+	Publish(ctx context.Context, event *entity.Event) error
+	Listen(ctx context.Context, handle func(event *entity.Event)) error
+}
@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"marketplace/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// eventChannel — канал LISTEN/NOTIFY, через который события расходятся по
+	// всем экземплярам сервера.
+	eventChannel = "marketplace_events"
+	// maxEventPayload — предел размера сообщения NOTIFY в PostgreSQL. События
+	// несут только ID, поэтому укладываются в него с запасом.
+	maxEventPayload = 8000
+)
+
+type EventAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewEventAdapter(db *pgxpool.Pool, logger *logrus.Logger) *EventAdapter {
+	return &EventAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Publish рассылает событие всем экземплярам сервера, слушающим канал.
+func (a *EventAdapter) Publish(ctx context.Context, event *entity.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	if len(payload) >= maxEventPayload {
+		return fmt.Errorf("event payload of %d bytes exceeds the NOTIFY limit", len(payload))
+	}
+
+	query, args, err := squirrel.Select().
+		Column("pg_notify(?, ?)", eventChannel, string(payload)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build publish event query")
+		return fmt.Errorf("publish event query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to publish event")
+		return fmt.Errorf("publish event: %w", err)
+	}
+	return nil
+}
+
+// Listen занимает отдельное соединение с базой и передаёт handle события
+// канала, пока не будет отменён контекст или не оборвётся соединение.
+func (a *EventAdapter) Listen(ctx context.Context, handle func(event *entity.Event)) error {
+	conn, err := a.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listen connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return fmt.Errorf("listen events: %w", err)
+	}
+	defer func() {
+		// После отмены контекста соединение может быть закрыто, тогда пул
+		// его просто выбросит.
+		if !conn.Conn().IsClosed() {
+			conn.Exec(context.Background(), "UNLISTEN "+eventChannel)
+		}
+	}()
+	a.logger.WithField("channel", eventChannel).Info("Listening for events in database")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for event: %w", err)
+		}
+
+		var event entity.Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			a.logger.WithError(err).Warn("Skipping malformed event")
+			continue
+		}
+		handle(&event)
+	}
+}
@@ -0,0 +1,20 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SavedSearchAdapterInterface interface {
+	Create(ctx context.Context, search *entity.SavedSearch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavedSearch, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	ListAfter(ctx context.Context, after *entity.SavedSearch, limit int) ([]*entity.SavedSearch, error)
+	Update(ctx context.Context, search *entity.SavedSearch) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddMatches(ctx context.Context, searchID uuid.UUID, postIDs []uuid.UUID) (int64, error)
+	MarkSeen(ctx context.Context, searchID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,263 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// unseenCount — число непросмотренных совпадений сохранённого поиска s.
+const unseenCount = "(SELECT COUNT(*) FROM saved_search_matches m WHERE m.saved_search_id = s.id AND m.seen_at IS NULL)"
+
+var savedSearchColumns = []string{"s.id", "s.user_id", "s.name", "s.filter", "s.created_at", unseenCount}
+
+func savedSearchDest(search *entity.SavedSearch) []interface{} {
+	return []interface{}{&search.ID, &search.UserID, &search.Name, &search.Filter, &search.CreatedAt, &search.UnseenCount}
+}
+
+type SavedSearchAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewSavedSearchAdapter(db *pgxpool.Pool, logger *logrus.Logger) *SavedSearchAdapter {
+	return &SavedSearchAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *SavedSearchAdapter) Create(ctx context.Context, search *entity.SavedSearch) error {
+	query, args, err := squirrel.Insert("saved_searches").
+		Columns("id", "user_id", "name", "filter", "created_at").
+		Values(search.ID, search.UserID, search.Name, search.Filter, search.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create saved search query")
+		return fmt.Errorf("create saved search query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to create saved search")
+		return fmt.Errorf("create saved search: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+		"user_id":         search.UserID,
+	}).Info("Saved search created in database")
+	return nil
+}
+
+func (a *SavedSearchAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error) {
+	query, args, err := squirrel.Select(savedSearchColumns...).
+		From("saved_searches s").
+		Where(squirrel.Eq{"s.id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get saved search by ID query")
+		return nil, fmt.Errorf("get saved search by ID query: %w", err)
+	}
+
+	var search entity.SavedSearch
+	if err := a.db.QueryRow(ctx, query, args...).Scan(savedSearchDest(&search)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("saved search not found")
+		}
+		a.logger.WithError(err).Error("Failed to get saved search by ID")
+		return nil, fmt.Errorf("get saved search by id: %w", err)
+	}
+	return &search, nil
+}
+
+func (a *SavedSearchAdapter) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavedSearch, error) {
+	query, args, err := squirrel.Select(savedSearchColumns...).
+		From("saved_searches s").
+		Where(squirrel.Eq{"s.user_id": userID}).
+		OrderBy("s.created_at DESC", "s.id DESC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list saved searches query")
+		return nil, fmt.Errorf("list saved searches query: %w", err)
+	}
+
+	return a.query(ctx, query, args...)
+}
+
+func (a *SavedSearchAdapter) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	query, args, err := squirrel.Select("COUNT(*)").
+		From("saved_searches").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build count saved searches query")
+		return 0, fmt.Errorf("count saved searches query: %w", err)
+	}
+
+	var count int
+	if err := a.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		a.logger.WithError(err).Error("Failed to count saved searches")
+		return 0, fmt.Errorf("count saved searches: %w", err)
+	}
+	return count, nil
+}
+
+// ListAfter возвращает до limit сохранённых поисков всех пользователей,
+// следующих за after, без счётчиков совпадений: их сверяет с новыми постами
+// фоновый подбор. Поиски упорядочены по фильтру, поэтому одинаковые фильтры
+// идут подряд.
+func (a *SavedSearchAdapter) ListAfter(ctx context.Context, after *entity.SavedSearch, limit int) ([]*entity.SavedSearch, error) {
+	builder := squirrel.Select("s.id", "s.user_id", "s.name", "s.filter", "s.created_at", "0").
+		From("saved_searches s").
+		Join("users u ON s.user_id = u.id").
+		Where(squirrel.Eq{"u.deleted_at": nil}).
+		OrderBy("s.filter", "s.id").
+		Limit(uint64(limit))
+	if after != nil {
+		builder = builder.Where("(s.filter, s.id) > (?::jsonb, ?)", after.Filter, after.ID)
+	}
+
+	query, args, err := builder.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build list saved searches after query")
+		return nil, fmt.Errorf("list saved searches after query: %w", err)
+	}
+
+	return a.query(ctx, query, args...)
+}
+
+func (a *SavedSearchAdapter) Update(ctx context.Context, search *entity.SavedSearch) error {
+	query, args, err := squirrel.Update("saved_searches").
+		Set("name", search.Name).
+		Set("filter", search.Filter).
+		Where(squirrel.Eq{"id": search.ID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build update saved search query")
+		return fmt.Errorf("update saved search query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update saved search")
+		return fmt.Errorf("update saved search: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("saved search not found")
+	}
+
+	a.logger.WithField("saved_search_id", search.ID).Info("Saved search updated in database")
+	return nil
+}
+
+func (a *SavedSearchAdapter) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Delete("saved_searches").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build delete saved search query")
+		return fmt.Errorf("delete saved search query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to delete saved search")
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("saved search not found")
+	}
+
+	a.logger.WithField("saved_search_id", id).Info("Saved search deleted from database")
+	return nil
+}
+
+// AddMatches записывает совпадения поиска с постами и возвращает число новых.
+// Собственные посты владельца поиска и уже записанные совпадения пропускаются.
+func (a *SavedSearchAdapter) AddMatches(ctx context.Context, searchID uuid.UUID, postIDs []uuid.UUID) (int64, error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := squirrel.Insert("saved_search_matches").
+		Columns("saved_search_id", "post_id", "created_at").
+		Select(squirrel.Select("s.id", "p.id").
+			Column("?::TIMESTAMPTZ", time.Now()).
+			From("saved_searches s").
+			Join("posts p ON p.id = ANY(?)", postIDs).
+			Where(squirrel.Eq{"s.id": searchID}).
+			Where("p.author_id <> s.user_id")).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build add saved search matches query")
+		return 0, fmt.Errorf("add saved search matches query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to add saved search matches")
+		return 0, fmt.Errorf("add saved search matches: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// MarkSeen отмечает все совпадения поиска просмотренными и возвращает их число.
+func (a *SavedSearchAdapter) MarkSeen(ctx context.Context, searchID uuid.UUID) (int64, error) {
+	query, args, err := squirrel.Update("saved_search_matches").
+		Set("seen_at", time.Now()).
+		Where(squirrel.Eq{"saved_search_id": searchID, "seen_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark saved search matches seen query")
+		return 0, fmt.Errorf("mark saved search matches seen query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to mark saved search matches seen")
+		return 0, fmt.Errorf("mark saved search matches seen: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (a *SavedSearchAdapter) query(ctx context.Context, query string, args ...interface{}) ([]*entity.SavedSearch, error) {
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list saved searches")
+		return nil, fmt.Errorf("list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []*entity.SavedSearch
+	for rows.Next() {
+		var search entity.SavedSearch
+		if err := rows.Scan(savedSearchDest(&search)...); err != nil {
+			a.logger.WithError(err).Error("Failed to scan saved search row")
+			return nil, fmt.Errorf("scan saved search: %w", err)
+		}
+		searches = append(searches, &search)
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.WithError(err).Error("Error iterating saved search rows")
+		return nil, fmt.Errorf("iterate saved searches: %w", err)
+	}
+	return searches, nil
+}
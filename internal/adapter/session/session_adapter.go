@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SessionAdapterInterface interface {
+	CreateSession(ctx context.Context, session *entity.Session) error
+	GetSession(ctx context.Context, id uuid.UUID) (*entity.Session, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,158 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionAdapter struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewSessionAdapter(db *pgxpool.Pool, logger *logrus.Logger) *SessionAdapter {
+	return &SessionAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *SessionAdapter) CreateSession(ctx context.Context, session *entity.Session) error {
+	query, args, err := squirrel.Insert("sessions").
+		Columns("id", "user_id", "created_at").
+		Values(session.ID, session.UserID, session.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create session query")
+		return fmt.Errorf("create session query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to create session")
+		return fmt.Errorf("create session: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"user_id":    session.UserID,
+	}).Info("Session created in database")
+	return nil
+}
+
+func (a *SessionAdapter) GetSession(ctx context.Context, id uuid.UUID) (*entity.Session, error) {
+	query, args, err := squirrel.Select("id", "user_id", "created_at", "revoked_at").
+		From("sessions").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get session query")
+		return nil, fmt.Errorf("get session query: %w", err)
+	}
+
+	var session entity.Session
+	err = a.db.QueryRow(ctx, query, args...).Scan(&session.ID, &session.UserID, &session.CreatedAt, &session.RevokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("session not found")
+		}
+		a.logger.WithError(err).Error("Failed to get session")
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return &session, nil
+}
+
+func (a *SessionAdapter) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Update("sessions").
+		Set("revoked_at", time.Now()).
+		Where(squirrel.Eq{"id": id, "revoked_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build revoke session query")
+		return fmt.Errorf("revoke session query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to revoke session")
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"session_id": id,
+	}).Info("Session revoked in database")
+	return nil
+}
+
+func (a *SessionAdapter) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query, args, err := squirrel.Insert("refresh_tokens").
+		Columns("id", "session_id", "token_hash", "expires_at", "created_at").
+		Values(token.ID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build create refresh token query")
+		return fmt.Errorf("create refresh token query: %w", err)
+	}
+
+	if _, err := a.db.Exec(ctx, query, args...); err != nil {
+		a.logger.WithError(err).Error("Failed to create refresh token")
+		return fmt.Errorf("create refresh token: %w", err)
+	}
+	return nil
+}
+
+func (a *SessionAdapter) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	query, args, err := squirrel.Select("id", "session_id", "token_hash", "expires_at", "used_at", "created_at").
+		From("refresh_tokens").
+		Where(squirrel.Eq{"token_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get refresh token query")
+		return nil, fmt.Errorf("get refresh token query: %w", err)
+	}
+
+	var token entity.RefreshToken
+	err = a.db.QueryRow(ctx, query, args...).Scan(&token.ID, &token.SessionID, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("refresh token not found")
+		}
+		a.logger.WithError(err).Error("Failed to get refresh token")
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed помечает токен использованным только если он ещё не был
+// использован. false означает, что токен уже кто-то предъявил.
+func (a *SessionAdapter) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("used_at", time.Now()).
+		Where(squirrel.Eq{"id": id, "used_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mark refresh token used query")
+		return false, fmt.Errorf("mark refresh token used query: %w", err)
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to mark refresh token used")
+		return false, fmt.Errorf("mark refresh token used: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
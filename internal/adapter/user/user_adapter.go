@@ -3,6 +3,7 @@ package adapter
 import (
 	"context"
 	"marketplace/internal/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,4 +14,7 @@ type UserAdapterInterface interface {
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByUsername(ctx context.Context, username string) (*entity.User, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
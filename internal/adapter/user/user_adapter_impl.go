@@ -3,11 +3,15 @@ package adapter
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+	"marketplace/internal/adapter/pgerror"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 )
@@ -27,8 +31,8 @@ func NewUserAdaper(db *pgxpool.Pool, logger *logrus.Logger) *UserAdapter {
 func (a *UserAdapter) Create(ctx context.Context, user *entity.User) error {
 	query, args, err := squirrel.
 		Insert("users").
-		Columns("id", "username", "hashed_password", "created_at").
-		Values(user.ID, user.Username, user.HashedPassword, user.CreatedAt).
+		Columns("id", "username", "hashed_password", "role", "created_at").
+		Values(user.ID, user.Username, user.HashedPassword, user.Role, user.CreatedAt).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
@@ -39,6 +43,9 @@ func (a *UserAdapter) Create(ctx context.Context, user *entity.User) error {
 
 	_, err = a.db.Exec(ctx, query, args...)
 	if err != nil {
+		if pgerror.IsUniqueViolation(err) {
+			return apperror.Conflict("username already exists")
+		}
 		a.logger.WithError(err).Error("Failed to create user")
 		return err
 	}
@@ -51,9 +58,9 @@ func (a *UserAdapter) Create(ctx context.Context, user *entity.User) error {
 }
 
 func (a *UserAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
-	query, args, err := squirrel.Select("id", "username", "hashed_password", "created_at").
+	query, args, err := squirrel.Select("id", "username", "hashed_password", "role", "created_at").
 		From("users").
-		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"id": id, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -62,10 +69,10 @@ func (a *UserAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.User,
 	}
 
 	var user entity.User
-	err = a.db.QueryRow(ctx, query, args...).Scan(&user.ID, &user.Username, &user.HashedPassword, &user.CreatedAt)
+	err = a.db.QueryRow(ctx, query, args...).Scan(&user.ID, &user.Username, &user.HashedPassword, &user.Role, &user.CreatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
 		}
 		a.logger.WithError(err).Error("Failed to get user by ID")
 		return nil, err
@@ -75,9 +82,9 @@ func (a *UserAdapter) GetByID(ctx context.Context, id uuid.UUID) (*entity.User,
 }
 
 func (a *UserAdapter) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
-	query, args, err := squirrel.Select("id", "username", "hashed_password", "created_at").
+	query, args, err := squirrel.Select("id", "username", "hashed_password", "role", "created_at").
 		From("users").
-		Where(squirrel.Eq{"username": username}).
+		Where(squirrel.Eq{"username": username, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -86,10 +93,10 @@ func (a *UserAdapter) GetByUsername(ctx context.Context, username string) (*enti
 	}
 
 	var user entity.User
-	err = a.db.QueryRow(ctx, query, args...).Scan(&user.ID, &user.Username, &user.HashedPassword, &user.CreatedAt)
+	err = a.db.QueryRow(ctx, query, args...).Scan(&user.ID, &user.Username, &user.HashedPassword, &user.Role, &user.CreatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
 		}
 		a.logger.WithError(err).Error("Failed to get user by username")
 		return nil, err
@@ -102,7 +109,8 @@ func (a *UserAdapter) Update(ctx context.Context, user *entity.User) error {
 	query, args, err := squirrel.Update("users").
 		Set("username", user.Username).
 		Set("hashed_password", user.HashedPassword).
-		Where(squirrel.Eq{"id": user.ID}).
+		Set("role", user.Role).
+		Where(squirrel.Eq{"id": user.ID, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -112,13 +120,16 @@ func (a *UserAdapter) Update(ctx context.Context, user *entity.User) error {
 
 	result, err := a.db.Exec(ctx, query, args...)
 	if err != nil {
+		if pgerror.IsUniqueViolation(err) {
+			return apperror.Conflict("username already exists")
+		}
 		a.logger.WithError(err).Error("Failed to update user")
 		return err
 	}
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return apperror.NotFound("user not found")
 	}
 
 	a.logger.WithFields(logrus.Fields{
@@ -127,9 +138,12 @@ func (a *UserAdapter) Update(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+// Delete помечает пользователя удалённым и отзывает все его сессии.
+// Окончательно пользователя и его посты удаляет PurgeDeleted.
 func (a *UserAdapter) Delete(ctx context.Context, id uuid.UUID) error {
-	query, args, err := squirrel.Delete("users").
-		Where(squirrel.Eq{"id": id}).
+	query, args, err := squirrel.Update("users").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id, "deleted_at": nil}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -137,19 +151,102 @@ func (a *UserAdapter) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	result, err := a.db.Exec(ctx, query, args...)
+	err = pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return apperror.NotFound("user not found")
+		}
+		_, err = tx.Exec(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", id)
+		return err
+	})
 	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
 		a.logger.WithError(err).Error("Failed to delete user")
 		return err
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+	a.logger.WithFields(logrus.Fields{
+		"user_id": id,
+	}).Info("User deleted from database")
+	return nil
+}
+
+// GetDeletedByUsername ищет удалённого пользователя, которого ещё можно восстановить.
+func (a *UserAdapter) GetDeletedByUsername(ctx context.Context, username string) (*entity.User, error) {
+	query, args, err := squirrel.Select("id", "username", "hashed_password", "role", "created_at").
+		From("users").
+		Where(squirrel.Eq{"username": username}).
+		Where(squirrel.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build get deleted user query")
+		return nil, err
+	}
+
+	var user entity.User
+	err = a.db.QueryRow(ctx, query, args...).Scan(&user.ID, &user.Username, &user.HashedPassword, &user.Role, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("deleted user not found")
+		}
+		a.logger.WithError(err).Error("Failed to get deleted user")
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *UserAdapter) Restore(ctx context.Context, id uuid.UUID) error {
+	query, args, err := squirrel.Update("users").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build restore user query")
+		return err
+	}
+
+	result, err := a.db.Exec(ctx, query, args...)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to restore user")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.NotFound("deleted user not found")
 	}
 
 	a.logger.WithFields(logrus.Fields{
 		"user_id": id,
-	}).Info("User deleted from database")
+	}).Info("User restored in database")
 	return nil
 }
+
+// PurgeDeleted окончательно удаляет пользователей, удалённых раньше before,
+// вместе со всеми их постами.
+func (a *UserAdapter) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := pgx.BeginFunc(ctx, a.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM posts WHERE author_id IN (SELECT id FROM users WHERE deleted_at < $1)", before); err != nil {
+			return err
+		}
+		result, err := tx.Exec(ctx, "DELETE FROM users WHERE deleted_at < $1", before)
+		if err != nil {
+			return err
+		}
+		purged = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to purge deleted users")
+		return 0, err
+	}
+	return purged, nil
+}
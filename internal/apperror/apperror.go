@@ -0,0 +1,95 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Виды доменных ошибок. Проверяются через errors.Is и переводятся в HTTP-статусы
+// в httperror.Middleware.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrTooLarge     = errors.New("too large")
+	// ErrPreconditionFailed — версия ресурса не совпала с ожидаемой клиентом.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired — запрос на изменение пришёл без ожидаемой версии.
+	ErrPreconditionRequired = errors.New("precondition required")
+	// ErrUnsupportedMediaType — тело запроса пришло в неподдерживаемом формате.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrUnavailable — сервер временно не может принять запрос, например
+	// исчерпан лимит соединений.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error — доменная ошибка с сообщением, которое можно показать клиенту.
+type Error struct {
+	Kind       error
+	Message    string
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return newError(ErrNotFound, format, args...)
+}
+
+func Conflict(format string, args ...interface{}) error {
+	return newError(ErrConflict, format, args...)
+}
+
+func Forbidden(format string, args ...interface{}) error {
+	return newError(ErrForbidden, format, args...)
+}
+
+func Validation(format string, args ...interface{}) error {
+	return newError(ErrValidation, format, args...)
+}
+
+func Unauthorized(format string, args ...interface{}) error {
+	return newError(ErrUnauthorized, format, args...)
+}
+
+func TooLarge(format string, args ...interface{}) error {
+	return newError(ErrTooLarge, format, args...)
+}
+
+func PreconditionFailed(format string, args ...interface{}) error {
+	return newError(ErrPreconditionFailed, format, args...)
+}
+
+func PreconditionRequired(format string, args ...interface{}) error {
+	return newError(ErrPreconditionRequired, format, args...)
+}
+
+func UnsupportedMediaType(format string, args ...interface{}) error {
+	return newError(ErrUnsupportedMediaType, format, args...)
+}
+
+func Unavailable(format string, args ...interface{}) error {
+	return newError(ErrUnavailable, format, args...)
+}
+
+// Message возвращает сообщение доменной ошибки без контекста, добавленного
+// при оборачивании. Для остальных ошибок возвращает false.
+func Message(err error) (string, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Message, true
+	}
+	return "", false
+}
@@ -0,0 +1,76 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Violation описывает ошибку в одном поле запроса.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Violations накапливает ошибки валидации, чтобы вернуть клиенту все сразу,
+// а не только первую.
+type Violations []Violation
+
+func (v *Violations) Add(field, format string, args ...interface{}) {
+	*v = append(*v, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает ошибку вида ErrValidation со всеми нарушениями или nil,
+// если нарушений нет.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	parts := make([]string, 0, len(v))
+	for _, violation := range v {
+		parts = append(parts, violation.Field+": "+violation.Message)
+	}
+	return &Error{
+		Kind:       ErrValidation,
+		Message:    strings.Join(parts, "; "),
+		Violations: v,
+	}
+}
+
+// InvalidField — ошибка валидации одного поля.
+func InvalidField(field, format string, args ...interface{}) error {
+	var v Violations
+	v.Add(field, format, args...)
+	return v.Err()
+}
+
+// Merge объединяет нарушения из нескольких ошибок валидации в одну.
+// Если среди ошибок есть ошибка другого вида, она возвращается как есть.
+func Merge(errs ...error) error {
+	var merged Violations
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrValidation) {
+			return err
+		}
+		if violations := ViolationsOf(err); len(violations) > 0 {
+			merged = append(merged, violations...)
+		} else {
+			message, _ := Message(err)
+			merged = append(merged, Violation{Message: message})
+		}
+	}
+	return merged.Err()
+}
+
+// ViolationsOf возвращает нарушения по полям из доменной ошибки.
+func ViolationsOf(err error) []Violation {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Violations
+	}
+	return nil
+}
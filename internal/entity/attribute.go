@@ -0,0 +1,230 @@
+package entity
+
+import (
+	"encoding/json"
+	"marketplace/internal/apperror"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type AttributeType string
+
+const (
+	AttributeEnum   AttributeType = "enum"
+	AttributeNumber AttributeType = "number"
+	AttributeString AttributeType = "string"
+)
+
+const (
+	maxAttributes         = 30
+	maxAttributeName      = 50
+	maxAttributeLabel     = 100
+	maxAttributeOptions   = 100
+	maxAttributeOption    = 100
+	maxAttributeStringLen = 200
+)
+
+var validAttributeName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Attribute — характеристика товара в схеме категории. Options задаются
+// только у перечислений, Min и Max — у чисел, MaxLength — у строк.
+type Attribute struct {
+	Name      string        `json:"name"`
+	Label     string        `json:"label"`
+	Type      AttributeType `json:"type"`
+	Required  bool          `json:"required"`
+	Options   []string      `json:"options,omitempty"`
+	Min       *float64      `json:"min,omitempty"`
+	Max       *float64      `json:"max,omitempty"`
+	MaxLength int           `json:"max_length,omitempty"`
+}
+
+// Attributes — значения характеристик поста: строки у перечислений и строк,
+// float64 у чисел.
+type Attributes map[string]interface{}
+
+// ValidAttributeName сообщает, может ли name быть именем характеристики.
+func ValidAttributeName(name string) bool {
+	return len(name) <= maxAttributeName && validAttributeName.MatchString(name)
+}
+
+// validateAttributes проверяет схему характеристик категории.
+func validateAttributes(v *apperror.Violations, attributes []Attribute) {
+	if len(attributes) > maxAttributes {
+		v.Add("attributes", "category must not have more than %d attributes", maxAttributes)
+	}
+
+	seen := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		field := "attributes." + attribute.Name
+		switch {
+		case !ValidAttributeName(attribute.Name):
+			v.Add("attributes", "attribute name %q must start with a letter and contain only lowercase letters, digits, and underscores (up to %d characters)", attribute.Name, maxAttributeName)
+			continue
+		case seen[attribute.Name]:
+			v.Add(field, "attribute %s is defined more than once", attribute.Name)
+			continue
+		}
+		seen[attribute.Name] = true
+
+		switch {
+		case strings.TrimSpace(attribute.Label) == "":
+			v.Add(field, "label can't be empty")
+		case len([]rune(attribute.Label)) > maxAttributeLabel:
+			v.Add(field, "label must not exceed %d characters", maxAttributeLabel)
+		}
+
+		if attribute.Type != AttributeEnum && len(attribute.Options) > 0 {
+			v.Add(field, "options are only allowed for enum attributes")
+		}
+		if attribute.Type != AttributeNumber && (attribute.Min != nil || attribute.Max != nil) {
+			v.Add(field, "min and max are only allowed for number attributes")
+		}
+		if attribute.Type != AttributeString && attribute.MaxLength != 0 {
+			v.Add(field, "max_length is only allowed for string attributes")
+		}
+
+		switch attribute.Type {
+		case AttributeEnum:
+			validateOptions(v, field, attribute.Options)
+		case AttributeNumber:
+			if attribute.Min != nil && attribute.Max != nil && *attribute.Min > *attribute.Max {
+				v.Add(field, "min must not exceed max")
+			}
+		case AttributeString:
+			if attribute.MaxLength < 0 || attribute.MaxLength > maxAttributeStringLen {
+				v.Add(field, "max_length must be between 1 and %d", maxAttributeStringLen)
+			}
+		default:
+			v.Add(field, "type must be one of: enum, number, string")
+		}
+	}
+}
+
+func validateOptions(v *apperror.Violations, field string, options []string) {
+	switch {
+	case len(options) == 0:
+		v.Add(field, "enum attribute must have options")
+		return
+	case len(options) > maxAttributeOptions:
+		v.Add(field, "enum attribute must not have more than %d options", maxAttributeOptions)
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		switch {
+		case strings.TrimSpace(option) == "" || len([]rune(option)) > maxAttributeOption:
+			v.Add(field, "option %q must be 1 to %d characters long", option, maxAttributeOption)
+		case seen[option]:
+			v.Add(field, "option %q is listed more than once", option)
+		}
+		seen[option] = true
+	}
+}
+
+// ValidateAttributes проверяет значения характеристик поста по схеме его
+// категории и возвращает их в нормализованном виде: числа как float64,
+// строки без лишних пробелов, без значений null.
+func ValidateAttributes(schema []Attribute, values Attributes) (Attributes, error) {
+	var v apperror.Violations
+	normalized := make(Attributes, len(values))
+
+	defined := make(map[string]bool, len(schema))
+	for _, attribute := range schema {
+		defined[attribute.Name] = true
+		field := "attributes." + attribute.Name
+
+		value, ok := values[attribute.Name]
+		if !ok || value == nil {
+			if attribute.Required {
+				v.Add(field, "%s is required", attribute.Name)
+			}
+			continue
+		}
+
+		switch attribute.Type {
+		case AttributeEnum:
+			s, ok := value.(string)
+			if !ok || !slices.Contains(attribute.Options, s) {
+				v.Add(field, "%s must be one of: %s", attribute.Name, strings.Join(attribute.Options, ", "))
+				continue
+			}
+			normalized[attribute.Name] = s
+		case AttributeNumber:
+			number, ok := attributeNumber(value)
+			if !ok {
+				v.Add(field, "%s must be a number", attribute.Name)
+				continue
+			}
+			if attribute.Min != nil && number < *attribute.Min {
+				v.Add(field, "%s must be at least %s", attribute.Name, formatNumber(*attribute.Min))
+				continue
+			}
+			if attribute.Max != nil && number > *attribute.Max {
+				v.Add(field, "%s must not exceed %s", attribute.Name, formatNumber(*attribute.Max))
+				continue
+			}
+			normalized[attribute.Name] = number
+		case AttributeString:
+			s, ok := value.(string)
+			if !ok {
+				v.Add(field, "%s must be a string", attribute.Name)
+				continue
+			}
+			s = strings.Join(strings.Fields(s), " ")
+			limit := attribute.MaxLength
+			if limit == 0 {
+				limit = maxAttributeStringLen
+			}
+			switch {
+			case s == "" && attribute.Required:
+				v.Add(field, "%s is required", attribute.Name)
+			case s == "":
+			case len([]rune(s)) > limit:
+				v.Add(field, "%s must not exceed %d characters", attribute.Name, limit)
+			default:
+				normalized[attribute.Name] = s
+			}
+		}
+	}
+
+	var unknown []string
+	for name, value := range values {
+		if !defined[name] && value != nil {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		v.Add("attributes."+name, "category has no attribute %s", name)
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func attributeNumber(value interface{}) (float64, bool) {
+	var number float64
+	switch n := value.(type) {
+	case float64:
+		number = n
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		number = f
+	default:
+		return 0, false
+	}
+	return number, !math.IsNaN(number) && !math.IsInf(number, 0)
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"marketplace/internal/apperror"
+	"reflect"
+	"testing"
+)
+
+func attributeSchema() []Attribute {
+	minSize, maxSize := 16.0, 50.0
+	return []Attribute{
+		{Name: "condition", Label: "Состояние", Type: AttributeEnum, Required: true, Options: []string{"new", "used"}},
+		{Name: "size", Label: "Размер", Type: AttributeNumber, Min: &minSize, Max: &maxSize},
+		{Name: "brand", Label: "Бренд", Type: AttributeString, MaxLength: 10},
+	}
+}
+
+func TestValidateAttributes(t *testing.T) {
+	values := Attributes{"condition": "new", "size": json.Number("42"), "brand": "  Trek   bikes ", "color": nil}
+	got, err := ValidateAttributes(attributeSchema(), values)
+	if err != nil {
+		t.Fatalf("ValidateAttributes() error = %v", err)
+	}
+	want := Attributes{"condition": "new", "size": 42.0, "brand": "Trek bikes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateAttributes() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		values Attributes
+		field  string
+	}{
+		{"missing required", Attributes{}, "attributes.condition"},
+		{"unknown option", Attributes{"condition": "broken"}, "attributes.condition"},
+		{"number as string", Attributes{"condition": "new", "size": "42"}, "attributes.size"},
+		{"number below min", Attributes{"condition": "new", "size": 10.0}, "attributes.size"},
+		{"long string", Attributes{"condition": "new", "brand": "Specialized"}, "attributes.brand"},
+		{"unknown attribute", Attributes{"condition": "new", "color": "red"}, "attributes.color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateAttributes(attributeSchema(), tt.values)
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("ValidateAttributes() error = %v, want validation error", err)
+			}
+			violations := apperror.ViolationsOf(err)
+			if len(violations) != 1 || violations[0].Field != tt.field {
+				t.Errorf("violations = %v, want one for %s", violations, tt.field)
+			}
+		})
+	}
+
+	if _, err := ValidateAttributes(nil, Attributes{"size": 42.0}); !errors.Is(err, apperror.ErrValidation) {
+		t.Errorf("ValidateAttributes() without schema error = %v, want validation error", err)
+	}
+}
+
+func TestCategoryValidateAttributes(t *testing.T) {
+	category := &Category{Name: "Велосипеды", Slug: "bicycles", Attributes: attributeSchema()}
+	if err := category.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	minSize, maxSize := 10.0, 5.0
+	tests := []struct {
+		name      string
+		attribute Attribute
+		field     string
+	}{
+		{"bad name", Attribute{Name: "Size", Label: "Размер", Type: AttributeNumber}, "attributes"},
+		{"duplicate", Attribute{Name: "size", Label: "Размер", Type: AttributeNumber}, "attributes.size"},
+		{"enum without options", Attribute{Name: "color", Label: "Цвет", Type: AttributeEnum}, "attributes.color"},
+		{"min above max", Attribute{Name: "weight", Label: "Вес", Type: AttributeNumber, Min: &minSize, Max: &maxSize}, "attributes.weight"},
+		{"options on string", Attribute{Name: "model", Label: "Модель", Type: AttributeString, Options: []string{"a"}}, "attributes.model"},
+		{"unknown type", Attribute{Name: "year", Label: "Год", Type: "date"}, "attributes.year"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := &Category{Name: "Велосипеды", Slug: "bicycles", Attributes: append(attributeSchema(), tt.attribute)}
+			err := category.Validate()
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("Validate() error = %v, want validation error", err)
+			}
+			violations := apperror.ViolationsOf(err)
+			if len(violations) != 1 || violations[0].Field != tt.field {
+				t.Errorf("violations = %v, want one for %s", violations, tt.field)
+			}
+		})
+	}
+}
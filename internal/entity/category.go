@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var validSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type Category struct {
+	ID         uuid.UUID   `json:"id"`
+	ParentID   *uuid.UUID  `json:"parent_id"`
+	Name       string      `json:"name"`
+	Slug       string      `json:"slug"`
+	Attributes []Attribute `json:"attributes"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Children   []*Category `json:"children,omitempty"`
+}
+
+// CategoryFacet — количество постов в категории для текущих фильтров списка.
+type CategoryFacet struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+}
+
+func (c *Category) Validate() error {
+	var v apperror.Violations
+
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		v.Add("name", "name can't be empty")
+	case len(c.Name) > 100:
+		v.Add("name", "name must not exceed 100 characters")
+	}
+
+	switch {
+	case c.Slug == "":
+		v.Add("slug", "slug can't be empty")
+	case len(c.Slug) > 100:
+		v.Add("slug", "slug must not exceed 100 characters")
+	case !validSlug.MatchString(c.Slug):
+		v.Add("slug", "slug can only contain lowercase letters, digits, and hyphens")
+	}
+
+	if c.ParentID != nil && *c.ParentID == c.ID {
+		v.Add("parent_id", "category can't be its own parent")
+	}
+
+	validateAttributes(&v, c.Attributes)
+
+	return v.Err()
+}
+
+// BuildCategoryTree раскладывает плоский список категорий в дерево и
+// возвращает корневые категории.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.ID] = category
+	}
+
+	roots := make([]*Category, 0)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+	return roots
+}
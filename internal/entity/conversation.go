@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"encoding/base64"
+	"marketplace/internal/apperror"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxMessageLength       = 2000
+	DefaultMessagePageSize = 50
+	MaxMessagePageSize     = 100
+)
+
+// Conversation — переписка покупателя с автором поста. У покупателя по
+// каждому посту не больше одной переписки.
+type Conversation struct {
+	ID            uuid.UUID `json:"id"`
+	PostID        uuid.UUID `json:"post_id"`
+	PostHeader    string    `json:"post_header"`
+	BuyerID       uuid.UUID `json:"buyer_id"`
+	SellerID      uuid.UUID `json:"seller_id"`
+	UnreadCount   int       `json:"unread_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastMessageAt time.Time `json:"last_message_at"`
+}
+
+// HasParticipant сообщает, участвует ли пользователь в переписке.
+func (c *Conversation) HasParticipant(userID uuid.UUID) bool {
+	return c.BuyerID == userID || c.SellerID == userID
+}
+
+// Peer возвращает собеседника пользователя userID.
+func (c *Conversation) Peer(userID uuid.UUID) uuid.UUID {
+	if c.BuyerID == userID {
+		return c.SellerID
+	}
+	return c.BuyerID
+}
+
+// Message — сообщение в переписке. ReadAt пуст, пока собеседник не прочитал
+// сообщение.
+type Message struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	SenderID       uuid.UUID  `json:"sender_id"`
+	Body           string     `json:"body"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReadAt         *time.Time `json:"read_at"`
+}
+
+// NormalizeMessageBody убирает пробелы по краям текста сообщения.
+func NormalizeMessageBody(body string) string {
+	return strings.TrimSpace(body)
+}
+
+func (m *Message) Validate() error {
+	var v apperror.Violations
+
+	switch {
+	case m.Body == "":
+		v.Add("body", "message can't be empty")
+	case len([]rune(m.Body)) > MaxMessageLength:
+		v.Add("body", "message must not exceed %d characters", MaxMessageLength)
+	}
+
+	return v.Err()
+}
+
+// MessageCursor — позиция в переписке: страница начинается с сообщений,
+// отправленных раньше этого.
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Cursor возвращает курсор страницы, которая начинается сразу после
+// сообщения m.
+func (m *Message) Cursor() MessageCursor {
+	return MessageCursor{CreatedAt: m.CreatedAt, ID: m.ID}
+}
+
+// String кодирует курсор в непрозрачную строку для клиента.
+func (c MessageCursor) String() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseMessageCursor разбирает курсор, полученный от клиента.
+func ParseMessageCursor(s string) (MessageCursor, error) {
+	invalid := apperror.InvalidField("cursor", "invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return MessageCursor{}, invalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return MessageCursor{}, invalid
+	}
+
+	var cursor MessageCursor
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return MessageCursor{}, invalid
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return MessageCursor{}, invalid
+	}
+	return cursor, nil
+}
+
+// BlockedUser — пользователь из чёрного списка. Заблокированный не может
+// начинать переписку с тем, кто его заблокировал.
+type BlockedUser struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
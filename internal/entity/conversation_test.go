@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMessageCursor(t *testing.T) {
+	message := &Message{ID: uuid.New(), CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)}
+
+	cursor, err := ParseMessageCursor(message.Cursor().String())
+	if err != nil {
+		t.Fatalf("ParseMessageCursor() error = %v", err)
+	}
+	if !cursor.CreatedAt.Equal(message.CreatedAt) || cursor.ID != message.ID {
+		t.Errorf("ParseMessageCursor() = %v, want %v", cursor, message.Cursor())
+	}
+
+	for _, s := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "MjAyNC0wNS0wMXxub3QtdXVpZA"} {
+		if _, err := ParseMessageCursor(s); !errors.Is(err, apperror.ErrValidation) {
+			t.Errorf("ParseMessageCursor(%q) error = %v, want validation error", s, err)
+		}
+	}
+}
+
+func TestMessageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"valid", "Ещё продаёте?", false},
+		{"empty", NormalizeMessageBody("   "), true},
+		{"too long", strings.Repeat("я", MaxMessageLength+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Message{Body: tt.body}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConversationPeer(t *testing.T) {
+	conversation := &Conversation{BuyerID: uuid.New(), SellerID: uuid.New()}
+
+	if got := conversation.Peer(conversation.BuyerID); got != conversation.SellerID {
+		t.Errorf("Peer(buyer) = %v, want seller", got)
+	}
+	if got := conversation.Peer(conversation.SellerID); got != conversation.BuyerID {
+		t.Errorf("Peer(seller) = %v, want buyer", got)
+	}
+	if conversation.HasParticipant(uuid.New()) {
+		t.Error("HasParticipant(stranger) = true, want false")
+	}
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventMessage      EventType = "message"
+	EventNotification EventType = "notification"
+	EventPostStatus   EventType = "post_status"
+	// EventPostPublished не уходит клиентам WebSocket: по нему пост
+	// попадает в ленту новых постов GET /posts/stream.
+	EventPostPublished EventType = "post_published"
+)
+
+// Event — изменение, которое доставляется подключённым клиентам в реальном
+// времени. Сообщения и уведомления адресованы пользователям UserIDs, смена
+// статуса поста — всем, у кого пост PostID в избранном.
+// Публикация поста PostID доставляется подписчикам ленты новых постов.
+//
+// Между экземплярами сервера событие передаётся без содержимого: сообщение
+// или уведомление ObjectID и пост PostID загружаются из базы при получении,
+// и Data заполняется перед отправкой клиентам.
+type Event struct {
+	Type     EventType       `json:"type"`
+	UserIDs  []uuid.UUID     `json:"user_ids,omitempty"`
+	PostID   *uuid.UUID      `json:"post_id,omitempty"`
+	ObjectID *uuid.UUID      `json:"object_id,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// NewUserEvent создаёт событие об объекте objectID для пользователей userIDs.
+func NewUserEvent(eventType EventType, objectID uuid.UUID, userIDs ...uuid.UUID) *Event {
+	return &Event{Type: eventType, UserIDs: userIDs, ObjectID: &objectID}
+}
+
+// NewPostEvent создаёт событие для пользователей, у которых пост в избранном.
+// data может быть nil.
+func NewPostEvent(eventType EventType, postID uuid.UUID, data interface{}) (*Event, error) {
+	event := &Event{Type: eventType, PostID: &postID}
+	if data == nil {
+		return event, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	event.Data = raw
+	return event, nil
+}
+
+// PostPublishedData — данные события EventPostPublished. Время публикации
+// задаёт порядок поста в ленте одинаково на всех экземплярах сервера.
+type PostPublishedData struct {
+	PublishedAt time.Time `json:"published_at"`
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"math/big"
+	"regexp"
+	"time"
+)
+
+// validRate — положительное десятичное число, не больше 10 знаков после
+// точки, как в колонке NUMERIC(20, 10).
+var validRate = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,10})?$`)
+
+// ExchangeRate — курс валют: 1 единица Base стоит Rate единиц Quote.
+// Rate хранится десятичной строкой, чтобы не терять точность.
+type ExchangeRate struct {
+	Base      Currency  `json:"base"`
+	Quote     Currency  `json:"quote"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *ExchangeRate) Validate() error {
+	var v apperror.Violations
+
+	if !r.Base.IsValid() {
+		v.Add("base", "unsupported currency %q", r.Base)
+	}
+	if !r.Quote.IsValid() {
+		v.Add("quote", "unsupported currency %q", r.Quote)
+	}
+	if r.Base == r.Quote {
+		v.Add("quote", "quote currency must differ from base currency")
+	}
+	if rate, ok := r.value(); !ok || rate.Sign() <= 0 {
+		v.Add("rate", "rate must be a positive decimal number with at most 10 fractional digits")
+	}
+
+	return v.Err()
+}
+
+func (r *ExchangeRate) value() (*big.Rat, bool) {
+	if !validRate.MatchString(r.Rate) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(r.Rate)
+}
+
+// ExchangeRates — таблица курсов для пересчёта цен.
+type ExchangeRates []*ExchangeRate
+
+// Convert пересчитывает сумму в валюту to по прямому курсу, а если его нет —
+// по обратному. Результат округляется до минимальной единицы валюты
+// (половина — от нуля), так же как в фильтрах по цене. ok равен false, если
+// подходящего курса нет.
+func (rs ExchangeRates) Convert(m Money, to Currency) (Money, bool) {
+	if m.Currency == to {
+		return m, true
+	}
+
+	var rate *big.Rat
+	for _, r := range rs {
+		value, ok := r.value()
+		if !ok {
+			continue
+		}
+		if r.Base == m.Currency && r.Quote == to {
+			rate = value
+			break
+		}
+		if r.Base == to && r.Quote == m.Currency && rate == nil {
+			rate = new(big.Rat).Inv(value)
+		}
+	}
+	if rate == nil {
+		return Money{}, false
+	}
+
+	amount := new(big.Rat).SetInt64(m.Amount)
+	amount.Mul(amount, rate)
+	amount.Mul(amount, new(big.Rat).SetFrac64(to.MinorUnits(), m.Currency.MinorUnits()))
+	return Money{Amount: roundHalfAwayFromZero(amount), Currency: to}, true
+}
+
+// SetDisplayPrices заполняет DisplayPrice постов ценой в валюте currency. У
+// постов, для валюты которых нет курса, DisplayPrice остаётся пустым.
+func (rs ExchangeRates) SetDisplayPrices(currency Currency, posts ...*Post) {
+	if currency == "" {
+		return
+	}
+	for _, post := range posts {
+		if price, ok := rs.Convert(post.Price, currency); ok {
+			post.DisplayPrice = &price
+		}
+	}
+}
+
+func roundHalfAwayFromZero(x *big.Rat) int64 {
+	quo, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	// |rem| / denom >= 1/2
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(x.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(x.Sign())))
+	}
+	return quo.Int64()
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"testing"
+)
+
+func TestExchangeRatesConvert(t *testing.T) {
+	rates := ExchangeRates{
+		{Base: "USD", Quote: "RUB", Rate: "92.5"},
+		{Base: "EUR", Quote: "USD", Rate: "1.08"},
+		{Base: "USD", Quote: "JPY", Rate: "150.25"},
+	}
+
+	tests := []struct {
+		name   string
+		money  Money
+		to     Currency
+		want   Money
+		wantOK bool
+	}{
+		{"same currency", Money{Amount: 9950, Currency: "RUB"}, "RUB", Money{Amount: 9950, Currency: "RUB"}, true},
+		{"direct rate", Money{Amount: 1000, Currency: "USD"}, "RUB", Money{Amount: 92500, Currency: "RUB"}, true},
+		{"inverse rate", Money{Amount: 92500, Currency: "RUB"}, "USD", Money{Amount: 1000, Currency: "USD"}, true},
+		{"inverse rate rounds", Money{Amount: 100, Currency: "USD"}, "EUR", Money{Amount: 93, Currency: "EUR"}, true},
+		{"to currency without minor units", Money{Amount: 199, Currency: "USD"}, "JPY", Money{Amount: 299, Currency: "JPY"}, true},
+		{"from currency without minor units", Money{Amount: 15025, Currency: "JPY"}, "USD", Money{Amount: 10000, Currency: "USD"}, true},
+		{"half rounds away from zero", Money{Amount: 1, Currency: "USD"}, "RUB", Money{Amount: 93, Currency: "RUB"}, true},
+		{"no cross rates", Money{Amount: 100, Currency: "EUR"}, "RUB", Money{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rates.Convert(tt.money, tt.to)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Convert(%v, %s) = %v, %v; want %v, %v", tt.money, tt.to, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestExchangeRateValidate(t *testing.T) {
+	valid := &ExchangeRate{Base: "USD", Quote: "RUB", Rate: "92.5"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	for _, rate := range []*ExchangeRate{
+		{Base: "USD", Quote: "USD", Rate: "1"},
+		{Base: "XXX", Quote: "RUB", Rate: "1"},
+		{Base: "USD", Quote: "RUB", Rate: "0"},
+		{Base: "USD", Quote: "RUB", Rate: "-1"},
+		{Base: "USD", Quote: "RUB", Rate: "1e3"},
+		{Base: "USD", Quote: "RUB", Rate: "0.00000000001"},
+	} {
+		if err := rate.Validate(); !errors.Is(err, apperror.ErrValidation) {
+			t.Errorf("Validate(%+v) error = %v, want validation error", rate, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Допустимые форматы загружаемых изображений.
+const (
+	ImageJPEG = "image/jpeg"
+	ImagePNG  = "image/png"
+)
+
+type Image struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	StorageKey  string    `json:"-"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ImageURL — путь, по которому изображение отдаёт GET /images/:id.
+func ImageURL(id uuid.UUID) string {
+	return "/images/" + id.String()
+}
+
+// PostImage — изображение в галерее поста.
+type PostImage struct {
+	ImageID  uuid.UUID `json:"image_id"`
+	URL      string    `json:"url"`
+	Position int       `json:"position"`
+	IsCover  bool      `json:"is_cover"`
+	// Srcset — уменьшенные копии по форматам; пусто, пока воркер их не построил.
+	Srcset map[string]string `json:"srcset,omitempty"`
+}
+
+// NewGallery раскладывает изображения по позициям в заданном порядке.
+// Обложкой становится coverID, а если он не задан — первое изображение.
+func NewGallery(imageIDs []uuid.UUID, coverID *uuid.UUID) ([]PostImage, error) {
+	var v apperror.Violations
+
+	gallery := make([]PostImage, 0, len(imageIDs))
+	seen := make(map[uuid.UUID]bool, len(imageIDs))
+	for i, id := range imageIDs {
+		if seen[id] {
+			v.Add("image_ids", "image %s is listed more than once", id)
+			continue
+		}
+		seen[id] = true
+		gallery = append(gallery, PostImage{
+			ImageID:  id,
+			URL:      ImageURL(id),
+			Position: i,
+			IsCover:  (coverID == nil && i == 0) || (coverID != nil && *coverID == id),
+		})
+	}
+
+	if coverID != nil && !seen[*coverID] {
+		v.Add("cover_image_id", "cover image must be one of the post images")
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return gallery, nil
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"testing"
+
+	"marketplace/internal/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGallery(t *testing.T) {
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	gallery, err := NewGallery([]uuid.UUID{first, second, third}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []PostImage{
+		{ImageID: first, URL: ImageURL(first), Position: 0, IsCover: true},
+		{ImageID: second, URL: ImageURL(second), Position: 1},
+		{ImageID: third, URL: ImageURL(third), Position: 2},
+	}, gallery)
+
+	gallery, err = NewGallery([]uuid.UUID{first, second}, &second)
+	assert.NoError(t, err)
+	assert.False(t, gallery[0].IsCover)
+	assert.True(t, gallery[1].IsCover)
+
+	post := &Post{}
+	post.SetImages(gallery)
+	assert.Equal(t, ImageURL(second), post.Image)
+	assert.Equal(t, []uuid.UUID{first, second}, post.ImageIDs())
+}
+
+func TestNewGallery_Invalid(t *testing.T) {
+	first := uuid.New()
+	missing := uuid.New()
+
+	_, err := NewGallery([]uuid.UUID{first, first}, &missing)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Equal(t, []apperror.Violation{
+		{Field: "image_ids", Message: "image " + first.String() + " is listed more than once"},
+		{Field: "cover_image_id", Message: "cover image must be one of the post images"},
+	}, apperror.ViolationsOf(err))
+}
+
+func TestBuildSrcset(t *testing.T) {
+	id := uuid.New()
+	srcset := BuildSrcset([]*ImageVariant{
+		{ImageID: id, Width: 640, Format: VariantWebP},
+		{ImageID: id, Width: 320, Format: VariantWebP},
+		{ImageID: id, Width: 320, Format: VariantJPEG},
+	})
+
+	base := "/images/" + id.String()
+	assert.Equal(t, map[string]string{
+		VariantWebP: base + "/w320.webp 320w, " + base + "/w640.webp 640w",
+		VariantJPEG: base + "/w320.jpg 320w",
+	}, srcset)
+	assert.Nil(t, BuildSrcset(nil))
+}
+
+func TestParseVariantName(t *testing.T) {
+	width, format, ok := ParseVariantName("w640.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, 640, width)
+	assert.Equal(t, VariantJPEG, format)
+
+	for _, name := range []string{"640.jpg", "w0.png", "wx.webp", "w320.gif", "w320"} {
+		_, _, ok := ParseVariantName(name)
+		assert.False(t, ok, name)
+	}
+}
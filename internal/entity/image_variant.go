@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Форматы уменьшенных копий изображения.
+const (
+	VariantJPEG = "jpeg"
+	VariantPNG  = "png"
+	VariantWebP = "webp"
+)
+
+var variantExtensions = map[string]string{
+	VariantJPEG: "jpg",
+	VariantPNG:  "png",
+	VariantWebP: "webp",
+}
+
+var variantContentTypes = map[string]string{
+	VariantJPEG: ImageJPEG,
+	VariantPNG:  ImagePNG,
+	VariantWebP: "image/webp",
+}
+
+// ImageVariant — уменьшенная копия изображения заданной ширины и формата.
+type ImageVariant struct {
+	ImageID     uuid.UUID
+	Width       int
+	Height      int
+	Format      string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedAt   time.Time
+}
+
+// VariantContentType возвращает MIME-тип формата варианта.
+func VariantContentType(format string) string {
+	return variantContentTypes[format]
+}
+
+// VariantName — имя варианта в URL и ключе хранилища, например "w320.webp".
+func VariantName(width int, format string) string {
+	return fmt.Sprintf("w%d.%s", width, variantExtensions[format])
+}
+
+// ParseVariantName разбирает имя варианта, полученное из URL.
+func ParseVariantName(name string) (width int, format string, ok bool) {
+	base, ext, found := strings.Cut(name, ".")
+	if !found || !strings.HasPrefix(base, "w") {
+		return 0, "", false
+	}
+	width, err := strconv.Atoi(base[1:])
+	if err != nil || width <= 0 {
+		return 0, "", false
+	}
+	for f, e := range variantExtensions {
+		if e == ext {
+			return width, f, true
+		}
+	}
+	return 0, "", false
+}
+
+// ImageVariantURL — путь, по которому вариант отдаёт GET /images/:id/:variant.
+func ImageVariantURL(id uuid.UUID, width int, format string) string {
+	return ImageURL(id) + "/" + VariantName(width, format)
+}
+
+// BuildSrcset группирует варианты по формату в строки для атрибута srcset:
+// {"webp": "/images/<id>/w320.webp 320w, /images/<id>/w640.webp 640w"}.
+func BuildSrcset(variants []*ImageVariant) map[string]string {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	byFormat := make(map[string][]*ImageVariant)
+	for _, variant := range variants {
+		byFormat[variant.Format] = append(byFormat[variant.Format], variant)
+	}
+
+	srcset := make(map[string]string, len(byFormat))
+	for format, list := range byFormat {
+		sort.Slice(list, func(i, j int) bool { return list[i].Width < list[j].Width })
+		parts := make([]string, 0, len(list))
+		for _, variant := range list {
+			parts = append(parts, fmt.Sprintf("%s %dw", ImageVariantURL(variant.ImageID, variant.Width, format), variant.Width))
+		}
+		srcset[format] = strings.Join(parts, ", ")
+	}
+	return srcset
+}
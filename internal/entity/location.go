@@ -0,0 +1,21 @@
+package entity
+
+import "strings"
+
+// maxCityLength ограничивает длину названия города у поста.
+const maxCityLength = 100
+
+// ValidLatitude сообщает, что широта в градусах лежит в пределах [-90, 90].
+func ValidLatitude(lat float64) bool {
+	return lat >= -90 && lat <= 90
+}
+
+// ValidLongitude сообщает, что долгота в градусах лежит в пределах [-180, 180].
+func ValidLongitude(lng float64) bool {
+	return lng >= -180 && lng <= 180
+}
+
+// NormalizeCity убирает лишние пробелы в названии города.
+func NormalizeCity(city string) string {
+	return strings.Join(strings.Fields(city), " ")
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"math"
+	"testing"
+)
+
+func TestValidCoordinates(t *testing.T) {
+	for _, lat := range []float64{-90, 0, 55.7558, 90} {
+		if !ValidLatitude(lat) {
+			t.Errorf("ValidLatitude(%v) = false, want true", lat)
+		}
+	}
+	for _, lat := range []float64{-90.1, 91, math.NaN(), math.Inf(1)} {
+		if ValidLatitude(lat) {
+			t.Errorf("ValidLatitude(%v) = true, want false", lat)
+		}
+	}
+	for _, lng := range []float64{-180, 37.6173, 180} {
+		if !ValidLongitude(lng) {
+			t.Errorf("ValidLongitude(%v) = false, want true", lng)
+		}
+	}
+	for _, lng := range []float64{-180.5, 181, math.NaN()} {
+		if ValidLongitude(lng) {
+			t.Errorf("ValidLongitude(%v) = true, want false", lng)
+		}
+	}
+}
+
+func TestPostValidateLocation(t *testing.T) {
+	lat, lng, far := 55.7558, 37.6173, 200.0
+	tests := []struct {
+		name   string
+		lat    *float64
+		lng    *float64
+		city   string
+		fields []string
+	}{
+		{"no location", nil, nil, "", nil},
+		{"coordinates and city", &lat, &lng, "Москва", nil},
+		{"latitude only", &lat, nil, "", []string{"lat"}},
+		{"out of range", &far, &far, "", []string{"lat", "lng"}},
+		{"long city", nil, nil, string(make([]rune, maxCityLength+1)), []string{"city"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := &Post{
+				Header:  "Bicycle",
+				Content: "A good bicycle",
+				Image:   "https://example.com/bike.jpg",
+				Price:   Money{Amount: 100, Currency: "RUB"},
+				Lat:     tt.lat,
+				Lng:     tt.lng,
+				City:    tt.city,
+			}
+			err := post.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("Validate() error = %v, want validation error", err)
+			}
+			var fields []string
+			for _, violation := range apperror.ViolationsOf(err) {
+				fields = append(fields, violation.Field)
+			}
+			if len(fields) != len(tt.fields) {
+				t.Fatalf("violations = %v, want %v", fields, tt.fields)
+			}
+			for i := range fields {
+				if fields[i] != tt.fields[i] {
+					t.Errorf("violations = %v, want %v", fields, tt.fields)
+				}
+			}
+		})
+	}
+}
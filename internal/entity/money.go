@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"fmt"
+	"marketplace/internal/apperror"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Currency — код валюты по ISO 4217.
+type Currency string
+
+// currencyExponents — поддерживаемые валюты и число знаков после запятой
+// в их минимальной единице.
+var currencyExponents = map[Currency]int{
+	"RUB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CNY": 2,
+	"KZT": 2,
+	"BYN": 2,
+	"JPY": 0,
+}
+
+// ParseCurrency проверяет код валюты без учёта регистра.
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if !c.IsValid() {
+		return "", apperror.Validation("unsupported currency %q", s)
+	}
+	return c, nil
+}
+
+func (c Currency) IsValid() bool {
+	_, ok := currencyExponents[c]
+	return ok
+}
+
+// Exponent возвращает число знаков после запятой в сумме этой валюты.
+func (c Currency) Exponent() int {
+	return currencyExponents[c]
+}
+
+// MinorUnits возвращает число минимальных единиц в одной основной.
+func (c Currency) MinorUnits() int64 {
+	return pow10(c.Exponent())
+}
+
+// Currencies возвращает поддерживаемые валюты по алфавиту.
+func Currencies() []Currency {
+	currencies := make([]Currency, 0, len(currencyExponents))
+	for c := range currencyExponents {
+		currencies = append(currencies, c)
+	}
+	slices.Sort(currencies)
+	return currencies
+}
+
+// Money — сумма в минимальных единицах валюты (копейках, центах), без
+// ошибок округления float. В JSON: {"amount": 9950, "currency": "RUB"}.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// String форматирует сумму в основных единицах: "99.50 RUB".
+func (m Money) String() string {
+	exp := m.Currency.Exponent()
+	if exp == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := m.Currency.MinorUnits()
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/unit, exp, amount%unit, m.Currency)
+}
+
+// ParseAmount переводит десятичную запись в основных единицах ("99.5") в
+// минимальные единицы валюты. Знаков после запятой не может быть больше,
+// чем у валюты.
+func ParseAmount(s string, currency Currency) (int64, error) {
+	exp := currency.Exponent()
+	whole, frac, hasFrac := strings.Cut(strings.TrimSpace(s), ".")
+	if whole == "" || (hasFrac && frac == "") || len(frac) > exp || strings.HasPrefix(frac, "-") || strings.HasPrefix(frac, "+") {
+		return 0, fmt.Errorf("invalid amount %q for %s", s, currency)
+	}
+
+	amount, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q for %s", s, currency)
+	}
+	return amount, nil
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package entity
+
+import "testing"
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 9950, Currency: "RUB"}, "99.50 RUB"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05 USD"},
+		{Money{Amount: -120, Currency: "EUR"}, "-1.20 EUR"},
+		{Money{Amount: 1500, Currency: "JPY"}, "1500 JPY"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		want     int64
+		wantErr  bool
+	}{
+		{"99.5", "RUB", 9950, false},
+		{"99.50", "RUB", 9950, false},
+		{"100", "USD", 10000, false},
+		{"0.01", "EUR", 1, false},
+		{"1500", "JPY", 1500, false},
+		{"1500.5", "JPY", 0, true},
+		{"0.001", "RUB", 0, true},
+		{"12.", "RUB", 0, true},
+		{".5", "RUB", 0, true},
+		{"1.-5", "RUB", 0, true},
+		{"cheap", "RUB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in, tt.currency)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAmount(%q, %s) = %d, %v; want %d, error %v", tt.in, tt.currency, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	if c, err := ParseCurrency("usd"); err != nil || c != "USD" {
+		t.Errorf("ParseCurrency(usd) = %q, %v", c, err)
+	}
+	if _, err := ParseCurrency("XXX"); err == nil {
+		t.Error("ParseCurrency(XXX) error = nil, want error")
+	}
+}
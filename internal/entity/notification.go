@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind string
+
+const (
+	NotificationNewMessage       NotificationKind = "new_message"
+	NotificationPostFavorited    NotificationKind = "post_favorited"
+	NotificationSavedSearchMatch NotificationKind = "saved_search_match"
+	NotificationPostModerated    NotificationKind = "post_moderated"
+)
+
+// NotificationKinds возвращает все виды уведомлений.
+func NotificationKinds() []NotificationKind {
+	return []NotificationKind{NotificationNewMessage, NotificationPostFavorited, NotificationSavedSearchMatch, NotificationPostModerated}
+}
+
+// ParseNotificationKind проверяет вид уведомления, пришедший в запросе.
+func ParseNotificationKind(s string) (NotificationKind, error) {
+	for _, kind := range NotificationKinds() {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	return "", apperror.InvalidField("kind", "notification kind must be one of: new_message, post_favorited, saved_search_match, post_moderated")
+}
+
+// NotificationData — подробности уведомления, набор полей зависит от вида:
+// post_id, saved_search_id, conversation_id и т. п.
+type NotificationData map[string]interface{}
+
+// Notification — событие, о котором нужно сообщить пользователю. ReadAt
+// пуст, пока пользователь не отметил уведомление прочитанным.
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Kind      NotificationKind `json:"kind"`
+	Data      NotificationData `json:"data"`
+	CreatedAt time.Time        `json:"created_at"`
+	ReadAt    *time.Time       `json:"read_at"`
+}
+
+// NotificationPreferences — какие виды уведомлений пользователь хочет
+// получать. Вид, которого нет в настройках, включён.
+type NotificationPreferences map[NotificationKind]bool
+
+// Enabled сообщает, нужно ли создавать уведомления вида kind.
+func (p NotificationPreferences) Enabled(kind NotificationKind) bool {
+	enabled, ok := p[kind]
+	return !ok || enabled
+}
+
+// WithDefaults возвращает настройки по всем видам уведомлений.
+func (p NotificationPreferences) WithDefaults() NotificationPreferences {
+	full := make(NotificationPreferences, len(NotificationKinds()))
+	for _, kind := range NotificationKinds() {
+		full[kind] = p.Enabled(kind)
+	}
+	return full
+}
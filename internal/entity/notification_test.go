@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"reflect"
+	"testing"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	preferences := NotificationPreferences{NotificationPostFavorited: false, NotificationNewMessage: true}
+
+	if preferences.Enabled(NotificationPostFavorited) {
+		t.Error("Enabled(post_favorited) = true, want false")
+	}
+	if !preferences.Enabled(NotificationSavedSearchMatch) {
+		t.Error("Enabled(saved_search_match) = false, want true by default")
+	}
+
+	want := NotificationPreferences{
+		NotificationNewMessage:       true,
+		NotificationPostFavorited:    false,
+		NotificationSavedSearchMatch: true,
+		NotificationPostModerated:    true,
+	}
+	if got := preferences.WithDefaults(); !reflect.DeepEqual(got, want) {
+		t.Errorf("WithDefaults() = %v, want %v", got, want)
+	}
+}
+
+func TestParseNotificationKind(t *testing.T) {
+	kind, err := ParseNotificationKind("post_moderated")
+	if err != nil || kind != NotificationPostModerated {
+		t.Errorf("ParseNotificationKind() = %v, %v", kind, err)
+	}
+	if _, err := ParseNotificationKind("newsletter"); !errors.Is(err, apperror.ErrValidation) {
+		t.Errorf("ParseNotificationKind() error = %v, want validation error", err)
+	}
+}
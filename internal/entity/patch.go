@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"marketplace/internal/apperror"
+	"marketplace/pkg/mergepatch"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PostPatch — редактируемые поля поста в виде документа, к которому
+// применяется JSON Merge Patch. null в патче очищает поле.
+type PostPatch struct {
+	Header       string      `json:"header"`
+	Content      string      `json:"content"`
+	ImageIDs     []uuid.UUID `json:"image_ids"`
+	CoverImageID *uuid.UUID  `json:"cover_image_id"`
+	Price        Money       `json:"price"`
+	CategoryID   *uuid.UUID  `json:"category_id"`
+	Tags         []string    `json:"tags"`
+	Attributes   Attributes  `json:"attributes"`
+	Lat          *float64    `json:"lat"`
+	Lng          *float64    `json:"lng"`
+	City         string      `json:"city"`
+}
+
+// PatchDocument возвращает текущие значения редактируемых полей поста.
+func (p *Post) PatchDocument() PostPatch {
+	doc := PostPatch{
+		Header:     p.Header,
+		Content:    p.Content,
+		ImageIDs:   p.ImageIDs(),
+		Price:      p.Price,
+		CategoryID: p.CategoryID,
+		Tags:       p.Tags,
+		Attributes: p.Attributes,
+		Lat:        p.Lat,
+		Lng:        p.Lng,
+		City:       p.City,
+	}
+	for _, image := range p.Images {
+		if image.IsCover {
+			id := image.ImageID
+			doc.CoverImageID = &id
+		}
+	}
+	return doc
+}
+
+// UserPatch — редактируемые поля пользователя. Пароль в документе не
+// отдаётся, его можно только задать.
+type UserPatch struct {
+	Username string  `json:"username"`
+	Password *string `json:"password,omitempty"`
+}
+
+// PatchDocument возвращает текущие значения редактируемых полей пользователя.
+func (u *User) PatchDocument() UserPatch {
+	return UserPatch{Username: u.Username}
+}
+
+// ApplyMergePatch применяет patch к документу current и раскладывает
+// результат в result. Поля, которых нет в документе, и значения не того типа
+// возвращаются как ошибки валидации.
+func ApplyMergePatch(current, result interface{}, patch []byte) error {
+	if !mergepatch.IsObject(patch) {
+		return apperror.Validation("merge patch must be a JSON object")
+	}
+
+	target, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	merged, err := mergepatch.Apply(target, patch)
+	if err != nil {
+		return apperror.Validation("malformed merge patch")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return apperror.InvalidField(typeErr.Field, "%s must be of type %s", typeErr.Field, typeErr.Type.Kind())
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field = strings.Trim(field, `"`)
+			return apperror.InvalidField(field, "%s can't be changed", field)
+		}
+		return apperror.Validation("malformed merge patch: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	categoryID := uuid.New()
+	post := &Post{
+		Header:     "Old header",
+		Content:    "Old content here",
+		Price:      Money{Amount: 10000, Currency: "RUB"},
+		CategoryID: &categoryID,
+		Tags:       []string{"bike"},
+	}
+
+	var patched PostPatch
+	err := ApplyMergePatch(post.PatchDocument(), &patched, []byte(`{"price":{"amount":0},"category_id":null,"tags":null}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatch() error = %v", err)
+	}
+
+	if patched.Price != (Money{Amount: 0, Currency: "RUB"}) {
+		t.Errorf("Price = %v, want 0.00 RUB", patched.Price)
+	}
+	if patched.CategoryID != nil {
+		t.Errorf("CategoryID = %v, want nil", patched.CategoryID)
+	}
+	if patched.Tags != nil {
+		t.Errorf("Tags = %v, want nil", patched.Tags)
+	}
+	if patched.Header != post.Header || patched.Content != post.Content {
+		t.Errorf("untouched fields changed: %+v", patched)
+	}
+}
+
+func TestApplyMergePatch_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		field string
+	}{
+		{"not an object", `["header"]`, ""},
+		{"malformed", `{"header":`, ""},
+		{"unknown field", `{"author_id":"x"}`, "author_id"},
+		{"wrong type", `{"price":{"amount":"free"}}`, "price.amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched PostPatch
+			err := ApplyMergePatch(PostPatch{Header: "Header"}, &patched, []byte(tt.patch))
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("ApplyMergePatch() error = %v, want validation error", err)
+			}
+			if tt.field == "" {
+				return
+			}
+			if violations := apperror.ViolationsOf(err); len(violations) != 1 || violations[0].Field != tt.field {
+				t.Errorf("violations = %+v, want one for %s", violations, tt.field)
+			}
+		})
+	}
+}
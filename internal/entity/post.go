@@ -1,8 +1,7 @@
 package entity
 
 import (
-	"fmt"
-	"net/url"
+	"marketplace/internal/apperror"
 	"regexp"
 	"strings"
 	"time"
@@ -10,55 +9,162 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	maxTags      = 10
+	maxTagLength = 30
+	maxImages    = 10
+	// maxPrice — предел цены в основных единицах любой валюты.
+	maxPrice = 1000000
+)
+
+var validTag = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _-]*$`)
+
 type Post struct {
-	ID             uuid.UUID `json:"id"`
-	Header         string    `json:"header"`
-	Content        string    `json:"content"`
-	Image          string    `json:"image"`
-	Price          float64   `json:"price"`
-	AuthorID       uuid.UUID `json:"author_id"`
-	CreatedAt      time.Time `json:"created_at"`
-	IsOwnPost      bool      `json:"is_own_post"`
-	AuthorUsername string    `json:"author_username"`
+	ID             uuid.UUID   `json:"id"`
+	Header         string      `json:"header"`
+	Content        string      `json:"content"`
+	Image          string      `json:"image"`
+	Images         []PostImage `json:"images"`
+	Price          Money       `json:"price"`
+	DisplayPrice   *Money      `json:"display_price,omitempty"`
+	CategoryID     *uuid.UUID  `json:"category_id"`
+	Tags           []string    `json:"tags"`
+	Attributes     Attributes  `json:"attributes"`
+	Lat            *float64    `json:"lat"`
+	Lng            *float64    `json:"lng"`
+	City           string      `json:"city"`
+	Distance       *float64    `json:"distance_km,omitempty"`
+	AuthorID       uuid.UUID   `json:"author_id"`
+	Status         PostStatus  `json:"status"`
+	Version        int         `json:"version"`
+	CreatedAt      time.Time   `json:"created_at"`
+	FavoritesCount int         `json:"favorites_count"`
+	IsOwnPost      bool        `json:"is_own_post"`
+	IsFavorited    bool        `json:"is_favorited"`
+	AuthorUsername string      `json:"author_username"`
+	Rank           float32     `json:"rank,omitempty"`
+	Snippet        string      `json:"snippet,omitempty"`
 }
 
-func (p *Post) Validate() error {
-	if p.Header == "" {
-		return fmt.Errorf("header can't be empty")
+// PostParams — поля поста, которые автор задаёт при создании и редактировании.
+// При редактировании пустые значения (и nil у ImageIDs, CoverImageID, Price,
+// CategoryID, Tags, Attributes, Lat и Lng) означают «не менять». Draft
+// учитывается только при создании: такой пост не виден покупателям до
+// публикации.
+type PostParams struct {
+	Header       string
+	Content      string
+	ImageIDs     []uuid.UUID
+	CoverImageID *uuid.UUID
+	Price        *Money
+	CategoryID   *uuid.UUID
+	Tags         []string
+	Attributes   Attributes
+	Lat          *float64
+	Lng          *float64
+	City         string
+	Draft        bool
+}
+
+// NormalizeTags приводит теги к нижнему регистру, убирает лишние пробелы,
+// пустые значения и повторы.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
 	}
-	if len(strings.TrimSpace(p.Header)) < 5 {
-		return fmt.Errorf("header must be at least 5 characters long")
+	return normalized
+}
+
+// ImageIDs возвращает изображения галереи в порядке показа.
+func (p *Post) ImageIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(p.Images))
+	for _, image := range p.Images {
+		ids = append(ids, image.ImageID)
 	}
-	if len(p.Header) > 100 {
-		return fmt.Errorf("header must not exceed 100 characters")
+	return ids
+}
+
+// SetImages заменяет галерею и обновляет Image — ссылку на обложку.
+func (p *Post) SetImages(images []PostImage) {
+	p.Images = images
+	p.Image = ""
+	for _, image := range images {
+		if image.IsCover {
+			p.Image = image.URL
+		}
 	}
+}
+
+func (p *Post) Validate() error {
+	var v apperror.Violations
 
-	if p.Content == "" {
-		return fmt.Errorf("content can't be empty")
+	switch {
+	case p.Header == "":
+		v.Add("header", "header can't be empty")
+	case len(strings.TrimSpace(p.Header)) < 5:
+		v.Add("header", "header must be at least 5 characters long")
+	case len(p.Header) > 100:
+		v.Add("header", "header must not exceed 100 characters")
 	}
-	if len(strings.TrimSpace(p.Content)) < 10 {
-		return fmt.Errorf("content must be at least 10 characters long")
+
+	switch {
+	case p.Content == "":
+		v.Add("content", "content can't be empty")
+	case len(strings.TrimSpace(p.Content)) < 10:
+		v.Add("content", "content must be at least 10 characters long")
+	case len(p.Content) > 1000:
+		v.Add("content", "content must not exceed 1000 characters")
 	}
-	if len(p.Content) > 1000 {
-		return fmt.Errorf("content must not exceed 1000 characters")
+
+	// У постов, созданных до загрузки изображений, остаётся внешний URL в Image.
+	switch {
+	case len(p.Images) == 0 && p.Image == "":
+		v.Add("image_ids", "post must have at least one image")
+	case len(p.Images) > maxImages:
+		v.Add("image_ids", "post must not have more than %d images", maxImages)
 	}
 
-	if p.Image == "" {
-		return fmt.Errorf("post must have image")
+	switch {
+	case !p.Price.Currency.IsValid():
+		v.Add("price.currency", "unsupported currency %q", p.Price.Currency)
+	case p.Price.Amount < 0:
+		v.Add("price", "price must be positive")
+	case p.Price.Amount > maxPrice*pow10(p.Price.Currency.Exponent()):
+		v.Add("price", "price must not exceed %d %s", maxPrice, p.Price.Currency)
 	}
-	if _, err := url.ParseRequestURI(p.Image); err != nil {
-		return fmt.Errorf("image must be a valid URL: %w", err)
+
+	if len(p.Tags) > maxTags {
+		v.Add("tags", "post must not have more than %d tags", maxTags)
 	}
-	validImageExt := regexp.MustCompile(`\.(png|jpg|jpeg)$`)
-	if !validImageExt.MatchString(strings.ToLower(p.Image)) {
-		return fmt.Errorf("image must be in PNG or JPEG format")
+	for _, tag := range p.Tags {
+		if len([]rune(tag)) > maxTagLength {
+			v.Add("tags", "tag %q must not exceed %d characters", tag, maxTagLength)
+		} else if !validTag.MatchString(tag) {
+			v.Add("tags", "tag %q can only contain letters, digits, spaces, hyphens, and underscores", tag)
+		}
 	}
 
-	if p.Price < 0 {
-		return fmt.Errorf("price must be positive")
+	switch {
+	case (p.Lat == nil) != (p.Lng == nil):
+		v.Add("lat", "lat and lng must be set together")
+	case p.Lat != nil:
+		if !ValidLatitude(*p.Lat) {
+			v.Add("lat", "lat must be between -90 and 90")
+		}
+		if !ValidLongitude(*p.Lng) {
+			v.Add("lng", "lng must be between -180 and 180")
+		}
 	}
-	if p.Price > 1000000 {
-		return fmt.Errorf("price must not exceed 1000000")
+	if len([]rune(p.City)) > maxCityLength {
+		v.Add("city", "city must not exceed %d characters", maxCityLength)
 	}
-	return nil
+
+	return v.Err()
 }
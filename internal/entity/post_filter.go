@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"net/url"
+	"strings"
+)
+
+// maxSearchLength ограничивает длину поискового запроса q.
+const maxSearchLength = 200
+
+// maxAttributeFilters ограничивает число фильтров attr.<name> в одном запросе.
+const maxAttributeFilters = 10
+
+// ParsePostFilter собирает фильтры списка постов из query-параметров.
+// Теги можно передать несколькими параметрами tag или через запятую.
+// Координаты, радиус и границы цены проверяются вместе с остальными
+// фильтрами при выборке.
+func ParsePostFilter(query url.Values) (map[string]string, error) {
+	filter := make(map[string]string)
+	for _, key := range []string{"min_price", "max_price", "category", "lat", "lng", "radius_km", "bbox"} {
+		if value := query.Get(key); value != "" {
+			filter[key] = value
+		}
+	}
+	if code := query.Get("currency"); code != "" {
+		currency, err := ParseCurrency(code)
+		if err != nil {
+			return nil, apperror.InvalidField("currency", "unsupported currency %q", code)
+		}
+		filter["currency"] = string(currency)
+	}
+	if search := strings.TrimSpace(query.Get("q")); search != "" {
+		if len(search) > maxSearchLength {
+			return nil, apperror.InvalidField("q", "q must not exceed %d characters", maxSearchLength)
+		}
+		filter["q"] = search
+	}
+	if status := query.Get("status"); status != "" {
+		if _, err := ParsePostStatus(status); err != nil {
+			return nil, err
+		}
+		filter["status"] = status
+	}
+
+	if err := attributeFilters(query, filter); err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, value := range query["tag"] {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+	if tags = NormalizeTags(tags); len(tags) > 0 {
+		filter["tag"] = strings.Join(tags, ",")
+	}
+
+	return filter, nil
+}
+
+// attributeFilters переносит в фильтр параметры attr.<name>=<value>. Значение
+// сравнивается с характеристикой поста целиком.
+func attributeFilters(query url.Values, filter map[string]string) error {
+	count := 0
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "attr.")
+		if !ok || len(values) == 0 || values[0] == "" {
+			continue
+		}
+		if !ValidAttributeName(name) {
+			return apperror.InvalidField(key, "invalid attribute name %q", name)
+		}
+		if count++; count > maxAttributeFilters {
+			return apperror.InvalidField("attr", "no more than %d attribute filters are allowed", maxAttributeFilters)
+		}
+		filter[key] = values[0]
+	}
+	return nil
+}
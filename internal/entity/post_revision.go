@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostRevision — снимок редактируемых полей поста после создания или правки.
+// Номер ревизии назначает репозиторий, первая ревизия — состояние при создании.
+type PostRevision struct {
+	ID             uuid.UUID   `json:"id"`
+	PostID         uuid.UUID   `json:"post_id"`
+	Revision       int         `json:"revision"`
+	EditorID       *uuid.UUID  `json:"editor_id"`
+	EditorUsername string      `json:"editor_username"`
+	Header         string      `json:"header"`
+	Content        string      `json:"content"`
+	Image          string      `json:"image"`
+	Price          Money       `json:"price"`
+	CategoryID     *uuid.UUID  `json:"category_id"`
+	Tags           []string    `json:"tags"`
+	Attributes     Attributes  `json:"attributes"`
+	Lat            *float64    `json:"lat"`
+	Lng            *float64    `json:"lng"`
+	City           string      `json:"city"`
+	ImageIDs       []uuid.UUID `json:"image_ids"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// FieldChange — изменение одного поля между двумя ревизиями.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionDiff — изменения между ревизиями From и To.
+type RevisionDiff struct {
+	PostID  uuid.UUID     `json:"post_id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+func NewPostRevision(post *Post, editorID uuid.UUID) *PostRevision {
+	tags := post.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	attributes := post.Attributes
+	if attributes == nil {
+		attributes = Attributes{}
+	}
+	return &PostRevision{
+		ID:         uuid.New(),
+		PostID:     post.ID,
+		EditorID:   &editorID,
+		Header:     post.Header,
+		Content:    post.Content,
+		Image:      post.Image,
+		Price:      post.Price,
+		CategoryID: post.CategoryID,
+		Tags:       tags,
+		Attributes: attributes,
+		Lat:        post.Lat,
+		Lng:        post.Lng,
+		City:       post.City,
+		ImageIDs:   post.ImageIDs(),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// DiffRevisions сравнивает ревизии поле за полем и возвращает только
+// изменившиеся поля в порядке их объявления.
+func DiffRevisions(from, to *PostRevision) *RevisionDiff {
+	diff := &RevisionDiff{PostID: to.PostID, From: from.Revision, To: to.Revision, Changes: []FieldChange{}}
+
+	fields := []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"header", from.Header, to.Header},
+		{"content", from.Content, to.Content},
+		{"image", from.Image, to.Image},
+		{"price", from.Price, to.Price},
+		{"category_id", from.CategoryID, to.CategoryID},
+		{"tags", from.Tags, to.Tags},
+		{"attributes", from.Attributes, to.Attributes},
+		{"lat", from.Lat, to.Lat},
+		{"lng", from.Lng, to.Lng},
+		{"city", from.City, to.City},
+		{"image_ids", from.ImageIDs, to.ImageIDs},
+	}
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.from, field.to) {
+			diff.Changes = append(diff.Changes, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return diff
+}
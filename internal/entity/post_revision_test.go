@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	imageID := uuid.New()
+	categoryID := uuid.New()
+	from := &PostRevision{
+		Revision: 1,
+		Header:   "Red bike",
+		Content:  "Almost new red bike",
+		Price:    Money{Amount: 10000, Currency: "RUB"},
+		Tags:     []string{"red"},
+		ImageIDs: []uuid.UUID{imageID},
+	}
+	to := &PostRevision{
+		Revision:   3,
+		Header:     "Red bike",
+		Content:    "Almost new red bike",
+		Price:      Money{Amount: 8000, Currency: "RUB"},
+		CategoryID: &categoryID,
+		Tags:       []string{"red"},
+		ImageIDs:   []uuid.UUID{imageID},
+	}
+
+	diff := DiffRevisions(from, to)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 3, diff.To)
+	assert.Equal(t, []FieldChange{
+		{Field: "price", From: Money{Amount: 10000, Currency: "RUB"}, To: Money{Amount: 8000, Currency: "RUB"}},
+		{Field: "category_id", From: (*uuid.UUID)(nil), To: &categoryID},
+	}, diff.Changes)
+
+	assert.Empty(t, DiffRevisions(to, to).Changes)
+}
+
+func TestDiffRevisions_AttributesAndLocation(t *testing.T) {
+	lat, lng := 55.75, 37.62
+	post := &Post{
+		ID:      uuid.New(),
+		Header:  "Sneakers",
+		Content: "Worn twice",
+		Price:   Money{Amount: 500000, Currency: "RUB"},
+		City:    "Moscow",
+	}
+	from := NewPostRevision(post, post.AuthorID)
+	from.Revision = 1
+	assert.Equal(t, Attributes{}, from.Attributes)
+
+	edited := *post
+	edited.Attributes = Attributes{"size": float64(42), "condition": "used"}
+	edited.Lat, edited.Lng = &lat, &lng
+	edited.City = "Saint Petersburg"
+	edited.Price = Money{Amount: 500000, Currency: "EUR"}
+	to := NewPostRevision(&edited, post.AuthorID)
+	to.Revision = 2
+
+	diff := DiffRevisions(from, to)
+	assert.Equal(t, []FieldChange{
+		{Field: "price", From: Money{Amount: 500000, Currency: "RUB"}, To: Money{Amount: 500000, Currency: "EUR"}},
+		{Field: "attributes", From: Attributes{}, To: Attributes{"size": float64(42), "condition": "used"}},
+		{Field: "lat", From: (*float64)(nil), To: &lat},
+		{Field: "lng", From: (*float64)(nil), To: &lng},
+		{Field: "city", From: "Moscow", To: "Saint Petersburg"},
+	}, diff.Changes)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "marketplace/internal/apperror"
+
+type PostStatus string
+
+const (
+	PostDraft     PostStatus = "draft"
+	PostPublished PostStatus = "published"
+	PostReserved  PostStatus = "reserved"
+	PostSold      PostStatus = "sold"
+	PostArchived  PostStatus = "archived"
+)
+
+// postTransitions — допустимые переходы между статусами поста.
+var postTransitions = map[PostStatus][]PostStatus{
+	PostDraft:     {PostPublished, PostArchived},
+	PostPublished: {PostReserved, PostSold, PostArchived},
+	PostReserved:  {PostPublished, PostSold, PostArchived},
+	PostSold:      {PostArchived},
+	PostArchived:  {PostPublished},
+}
+
+// ParsePostStatus проверяет статус, пришедший в запросе.
+func ParsePostStatus(s string) (PostStatus, error) {
+	status := PostStatus(s)
+	if _, ok := postTransitions[status]; !ok {
+		return "", apperror.InvalidField("status", "status must be one of: draft, published, reserved, sold, archived")
+	}
+	return status, nil
+}
+
+func (s PostStatus) CanTransitionTo(next PostStatus) bool {
+	for _, allowed := range postTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic сообщает, видна ли карточка поста всем пользователям.
+// Черновики и архив видны только автору и модераторам.
+func (s PostStatus) IsPublic() bool {
+	return s == PostPublished || s == PostReserved || s == PostSold
+}
+
+// PublicPostStatuses возвращает статусы, в которых пост виден всем.
+func PublicPostStatuses() []PostStatus {
+	return []PostStatus{PostPublished, PostReserved, PostSold}
+}
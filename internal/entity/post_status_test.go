@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+
+	"marketplace/internal/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to PostStatus
+		allowed  bool
+	}{
+		{PostDraft, PostPublished, true},
+		{PostDraft, PostSold, false},
+		{PostPublished, PostReserved, true},
+		{PostPublished, PostPublished, false},
+		{PostReserved, PostPublished, true},
+		{PostReserved, PostSold, true},
+		{PostSold, PostPublished, false},
+		{PostSold, PostArchived, true},
+		{PostArchived, PostPublished, true},
+		{PostArchived, PostReserved, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestParsePostStatus(t *testing.T) {
+	status, err := ParsePostStatus("reserved")
+	assert.NoError(t, err)
+	assert.Equal(t, PostReserved, status)
+
+	_, err = ParsePostStatus("deleted")
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"marketplace/internal/apperror"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxSavedSearches ограничивает число сохранённых поисков у одного пользователя.
+const MaxSavedSearches = 20
+
+// SavedSearch — сохранённые пользователем фильтры списка постов. Новые
+// опубликованные посты, подходящие под фильтры, попадают в совпадения поиска.
+type SavedSearch struct {
+	ID          uuid.UUID         `json:"id"`
+	UserID      uuid.UUID         `json:"user_id"`
+	Name        string            `json:"name"`
+	Filter      map[string]string `json:"filter"`
+	UnseenCount int               `json:"unseen_count"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// SavedSearchFilter оставляет из фильтров списка постов те, что имеют смысл
+// для сохранённого поиска: совпадения ищутся только среди опубликованных
+// постов, поэтому статус отбрасывается.
+func SavedSearchFilter(filter map[string]string) map[string]string {
+	saved := make(map[string]string, len(filter))
+	for key, value := range filter {
+		if key != "status" {
+			saved[key] = value
+		}
+	}
+	return saved
+}
+
+func (s *SavedSearch) Validate() error {
+	var v apperror.Violations
+
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		v.Add("name", "name can't be empty")
+	case len([]rune(s.Name)) > 100:
+		v.Add("name", "name must not exceed 100 characters")
+	}
+
+	// Без фильтров под поиск подходил бы каждый новый пост.
+	criteria := 0
+	for key := range s.Filter {
+		if key != "currency" {
+			criteria++
+		}
+	}
+	if criteria == 0 {
+		v.Add("query", "saved search must have at least one filter")
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSavedSearchValidate(t *testing.T) {
+	search := &SavedSearch{Name: "Велосипеды", Filter: map[string]string{"q": "велосипед", "currency": "RUB"}}
+	if err := search.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		search SavedSearch
+		field  string
+	}{
+		{"empty name", SavedSearch{Name: "  ", Filter: map[string]string{"q": "bike"}}, "name"},
+		{"long name", SavedSearch{Name: strings.Repeat("я", 101), Filter: map[string]string{"q": "bike"}}, "name"},
+		{"no filters", SavedSearch{Name: "Все", Filter: map[string]string{}}, "query"},
+		{"only currency", SavedSearch{Name: "Все", Filter: map[string]string{"currency": "USD"}}, "query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.search.Validate()
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("Validate() error = %v, want validation error", err)
+			}
+			violations := apperror.ViolationsOf(err)
+			if len(violations) != 1 || violations[0].Field != tt.field {
+				t.Errorf("violations = %v, want one for %s", violations, tt.field)
+			}
+		})
+	}
+}
+
+func TestSavedSearchFilter(t *testing.T) {
+	got := SavedSearchFilter(map[string]string{"q": "bike", "status": "sold", "tag": "kids"})
+	want := map[string]string{"q": "bike", "tag": "kids"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SavedSearchFilter() = %v, want %v", got, want)
+	}
+}
@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"fmt"
+	"marketplace/internal/apperror"
 	"regexp"
 	"strings"
 	"time"
@@ -9,16 +9,34 @@ import (
 	"github.com/google/uuid"
 )
 
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID             uuid.UUID `json:"id"`
 	Username       string    `json:"username"`
 	HashedPassword string    `json:"-"`
+	Role           Role      `json:"role"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
 type UserDTO struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -26,25 +44,29 @@ func (u *User) ToDTO() *UserDTO {
 	return &UserDTO{
 		ID:        u.ID,
 		Username:  u.Username,
+		Role:      u.Role,
 		CreatedAt: u.CreatedAt,
 	}
 }
 
 func (u *User) Validate() error {
-	if u.Username == "" {
-		return fmt.Errorf("username can't be empty")
-	}
-	if len(strings.TrimSpace(u.Username)) < 3 {
-		return fmt.Errorf("username must be at least 3 characters long")
-	}
-	if len(u.Username) > 50 {
-		return fmt.Errorf("username must not exceed 50 characters")
-	}
+	var v apperror.Violations
 
 	validUsername := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
-	if !validUsername.MatchString(u.Username) {
-		return fmt.Errorf("username can only contain letters, digits, and underscores")
+	switch {
+	case u.Username == "":
+		v.Add("username", "username can't be empty")
+	case len(strings.TrimSpace(u.Username)) < 3:
+		v.Add("username", "username must be at least 3 characters long")
+	case len(u.Username) > 50:
+		v.Add("username", "username must not exceed 50 characters")
+	case !validUsername.MatchString(u.Username):
+		v.Add("username", "username can only contain letters, digits, and underscores")
+	}
+
+	if !u.Role.IsValid() {
+		v.Add("role", "invalid role %q", u.Role)
 	}
 
-	return nil
+	return v.Err()
 }
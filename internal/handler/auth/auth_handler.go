@@ -1,8 +1,16 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"marketplace/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
 
 type AuthHandlerInterface interface {
 	AuthMiddleware() gin.HandlerFunc
-	OwnerMiddleware(paramID string) gin.HandlerFunc
+	OptionalAuthMiddleware() gin.HandlerFunc
+	RequireRole(roles ...entity.Role) gin.HandlerFunc
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	JWKS(c *gin.Context)
 }
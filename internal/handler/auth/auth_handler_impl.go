@@ -1,10 +1,12 @@
 package handler
 
 import (
-	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
 	service "marketplace/internal/service/auth"
+	"marketplace/internal/usecase/policy"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,59 +27,97 @@ func NewAuthHandler(authSvc service.AuthServiceInterface, logger *logrus.Logger)
 
 func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			h.logger.Warn("Authorization header is missing")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			return
-		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			h.logger.Warn("Invalid Authorization header format")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
+		if _, err := Authenticate(c, h.authSvc, h.logger, false); err != nil {
+			c.Error(err)
+			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		userID, err := h.authSvc.ValidateJWT(parts[1])
-		if err != nil {
-			h.logger.WithError(err).Error("Failed to validate JWT")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+// OptionalAuthMiddleware для публичных маршрутов: без заголовка Authorization
+// запрос проходит анонимно, а переданный токен проверяется как в AuthMiddleware.
+func (h *AuthHandler) OptionalAuthMiddleware() gin.HandlerFunc {
+	required := h.AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
-
-		ctx := context.WithValue(c.Request.Context(), "user_id", userID)
-		c.Request = c.Request.WithContext(ctx)
-		c.Next()
+		required(c)
 	}
 }
 
-func (h *AuthHandler) OwnerMiddleware(paramID string) gin.HandlerFunc {
+// RequireRole пропускает только пользователей с одной из указанных ролей.
+func (h *AuthHandler) RequireRole(roles ...entity.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, ok := c.Request.Context().Value("user_id").(uuid.UUID)
+		actor, ok := policy.ActorFromContext(c.Request.Context())
 		if !ok {
 			h.logger.Error("Failed to get user_id from context")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Error(apperror.Unauthorized("unauthorized"))
+			c.Abort()
 			return
 		}
 
-		idStr := c.Param(paramID)
-		resourceID, err := uuid.Parse(idStr)
-		if err != nil {
-			h.logger.WithError(err).Error("Invalid resource ID")
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
-			return
+		for _, role := range roles {
+			if actor.Role == role {
+				c.Next()
+				return
+			}
 		}
 
-		if userID != resourceID {
-			h.logger.WithFields(logrus.Fields{
-				"user_id":     userID,
-				"resource_id": resourceID,
-			}).Warn("User is not the owner of the resource")
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
-			return
-		}
+		h.logger.WithFields(logrus.Fields{
+			"user_id": actor.UserID,
+			"role":    actor.Role,
+		}).Warn("User role is not allowed for the resource")
+		c.Error(apperror.Forbidden("forbidden"))
+		c.Abort()
+	}
+}
 
-		c.Next()
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid refresh request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	tokens, err := h.authSvc.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to refresh tokens")
+		c.Error(err)
+		return
 	}
+
+	h.logger.Info("Tokens refreshed via handler")
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID, ok := c.Request.Context().Value("session_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get session_id from context")
+		c.Error(apperror.Unauthorized("unauthorized"))
+		return
+	}
+
+	if err := h.authSvc.Logout(c.Request.Context(), sessionID); err != nil {
+		h.logger.WithError(err).Error("Failed to logout")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"session_id": sessionID,
+	}).Info("User logged out via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authSvc.JWKS())
 }
@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -17,16 +24,22 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) ValidateJWT(token string) (uuid.UUID, error) {
+func (m *MockAuthService) ValidateJWT(token string) (*entity.TokenClaims, error) {
 	args := m.Called(token)
-	return args.Get(0).(uuid.UUID), args.Error(1)
+	claims, _ := args.Get(0).(*entity.TokenClaims)
+	return claims, args.Error(1)
 }
 
-func (m *MockAuthService) GenerateJWT(userID uuid.UUID) (string, error) {
-	args := m.Called(userID)
+func (m *MockAuthService) GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error) {
+	args := m.Called(userID, sessionID, role)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthService) JWKS() *entity.JSONWebKeySet {
+	args := m.Called()
+	return args.Get(0).(*entity.JSONWebKeySet)
+}
+
 func (m *MockAuthService) GeneratePasswordHash(password string) (string, error) {
 	args := m.Called(password)
 	return args.String(0), args.Error(1)
@@ -37,6 +50,22 @@ func (m *MockAuthService) VerifyPassword(hashedPassword, inputPassword string) e
 	return args.Error(0)
 }
 
+func (m *MockAuthService) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	tokens, _ := args.Get(0).(*entity.TokenPair)
+	return tokens, args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
@@ -44,9 +73,10 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	mockAuthSvc := new(MockAuthService)
 	logger := logrus.New()
 	authHandler := NewAuthHandler(mockAuthSvc, logger)
+	r.Use(httperror.Middleware(logger))
 
 	// Setup mock for invalid token
-	mockAuthSvc.On("ValidateJWT", "invalid_token").Return(uuid.Nil, fmt.Errorf("invalid token"))
+	mockAuthSvc.On("ValidateJWT", "invalid_token").Return(nil, fmt.Errorf("invalid token"))
 
 	r.Use(authHandler.AuthMiddleware())
 	r.GET("/protected", func(c *gin.Context) {
@@ -69,10 +99,13 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 
 	mockAuthSvc := new(MockAuthService)
 	validUserID := uuid.New()
-	mockAuthSvc.On("ValidateJWT", "valid_token").Return(validUserID, nil)
+	sessionID := uuid.New()
+	mockAuthSvc.On("ValidateJWT", "valid_token").Return(&entity.TokenClaims{UserID: validUserID, SessionID: sessionID}, nil)
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(false, nil)
 
 	logger := logrus.New()
 	authHandler := NewAuthHandler(mockAuthSvc, logger)
+	r.Use(httperror.Middleware(logger))
 
 	r.Use(authHandler.AuthMiddleware())
 	r.GET("/protected", func(c *gin.Context) {
@@ -91,3 +124,131 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockAuthSvc.AssertExpectations(t)
 }
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockAuthSvc := new(MockAuthService)
+	sessionID := uuid.New()
+	mockAuthSvc.On("ValidateJWT", "revoked_token").Return(&entity.TokenClaims{UserID: uuid.New(), SessionID: sessionID}, nil)
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(true, nil)
+
+	logger := logrus.New()
+	authHandler := NewAuthHandler(mockAuthSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.Use(authHandler.AuthMiddleware())
+	r.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, "success")
+	})
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer revoked_token")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockAuthSvc.AssertExpectations(t)
+}
+
+func TestRefreshHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockAuthSvc := new(MockAuthService)
+	logger := logrus.New()
+	authHandler := NewAuthHandler(mockAuthSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.POST("/auth/refresh", authHandler.Refresh)
+
+	tokens := &entity.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh", ExpiresIn: 900}
+	mockAuthSvc.On("RefreshTokens", mock.Anything, "old-refresh").Return(tokens, nil)
+	mockAuthSvc.On("RefreshTokens", mock.Anything, "reused-refresh").Return(nil, apperror.Unauthorized("refresh token reuse detected, session revoked"))
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": "old-refresh"})
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got entity.TokenPair
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, *tokens, got)
+
+	body, _ = json.Marshal(map[string]string{"refresh_token": "reused-refresh"})
+	req, _ = http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockAuthSvc.AssertExpectations(t)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockAuthSvc := new(MockAuthService)
+	logger := logrus.New()
+	authHandler := NewAuthHandler(mockAuthSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
+
+	jwks := &entity.JSONWebKeySet{Keys: []entity.JSONWebKey{{Kty: "OKP", Kid: "2025-01", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "abc"}}}
+	mockAuthSvc.On("JWKS").Return(jwks)
+
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got entity.JSONWebKeySet
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, *jwks, got)
+	mockAuthSvc.AssertExpectations(t)
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuthSvc := new(MockAuthService)
+	logger := logrus.New()
+	authHandler := NewAuthHandler(mockAuthSvc, logger)
+
+	tests := []struct {
+		name   string
+		role   entity.Role
+		status int
+	}{
+		{"admin allowed", entity.RoleAdmin, http.StatusOK},
+		{"moderator forbidden", entity.RoleModerator, http.StatusForbidden},
+		{"user forbidden", entity.RoleUser, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(httperror.Middleware(logger))
+			r.Use(func(c *gin.Context) {
+				ctx := context.WithValue(c.Request.Context(), "user_id", uuid.New())
+				ctx = context.WithValue(ctx, "user_role", tt.role)
+				c.Request = c.Request.WithContext(ctx)
+			})
+			r.GET("/admin", authHandler.RequireRole(entity.RoleAdmin), func(c *gin.Context) {
+				c.JSON(http.StatusOK, "success")
+			})
+
+			req, _ := http.NewRequest("GET", "/admin", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	service "marketplace/internal/service/auth"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Authenticate проверяет access-токен запроса и сессию, которой он выдан, и
+// кладёт пользователя в контекст запроса. С allowQuery токен принимается и в
+// параметре access_token: браузер не может передать заголовок Authorization
+// при открытии WebSocket.
+func Authenticate(c *gin.Context, authSvc service.AuthServiceInterface, logger *logrus.Logger, allowQuery bool) (*entity.TokenClaims, error) {
+	token, err := requestToken(c, logger, allowQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := authSvc.ValidateJWT(token)
+	if err != nil {
+		logger.WithError(err).Error("Failed to validate JWT")
+		return nil, apperror.Unauthorized("invalid token")
+	}
+
+	revoked, err := authSvc.IsSessionRevoked(c.Request.Context(), claims.SessionID)
+	if err != nil || revoked {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"user_id":    claims.UserID,
+			"session_id": claims.SessionID,
+		}).Warn("Token session is revoked")
+		return nil, apperror.Unauthorized("token has been revoked")
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "user_id", claims.UserID)
+	ctx = context.WithValue(ctx, "session_id", claims.SessionID)
+	ctx = context.WithValue(ctx, "user_role", claims.Role)
+	c.Request = c.Request.WithContext(ctx)
+	return claims, nil
+}
+
+func requestToken(c *gin.Context, logger *logrus.Logger, allowQuery bool) (string, error) {
+	header := c.GetHeader("Authorization")
+	if header == "" && allowQuery {
+		if token := c.Query("access_token"); token != "" {
+			return token, nil
+		}
+		logger.Warn("Access token is missing")
+		return "", apperror.Unauthorized("access token is required")
+	}
+	if header == "" {
+		logger.Warn("Authorization header is missing")
+		return "", apperror.Unauthorized("authorization header is required")
+	}
+
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		logger.Warn("Invalid Authorization header format")
+		return "", apperror.Unauthorized("invalid Authorization header format")
+	}
+	return parts[1], nil
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type CategoryHandlerInterface interface {
+	CreateCategory(c *gin.Context)
+	GetCategory(c *gin.Context)
+	ListCategories(c *gin.Context)
+	UpdateCategory(c *gin.Context)
+	DeleteCategory(c *gin.Context)
+}
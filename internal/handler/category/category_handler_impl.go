@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/category"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryHandler struct {
+	categorySvc service.CategoryServiceInterface
+	logger      *logrus.Logger
+}
+
+func NewCategoryHandler(categorySvc service.CategoryServiceInterface, logger *logrus.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categorySvc: categorySvc,
+		logger:      logger,
+	}
+}
+
+type categoryRequest struct {
+	Name       string             `json:"name" binding:"required,max=100"`
+	Slug       string             `json:"slug" binding:"required,max=100"`
+	ParentID   *uuid.UUID         `json:"parent_id"`
+	Attributes []entity.Attribute `json:"attributes"`
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid create category request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	category, err := h.categorySvc.CreateCategory(c.Request.Context(), req.Name, req.Slug, req.ParentID, req.Attributes)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create category")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"category_id": category.ID,
+	}).Info("Category created via handler")
+	c.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid category ID")
+		c.Error(apperror.Validation("invalid category ID"))
+		return
+	}
+
+	category, err := h.categorySvc.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get category")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category fetched via handler")
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.categorySvc.ListCategories(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list categories")
+		c.Error(err)
+		return
+	}
+
+	h.logger.Info("Categories listed via handler")
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid update category request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid category ID")
+		c.Error(apperror.Validation("invalid category ID"))
+		return
+	}
+
+	category, err := h.categorySvc.UpdateCategory(c.Request.Context(), id, req.Name, req.Slug, req.ParentID, req.Attributes)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update category")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category updated via handler")
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid category ID")
+		c.Error(apperror.Validation("invalid category ID"))
+		return
+	}
+
+	if err := h.categorySvc.DeleteCategory(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to delete category")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category deleted via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) CreateCategory(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	args := m.Called(ctx, name, slug, parentID, attributes)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategory(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	args := m.Called(ctx, id)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryService) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	args := m.Called(ctx, id, name, slug, parentID, attributes)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateCategoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockCategorySvc := new(MockCategoryService)
+	logger := logrus.New()
+	handler := NewCategoryHandler(mockCategorySvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.POST("/categories", handler.CreateCategory)
+
+	parentID := uuid.New()
+	attributes := []entity.Attribute{{Name: "condition", Label: "Состояние", Type: entity.AttributeEnum, Options: []string{"new", "used"}}}
+	expected := &entity.Category{ID: uuid.New(), ParentID: &parentID, Name: "Bicycles", Slug: "bicycles", Attributes: attributes}
+	mockCategorySvc.On("CreateCategory", mock.Anything, "Bicycles", "bicycles", &parentID, attributes).Return(expected, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "Bicycles", "slug": "bicycles", "parent_id": parentID, "attributes": attributes})
+	req, _ := http.NewRequest("POST", "/categories", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockCategorySvc.AssertExpectations(t)
+}
+
+func TestGetCategoryHandler_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockCategorySvc := new(MockCategoryService)
+	logger := logrus.New()
+	handler := NewCategoryHandler(mockCategorySvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/categories/:id", handler.GetCategory)
+
+	id := uuid.New()
+	mockCategorySvc.On("GetCategory", mock.Anything, id).Return(nil, apperror.NotFound("category not found"))
+
+	req, _ := http.NewRequest("GET", "/categories/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockCategorySvc.AssertExpectations(t)
+}
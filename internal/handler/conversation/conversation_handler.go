@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type ConversationHandlerInterface interface {
+	StartConversation(c *gin.Context)
+	ListConversations(c *gin.Context)
+	GetConversation(c *gin.Context)
+	SendMessage(c *gin.Context)
+	ListMessages(c *gin.Context)
+	MarkConversationRead(c *gin.Context)
+	BlockUser(c *gin.Context)
+	UnblockUser(c *gin.Context)
+	ListBlockedUsers(c *gin.Context)
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/conversation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type messageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type ConversationHandler struct {
+	conversationSvc service.ConversationServiceInterface
+	logger          *logrus.Logger
+}
+
+func NewConversationHandler(conversationSvc service.ConversationServiceInterface, logger *logrus.Logger) *ConversationHandler {
+	return &ConversationHandler{
+		conversationSvc: conversationSvc,
+		logger:          logger,
+	}
+}
+
+// StartConversation отправляет автору поста сообщение, начиная переписку
+// или продолжая уже существующую.
+func (h *ConversationHandler) StartConversation(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	var req messageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid start conversation request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	conversation, message, err := h.conversationSvc.StartConversation(c.Request.Context(), postID, req.Body)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to start conversation")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"conversation_id": conversation.ID,
+		"post_id":         postID,
+	}).Info("Conversation started via handler")
+	c.JSON(http.StatusCreated, gin.H{
+		"conversation": conversation,
+		"message":      message,
+	})
+}
+
+func (h *ConversationHandler) ListConversations(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+
+	conversations, total, err := h.conversationSvc.ListConversations(c.Request.Context(), page, pageSize)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list conversations")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	}).Info("Conversations listed via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"conversations": conversations,
+		"total":         total,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+func (h *ConversationHandler) GetConversation(c *gin.Context) {
+	id, ok := h.conversationID(c)
+	if !ok {
+		return
+	}
+
+	conversation, err := h.conversationSvc.GetConversation(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get conversation")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+	}).Info("Conversation fetched via handler")
+	c.JSON(http.StatusOK, conversation)
+}
+
+func (h *ConversationHandler) SendMessage(c *gin.Context) {
+	id, ok := h.conversationID(c)
+	if !ok {
+		return
+	}
+
+	var req messageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid send message request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	message, err := h.conversationSvc.SendMessage(c.Request.Context(), id, req.Body)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to send message")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"message_id":      message.ID,
+	}).Info("Message sent via handler")
+	c.JSON(http.StatusCreated, message)
+}
+
+// ListMessages отдаёт сообщения, начиная с последних. Чтобы получить более
+// ранние, клиент передаёт next_cursor из предыдущего ответа в cursor.
+func (h *ConversationHandler) ListMessages(c *gin.Context) {
+	id, ok := h.conversationID(c)
+	if !ok {
+		return
+	}
+
+	var cursor *entity.MessageCursor
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := entity.ParseMessageCursor(raw)
+		if err != nil {
+			h.logger.WithError(err).Error("Invalid message cursor")
+			c.Error(err)
+			return
+		}
+		cursor = &parsed
+	}
+
+	limit := entity.DefaultMessagePageSize
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			h.logger.WithError(err).Error("Invalid message limit")
+			c.Error(apperror.InvalidField("limit", "limit must be an integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	messages, next, err := h.conversationSvc.ListMessages(c.Request.Context(), id, cursor, limit)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list messages")
+		c.Error(err)
+		return
+	}
+
+	var nextCursor *string
+	if next != nil {
+		s := next.String()
+		nextCursor = &s
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"count":           len(messages),
+	}).Info("Messages listed via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"messages":    messages,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (h *ConversationHandler) MarkConversationRead(c *gin.Context) {
+	id, ok := h.conversationID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.conversationSvc.MarkConversationRead(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to mark conversation read")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"count":           count,
+	}).Info("Conversation marked as read via handler")
+	c.JSON(http.StatusOK, gin.H{"read": count})
+}
+
+func (h *ConversationHandler) BlockUser(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.conversationSvc.BlockUser(c.Request.Context(), userID); err != nil {
+		h.logger.WithError(err).Error("Failed to block user")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"blocked_user_id": userID,
+	}).Info("User blocked via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+func (h *ConversationHandler) UnblockUser(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.conversationSvc.UnblockUser(c.Request.Context(), userID); err != nil {
+		h.logger.WithError(err).Error("Failed to unblock user")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"blocked_user_id": userID,
+	}).Info("User unblocked via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
+
+func (h *ConversationHandler) ListBlockedUsers(c *gin.Context) {
+	blocked, err := h.conversationSvc.ListBlockedUsers(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list blocked users")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"count": len(blocked),
+	}).Info("Blocked users listed via handler")
+	c.JSON(http.StatusOK, gin.H{"blocked_users": blocked})
+}
+
+func (h *ConversationHandler) conversationID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid conversation ID")
+		c.Error(apperror.Validation("invalid conversation ID"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *ConversationHandler) userID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid user ID")
+		c.Error(apperror.Validation("invalid user ID"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
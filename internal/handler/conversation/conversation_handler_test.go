@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConversationService struct {
+	mock.Mock
+}
+
+func (m *MockConversationService) StartConversation(ctx context.Context, postID uuid.UUID, body string) (*entity.Conversation, *entity.Message, error) {
+	args := m.Called(ctx, postID, body)
+	conversation, _ := args.Get(0).(*entity.Conversation)
+	message, _ := args.Get(1).(*entity.Message)
+	return conversation, message, args.Error(2)
+}
+
+func (m *MockConversationService) ListConversations(ctx context.Context, page, pageSize int) ([]*entity.Conversation, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]*entity.Conversation), args.Int(1), args.Error(2)
+}
+
+func (m *MockConversationService) GetConversation(ctx context.Context, id uuid.UUID) (*entity.Conversation, error) {
+	args := m.Called(ctx, id)
+	conversation, _ := args.Get(0).(*entity.Conversation)
+	return conversation, args.Error(1)
+}
+
+func (m *MockConversationService) SendMessage(ctx context.Context, id uuid.UUID, body string) (*entity.Message, error) {
+	args := m.Called(ctx, id, body)
+	message, _ := args.Get(0).(*entity.Message)
+	return message, args.Error(1)
+}
+
+func (m *MockConversationService) ListMessages(ctx context.Context, id uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, *entity.MessageCursor, error) {
+	args := m.Called(ctx, id, cursor, limit)
+	next, _ := args.Get(1).(*entity.MessageCursor)
+	return args.Get(0).([]*entity.Message), next, args.Error(2)
+}
+
+func (m *MockConversationService) MarkConversationRead(ctx context.Context, id uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockConversationService) BlockUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockConversationService) UnblockUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockConversationService) ListBlockedUsers(ctx context.Context) ([]*entity.BlockedUser, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entity.BlockedUser), args.Error(1)
+}
+
+func TestStartConversationHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockConversationSvc := new(MockConversationService)
+	logger := logrus.New()
+	handler := NewConversationHandler(mockConversationSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.POST("/posts/:id/conversations", handler.StartConversation)
+
+	postID := uuid.New()
+	conversation := &entity.Conversation{ID: uuid.New(), PostID: postID}
+	message := &entity.Message{ID: uuid.New(), ConversationID: conversation.ID, Body: "Ещё продаёте?"}
+	mockConversationSvc.On("StartConversation", mock.Anything, postID, "Ещё продаёте?").Return(conversation, message, nil)
+
+	body, _ := json.Marshal(map[string]string{"body": "Ещё продаёте?"})
+	req, _ := http.NewRequest("POST", "/posts/"+postID.String()+"/conversations", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp struct {
+		Conversation entity.Conversation `json:"conversation"`
+		Message      entity.Message      `json:"message"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, conversation.ID, resp.Conversation.ID)
+	assert.Equal(t, message.ID, resp.Message.ID)
+
+	req, _ = http.NewRequest("POST", "/posts/"+postID.String()+"/conversations", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockConversationSvc.AssertNumberOfCalls(t, "StartConversation", 1)
+}
+
+func TestListMessagesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockConversationSvc := new(MockConversationService)
+	logger := logrus.New()
+	handler := NewConversationHandler(mockConversationSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/conversations/:id/messages", handler.ListMessages)
+
+	id := uuid.New()
+	cursor := entity.MessageCursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}
+	next := entity.MessageCursor{CreatedAt: cursor.CreatedAt.Add(-time.Hour), ID: uuid.New()}
+	messages := []*entity.Message{{ID: next.ID, ConversationID: id, CreatedAt: next.CreatedAt}}
+	mockConversationSvc.On("ListMessages", mock.Anything, id, &cursor, 1).Return(messages, &next, nil)
+
+	req, _ := http.NewRequest("GET", "/conversations/"+id.String()+"/messages?limit=1&cursor="+cursor.String(), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Messages   []*entity.Message `json:"messages"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Messages, 1)
+	assert.Equal(t, next.String(), resp.NextCursor)
+
+	req, _ = http.NewRequest("GET", "/conversations/"+id.String()+"/messages?cursor=garbage!", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockConversationSvc.AssertNumberOfCalls(t, "ListMessages", 1)
+}
@@ -0,0 +1,10 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type ExchangeRateHandlerInterface interface {
+	ListExchangeRates(c *gin.Context)
+	SetExchangeRate(c *gin.Context)
+	ImportExchangeRates(c *gin.Context)
+	DeleteExchangeRate(c *gin.Context)
+}
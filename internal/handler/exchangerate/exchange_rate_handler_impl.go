@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/exchangerate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportSize ограничивает размер CSV с курсами.
+const maxImportSize = 1 << 20
+
+type ExchangeRateHandler struct {
+	rateSvc service.ExchangeRateServiceInterface
+	logger  *logrus.Logger
+}
+
+func NewExchangeRateHandler(rateSvc service.ExchangeRateServiceInterface, logger *logrus.Logger) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		rateSvc: rateSvc,
+		logger:  logger,
+	}
+}
+
+func (h *ExchangeRateHandler) ListExchangeRates(c *gin.Context) {
+	rates, err := h.rateSvc.ListExchangeRates(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list exchange rates")
+		c.Error(err)
+		return
+	}
+
+	h.logger.Info("Exchange rates listed via handler")
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
+
+func (h *ExchangeRateHandler) SetExchangeRate(c *gin.Context) {
+	var req struct {
+		// Курс можно передать числом или строкой: "92.5".
+		Rate json.Number `json:"rate" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid set exchange rate request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	base, quote, err := currencyPair(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid currency pair")
+		c.Error(err)
+		return
+	}
+
+	rate, err := h.rateSvc.SetExchangeRate(c.Request.Context(), base, quote, req.Rate.String())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to set exchange rate")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"base":  base,
+		"quote": quote,
+	}).Info("Exchange rate set via handler")
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *ExchangeRateHandler) ImportExchangeRates(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	count, err := h.rateSvc.ImportExchangeRates(c.Request.Context(), body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = apperror.TooLarge("CSV must not exceed %d bytes", maxImportSize)
+		}
+		h.logger.WithError(err).Error("Failed to import exchange rates")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"count": count,
+	}).Info("Exchange rates imported via handler")
+	c.JSON(http.StatusOK, gin.H{"imported": count})
+}
+
+func (h *ExchangeRateHandler) DeleteExchangeRate(c *gin.Context) {
+	base, quote, err := currencyPair(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid currency pair")
+		c.Error(err)
+		return
+	}
+
+	if err := h.rateSvc.DeleteExchangeRate(c.Request.Context(), base, quote); err != nil {
+		h.logger.WithError(err).Error("Failed to delete exchange rate")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"base":  base,
+		"quote": quote,
+	}).Info("Exchange rate deleted via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rate deleted successfully"})
+}
+
+// currencyPair разбирает валюты из пути /exchange-rates/:base/:quote.
+func currencyPair(c *gin.Context) (entity.Currency, entity.Currency, error) {
+	base, baseErr := entity.ParseCurrency(c.Param("base"))
+	quote, quoteErr := entity.ParseCurrency(c.Param("quote"))
+	var v apperror.Violations
+	if baseErr != nil {
+		v.Add("base", "unsupported currency %q", c.Param("base"))
+	}
+	if quoteErr != nil {
+		v.Add("quote", "unsupported currency %q", c.Param("quote"))
+	}
+	return base, quote, v.Err()
+}
@@ -0,0 +1,93 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/pkg/mergepatch"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// В ошибках валидатора поля называются так же, как в JSON запроса.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" || name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// Binding переводит ошибку ShouldBindJSON в ошибку валидации с нарушениями
+// по полям.
+func Binding(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		var v apperror.Violations
+		for _, fe := range validationErrs {
+			v.Add(fe.Field(), "%s", fieldMessage(fe))
+		}
+		return v.Err()
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperror.InvalidField(typeErr.Field, "%s must be of type %s", typeErr.Field, typeErr.Type.Kind())
+	}
+
+	if errors.Is(err, io.EOF) {
+		return apperror.Validation("request body is empty")
+	}
+	return apperror.Validation("malformed JSON body")
+}
+
+// MergePatch переводит ошибку mergepatch.ReadRequest в доменную.
+func MergePatch(err error) error {
+	switch {
+	case errors.Is(err, mergepatch.ErrUnsupportedMediaType):
+		return apperror.UnsupportedMediaType("Content-Type must be %s", mergepatch.ContentType)
+	case errors.Is(err, mergepatch.ErrTooLarge):
+		return apperror.TooLarge("merge patch must not exceed %d bytes", mergepatch.MaxSize)
+	default:
+		return err
+	}
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	field := fe.Field()
+	isString := fe.Kind() == reflect.String
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "min":
+		if isString {
+			return fmt.Sprintf("%s must be at least %s characters long", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("%s must not exceed %s characters", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must not exceed %s", field, fe.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
@@ -0,0 +1,129 @@
+package httperror
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem — тело ответа с ошибкой в формате RFC 7807.
+type Problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Errors   []apperror.Violation `json:"errors,omitempty"`
+}
+
+// Status переводит вид доменной ошибки в HTTP-статус.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, apperror.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperror.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperror.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, apperror.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperror.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, apperror.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, apperror.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// problemType возвращает URI типа проблемы для статуса.
+func problemType(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "/problems/validation-error"
+	case http.StatusUnauthorized:
+		return "/problems/unauthorized"
+	case http.StatusForbidden:
+		return "/problems/forbidden"
+	case http.StatusNotFound:
+		return "/problems/not-found"
+	case http.StatusConflict:
+		return "/problems/conflict"
+	case http.StatusRequestEntityTooLarge:
+		return "/problems/payload-too-large"
+	case http.StatusUnsupportedMediaType:
+		return "/problems/unsupported-media-type"
+	case http.StatusPreconditionFailed:
+		return "/problems/precondition-failed"
+	case http.StatusPreconditionRequired:
+		return "/problems/precondition-required"
+	case http.StatusServiceUnavailable:
+		return "/problems/service-unavailable"
+	default:
+		return "about:blank"
+	}
+}
+
+// NewProblem собирает тело ответа для ошибки. Текст непредвиденных ошибок
+// клиенту не показывается.
+func NewProblem(err error, instance string) *Problem {
+	status := Status(err)
+	problem := &Problem{
+		Type:     problemType(status),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: instance,
+	}
+	if status == http.StatusInternalServerError {
+		return problem
+	}
+
+	problem.Detail, _ = apperror.Message(err)
+	if violations := apperror.ViolationsOf(err); len(violations) > 0 {
+		problem.Detail = "request contains invalid fields"
+		problem.Errors = violations
+	}
+	return problem
+}
+
+// Middleware отвечает клиенту по последней ошибке, добавленной обработчиком
+// через c.Error.
+func Middleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := NewProblem(err, c.Request.URL.Path)
+
+		entry := logger.WithError(err).WithFields(logrus.Fields{
+			"status": problem.Status,
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+		})
+		if problem.Status == http.StatusInternalServerError {
+			entry.Error("Request failed")
+		} else {
+			entry.Warn("Request rejected")
+		}
+
+		c.Header("Content-Type", ContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
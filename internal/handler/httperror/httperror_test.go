@@ -0,0 +1,139 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		detail string
+	}{
+		{"validation", apperror.Validation("invalid post ID"), http.StatusBadRequest, "invalid post ID"},
+		{"unauthorized", apperror.Unauthorized("invalid token"), http.StatusUnauthorized, "invalid token"},
+		{"forbidden", apperror.Forbidden("not allowed to edit the post"), http.StatusForbidden, "not allowed to edit the post"},
+		{"wrapped not found", fmt.Errorf("get post: %w", apperror.NotFound("post not found")), http.StatusNotFound, "post not found"},
+		{"conflict", apperror.Conflict("username already exists"), http.StatusConflict, "username already exists"},
+		{"too large", apperror.TooLarge("file must not exceed 10485760 bytes"), http.StatusRequestEntityTooLarge, "file must not exceed 10485760 bytes"},
+		{"precondition failed", apperror.PreconditionFailed("post has been modified"), http.StatusPreconditionFailed, "post has been modified"},
+		{"precondition required", apperror.PreconditionRequired("If-Match header is required"), http.StatusPreconditionRequired, "If-Match header is required"},
+		{"unsupported media type", apperror.UnsupportedMediaType("Content-Type must be application/merge-patch+json"), http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json"},
+		{"unavailable", apperror.Unavailable("too many post stream subscribers"), http.StatusServiceUnavailable, "too many post stream subscribers"},
+		{"internal error is hidden", errors.New("pq: connection refused"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Middleware(logger))
+			r.GET("/", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+			var problem Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, http.StatusText(tt.status), problem.Title)
+			assert.Equal(t, tt.detail, problem.Detail)
+			assert.Equal(t, "/", problem.Instance)
+		})
+	}
+}
+
+func TestMiddleware_Violations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	r := gin.New()
+	r.Use(Middleware(logger))
+	r.GET("/posts", func(c *gin.Context) {
+		post := &entity.Post{Header: "abc", Content: "", Image: "https://example.com/a.png", Price: entity.Money{Amount: -1, Currency: "RUB"}}
+		c.Error(fmt.Errorf("validate post: %w", post.Validate()))
+	})
+
+	req, _ := http.NewRequest("GET", "/posts", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "/problems/validation-error", problem.Type)
+	assert.Equal(t, "/posts", problem.Instance)
+	assert.Equal(t, []apperror.Violation{
+		{Field: "header", Message: "header must be at least 5 characters long"},
+		{Field: "content", Message: "content can't be empty"},
+		{Field: "price", Message: "price must be positive"},
+	}, problem.Errors)
+}
+
+func TestBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var req struct {
+		Username string  `json:"username" binding:"required,min=3,max=50"`
+		Price    float64 `json:"price" binding:"required,gt=0"`
+		Role     string  `json:"role" binding:"omitempty,oneof=user admin"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		violations []apperror.Violation
+	}{
+		{
+			name: "field rules",
+			body: `{"username":"ab","role":"root"}`,
+			violations: []apperror.Violation{
+				{Field: "username", Message: "username must be at least 3 characters long"},
+				{Field: "price", Message: "price is required"},
+				{Field: "role", Message: "role must be one of: user, admin"},
+			},
+		},
+		{
+			name:       "wrong type",
+			body:       `{"username":"alice","price":"free"}`,
+			violations: []apperror.Violation{{Field: "price", Message: "price must be of type float64"}},
+		},
+		{
+			name: "malformed body",
+			body: `{"username":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("POST", "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			err := Binding(c.ShouldBindJSON(&req))
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Equal(t, tt.violations, apperror.ViolationsOf(err))
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type ImageHandlerInterface interface {
+	UploadImage(c *gin.Context)
+	GetImage(c *gin.Context)
+	GetImageVariant(c *gin.Context)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	service "marketplace/internal/service/image"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// multipartOverhead — запас на заголовки multipart сверх размера самого файла.
+const multipartOverhead = 64 << 10
+
+// Файл по идентификатору никогда не меняется, поэтому его можно кэшировать навсегда.
+var immutableHeaders = map[string]string{
+	"Cache-Control":          "public, max-age=31536000, immutable",
+	"X-Content-Type-Options": "nosniff",
+}
+
+type ImageHandler struct {
+	imageSvc service.ImageServiceInterface
+	maxSize  int64
+	logger   *logrus.Logger
+}
+
+func NewImageHandler(imageSvc service.ImageServiceInterface, maxSize int64, logger *logrus.Logger) *ImageHandler {
+	return &ImageHandler{
+		imageSvc: imageSvc,
+		maxSize:  maxSize,
+		logger:   logger,
+	}
+}
+
+func (h *ImageHandler) UploadImage(c *gin.Context) {
+	userID, ok := c.Request.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user_id from context")
+		c.Error(apperror.Unauthorized("unauthorized"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid upload image request")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Error(apperror.TooLarge("file must not exceed %d bytes", h.maxSize))
+			return
+		}
+		c.Error(apperror.InvalidField("file", "file is required"))
+		return
+	}
+	if fileHeader.Size > h.maxSize {
+		c.Error(apperror.TooLarge("file must not exceed %d bytes", h.maxSize))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to open uploaded file")
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxSize+1))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to read uploaded file")
+		c.Error(err)
+		return
+	}
+
+	image, err := h.imageSvc.UploadImage(c.Request.Context(), userID, data)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to upload image")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"image_id": image.ID,
+		"owner_id": userID,
+	}).Info("Image uploaded via handler")
+	c.JSON(http.StatusCreated, image)
+}
+
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid image ID")
+		c.Error(apperror.Validation("invalid image ID"))
+		return
+	}
+
+	image, reader, err := h.imageSvc.GetImage(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get image")
+		c.Error(err)
+		return
+	}
+	defer reader.Close()
+
+	h.logger.WithFields(logrus.Fields{
+		"image_id": id,
+	}).Info("Image fetched via handler")
+
+	c.DataFromReader(http.StatusOK, image.Size, image.ContentType, reader, immutableHeaders)
+}
+
+func (h *ImageHandler) GetImageVariant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid image ID")
+		c.Error(apperror.Validation("invalid image ID"))
+		return
+	}
+	width, format, ok := entity.ParseVariantName(c.Param("variant"))
+	if !ok {
+		h.logger.WithField("variant", c.Param("variant")).Error("Invalid image variant")
+		c.Error(apperror.NotFound("image variant not found"))
+		return
+	}
+
+	variant, reader, err := h.imageSvc.GetImageVariant(c.Request.Context(), id, width, format)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get image variant")
+		c.Error(err)
+		return
+	}
+	defer reader.Close()
+
+	h.logger.WithFields(logrus.Fields{
+		"image_id": id,
+		"width":    width,
+		"format":   format,
+	}).Info("Image variant fetched via handler")
+	c.DataFromReader(http.StatusOK, variant.Size, variant.ContentType, reader, immutableHeaders)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImageService struct {
+	mock.Mock
+}
+
+func (m *MockImageService) UploadImage(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error) {
+	args := m.Called(ctx, ownerID, data)
+	image, _ := args.Get(0).(*entity.Image)
+	return image, args.Error(1)
+}
+
+func (m *MockImageService) GetImage(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error) {
+	args := m.Called(ctx, id)
+	image, _ := args.Get(0).(*entity.Image)
+	reader, _ := args.Get(1).(io.ReadCloser)
+	return image, reader, args.Error(2)
+}
+
+func (m *MockImageService) GetImageVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error) {
+	args := m.Called(ctx, id, width, format)
+	variant, _ := args.Get(0).(*entity.ImageVariant)
+	reader, _ := args.Get(1).(io.ReadCloser)
+	return variant, reader, args.Error(2)
+}
+
+func newUploadRequest(t *testing.T, field string, data []byte, userID uuid.UUID) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "photo.png")
+	assert.NoError(t, err)
+	part.Write(data)
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req.WithContext(context.WithValue(req.Context(), "user_id", userID))
+}
+
+func TestUploadImageHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	mockImageSvc := new(MockImageService)
+	handler := NewImageHandler(mockImageSvc, 1024, logger)
+	r := gin.New()
+	r.Use(httperror.Middleware(logger))
+	r.POST("/images", handler.UploadImage)
+
+	userID := uuid.New()
+	data := []byte("\x89PNG\r\n\x1a\nfake")
+	expected := &entity.Image{ID: uuid.New(), OwnerID: userID, ContentType: entity.ImagePNG, Size: int64(len(data))}
+	mockImageSvc.On("UploadImage", mock.Anything, userID, data).Return(expected, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUploadRequest(t, "file", data, userID))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockImageSvc.AssertExpectations(t)
+}
+
+func TestUploadImageHandler_Rejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	tests := []struct {
+		name   string
+		field  string
+		data   []byte
+		status int
+	}{
+		{"missing file", "photo", []byte("data"), http.StatusBadRequest},
+		{"too large", "file", bytes.Repeat([]byte("x"), 2048), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockImageSvc := new(MockImageService)
+			handler := NewImageHandler(mockImageSvc, 1024, logger)
+			r := gin.New()
+			r.Use(httperror.Middleware(logger))
+			r.POST("/images", handler.UploadImage)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newUploadRequest(t, tt.field, tt.data, uuid.New()))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, httperror.ContentType, w.Header().Get("Content-Type"))
+			mockImageSvc.AssertNotCalled(t, "UploadImage")
+		})
+	}
+}
+
+func TestGetImageHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	mockImageSvc := new(MockImageService)
+	handler := NewImageHandler(mockImageSvc, 1024, logger)
+	r := gin.New()
+	r.Use(httperror.Middleware(logger))
+	r.GET("/images/:id", handler.GetImage)
+
+	id := uuid.New()
+	image := &entity.Image{ID: id, ContentType: entity.ImageJPEG, Size: 4}
+	mockImageSvc.On("GetImage", mock.Anything, id).Return(image, io.NopCloser(strings.NewReader("jpeg")), nil)
+
+	req, _ := http.NewRequest("GET", "/images/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entity.ImageJPEG, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+	assert.Equal(t, "jpeg", w.Body.String())
+}
+
+func TestGetImageVariantHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	mockImageSvc := new(MockImageService)
+	handler := NewImageHandler(mockImageSvc, 1024, logger)
+	r := gin.New()
+	r.Use(httperror.Middleware(logger))
+	r.GET("/images/:id", handler.GetImage)
+	r.GET("/images/:id/:variant", handler.GetImageVariant)
+
+	id := uuid.New()
+	variant := &entity.ImageVariant{ImageID: id, Width: 320, Format: entity.VariantWebP, ContentType: "image/webp", Size: 4}
+	mockImageSvc.On("GetImageVariant", mock.Anything, id, 320, entity.VariantWebP).Return(variant, io.NopCloser(strings.NewReader("webp")), nil)
+
+	req, _ := http.NewRequest("GET", "/images/"+id.String()+"/w320.webp", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/webp", w.Header().Get("Content-Type"))
+	assert.Equal(t, "webp", w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/images/"+id.String()+"/w320.gif", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockImageSvc.AssertExpectations(t)
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type NotificationHandlerInterface interface {
+	ListNotifications(c *gin.Context)
+	MarkNotificationRead(c *gin.Context)
+	MarkAllNotificationsRead(c *gin.Context)
+	GetNotificationPreferences(c *gin.Context)
+	UpdateNotificationPreferences(c *gin.Context)
+}
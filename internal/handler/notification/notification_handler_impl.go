@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/notification"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationHandler struct {
+	notificationSvc service.NotificationServiceInterface
+	logger          *logrus.Logger
+}
+
+func NewNotificationHandler(notificationSvc service.NotificationServiceInterface, logger *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationSvc: notificationSvc,
+		logger:          logger,
+	}
+}
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, unread, err := h.notificationSvc.ListNotifications(c.Request.Context(), unreadOnly, page, pageSize)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list notifications")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+		"unread":    unread,
+	}).Info("Notifications listed via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"total":         total,
+		"unread_count":  unread,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid notification ID")
+		c.Error(apperror.Validation("invalid notification ID"))
+		return
+	}
+
+	if err := h.notificationSvc.MarkNotificationRead(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to mark notification read")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"notification_id": id,
+	}).Info("Notification marked as read via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	count, err := h.notificationSvc.MarkAllNotificationsRead(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to mark all notifications read")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"count": count,
+	}).Info("All notifications marked as read via handler")
+	c.JSON(http.StatusOK, gin.H{"read": count})
+}
+
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	preferences, err := h.notificationSvc.GetNotificationPreferences(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get notification preferences")
+		c.Error(err)
+		return
+	}
+
+	h.logger.Info("Notification preferences fetched via handler")
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
+
+// UpdateNotificationPreferences принимает {"post_favorited": false, ...}; виды,
+// которых нет в теле, не меняются.
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var req map[string]bool
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid update notification preferences request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	var violations apperror.Violations
+	preferences := make(entity.NotificationPreferences, len(req))
+	for key, enabled := range req {
+		kind, err := entity.ParseNotificationKind(key)
+		if err != nil {
+			violations.Add(key, "unknown notification kind %q", key)
+			continue
+		}
+		preferences[kind] = enabled
+	}
+	if err := violations.Err(); err != nil {
+		h.logger.WithError(err).Error("Invalid notification preferences")
+		c.Error(err)
+		return
+	}
+
+	updated, err := h.notificationSvc.UpdateNotificationPreferences(c.Request.Context(), preferences)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update notification preferences")
+		c.Error(err)
+		return
+	}
+
+	h.logger.Info("Notification preferences updated via handler")
+	c.JSON(http.StatusOK, gin.H{"preferences": updated})
+}
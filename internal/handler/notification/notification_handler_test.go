@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) ListNotifications(ctx context.Context, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, int, error) {
+	args := m.Called(ctx, unreadOnly, page, pageSize)
+	return args.Get(0).([]*entity.Notification), args.Int(1), args.Int(2), args.Error(3)
+}
+
+func (m *MockNotificationService) MarkNotificationRead(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) MarkAllNotificationsRead(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationService) GetNotificationPreferences(ctx context.Context) (entity.NotificationPreferences, error) {
+	args := m.Called(ctx)
+	preferences, _ := args.Get(0).(entity.NotificationPreferences)
+	return preferences, args.Error(1)
+}
+
+func (m *MockNotificationService) UpdateNotificationPreferences(ctx context.Context, preferences entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	args := m.Called(ctx, preferences)
+	updated, _ := args.Get(0).(entity.NotificationPreferences)
+	return updated, args.Error(1)
+}
+
+func TestListNotificationsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockNotificationSvc := new(MockNotificationService)
+	logger := logrus.New()
+	handler := NewNotificationHandler(mockNotificationSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/notifications", handler.ListNotifications)
+
+	notifications := []*entity.Notification{{ID: uuid.New(), Kind: entity.NotificationPostFavorited}}
+	mockNotificationSvc.On("ListNotifications", mock.Anything, true, 1, 20).Return(notifications, 4, 4, nil)
+
+	req, _ := http.NewRequest("GET", "/notifications?unread=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Notifications []*entity.Notification `json:"notifications"`
+		UnreadCount   int                    `json:"unread_count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Notifications, 1)
+	assert.Equal(t, 4, resp.UnreadCount)
+	mockNotificationSvc.AssertExpectations(t)
+}
+
+func TestUpdateNotificationPreferencesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockNotificationSvc := new(MockNotificationService)
+	logger := logrus.New()
+	handler := NewNotificationHandler(mockNotificationSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.PUT("/notifications/preferences", handler.UpdateNotificationPreferences)
+
+	preferences := entity.NotificationPreferences{entity.NotificationPostFavorited: false}
+	mockNotificationSvc.On("UpdateNotificationPreferences", mock.Anything, preferences).Return(preferences.WithDefaults(), nil)
+
+	body, _ := json.Marshal(map[string]bool{"post_favorited": false})
+	req, _ := http.NewRequest("PUT", "/notifications/preferences", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockNotificationSvc.AssertExpectations(t)
+
+	body, _ = json.Marshal(map[string]bool{"newsletter": false})
+	req, _ = http.NewRequest("PUT", "/notifications/preferences", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockNotificationSvc.AssertNumberOfCalls(t, "UpdateNotificationPreferences", 1)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postETag — сильный ETag ответа с постом: версия поста и хеш тела ответа.
+// Хеш меняется вместе со всем, что не меняет версию: избранным, именем
+// автора, копиями изображений и пересчитанной ценой.
+func postETag(post *entity.Post) string {
+	body, err := json.Marshal(post)
+	if err != nil {
+		return `"` + strconv.Itoa(post.Version) + `"`
+	}
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%d-%x"`, post.Version, sum[:8])
+}
+
+// setPostETag отдаёт ETag поста. Ответ зависит от пользователя (is_own_post,
+// is_favorited), поэтому кэши должны учитывать Authorization.
+func setPostETag(c *gin.Context, post *entity.Post) string {
+	etag := postETag(post)
+	c.Header("ETag", etag)
+	c.Header("Vary", "Authorization")
+	return etag
+}
+
+// ifMatchVersion достаёт из If-Match версию поста, которую видел клиент.
+// Правку разрешает только версия: хеш тела в ETag для If-Match не важен.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, apperror.PreconditionRequired("If-Match header with the post ETag is required")
+	}
+
+	tag, _, _ := strings.Cut(strings.Trim(header, `"`), "-")
+	version, err := strconv.Atoi(tag)
+	if err != nil || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) || version < 1 {
+		return 0, apperror.PreconditionFailed("If-Match does not match the post ETag")
+	}
+	return version, nil
+}
+
+// noneMatch проверяет If-None-Match слабым сравнением, как требует RFC 9110.
+func noneMatch(c *gin.Context, etag string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// displayCurrency возвращает валюту из параметра currency, в которой
+// покупатель хочет видеть цены.
+func displayCurrency(c *gin.Context) (entity.Currency, error) {
+	code := c.Query("currency")
+	if code == "" {
+		return "", nil
+	}
+	currency, err := entity.ParseCurrency(code)
+	if err != nil {
+		return "", apperror.InvalidField("currency", "unsupported currency %q", code)
+	}
+	return currency, nil
+}
+
+// listFilter собирает фильтры списка постов из query-параметров.
+// Фильтр status учитывается только в списке собственных постов автора.
+func listFilter(c *gin.Context) (map[string]string, error) {
+	return entity.ParsePostFilter(c.Request.URL.Query())
+}
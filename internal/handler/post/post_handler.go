@@ -5,8 +5,20 @@ import "github.com/gin-gonic/gin"
 type PostHandlerInterface interface {
 	CreatePost(c *gin.Context)
 	GetPost(c *gin.Context)
+	ListPostRevisions(c *gin.Context)
+	DiffPostRevisions(c *gin.Context)
 	EditPost(c *gin.Context)
+	PatchPost(c *gin.Context)
 	DeletePost(c *gin.Context)
+	RestorePost(c *gin.Context)
+	PublishPost(c *gin.Context)
+	ReservePost(c *gin.Context)
+	MarkPostSold(c *gin.Context)
+	ArchivePost(c *gin.Context)
 	ListPosts(c *gin.Context)
 	ListPostsByAuthor(c *gin.Context)
+	StreamPosts(c *gin.Context)
+	FavoritePost(c *gin.Context)
+	UnfavoritePost(c *gin.Context)
+	ListFavorites(c *gin.Context)
 }
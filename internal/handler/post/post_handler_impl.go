@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
 	servicePost "marketplace/internal/service/post"
 	serviceUser "marketplace/internal/service/user"
+	"marketplace/pkg/mergepatch"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,28 +31,49 @@ func NewPostHandler(postSvc servicePost.PostServiceInterface, userSvc serviceUse
 
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	var req struct {
-		Header  string  `json:"header" binding:"required,min=1,max=100"`
-		Content string  `json:"content" binding:"required,min=1,max=1000"`
-		Image   string  `json:"image" binding:"omitempty,url"`
-		Price   float64 `json:"price" binding:"required,gt=0"`
+		Header       string            `json:"header" binding:"required,min=1,max=100"`
+		Content      string            `json:"content" binding:"required,min=1,max=1000"`
+		ImageIDs     []uuid.UUID       `json:"image_ids" binding:"omitempty,max=10"`
+		CoverImageID *uuid.UUID        `json:"cover_image_id"`
+		Price        *entity.Money     `json:"price" binding:"required"`
+		CategoryID   *uuid.UUID        `json:"category_id"`
+		Tags         []string          `json:"tags" binding:"omitempty,max=10"`
+		Attributes   entity.Attributes `json:"attributes"`
+		Lat          *float64          `json:"lat"`
+		Lng          *float64          `json:"lng"`
+		City         string            `json:"city"`
+		Draft        bool              `json:"draft"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Invalid create post request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(httperror.Binding(err))
 		return
 	}
 
 	userID, ok := c.Request.Context().Value("user_id").(uuid.UUID)
 	if !ok {
 		h.logger.Error("Failed to get user_id from context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Error(apperror.Unauthorized("unauthorized"))
 		return
 	}
 
-	post, err := h.postSvc.CreatePost(c.Request.Context(), userID, req.Header, req.Content, req.Image, req.Price)
+	post, err := h.postSvc.CreatePost(c.Request.Context(), userID, entity.PostParams{
+		Header:       req.Header,
+		Content:      req.Content,
+		ImageIDs:     req.ImageIDs,
+		CoverImageID: req.CoverImageID,
+		Price:        req.Price,
+		CategoryID:   req.CategoryID,
+		Tags:         req.Tags,
+		Attributes:   req.Attributes,
+		Lat:          req.Lat,
+		Lng:          req.Lng,
+		City:         req.City,
+		Draft:        req.Draft,
+	})
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to create post")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -57,6 +81,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		"post_id":   post.ID,
 		"author_id": userID,
 	}).Info("Post created via handler")
+	setPostETag(c, post)
 	c.JSON(http.StatusCreated, post)
 }
 
@@ -65,18 +90,26 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid post ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid currency")
+		c.Error(err)
 		return
 	}
 
-	post, err := h.postSvc.GetPost(c.Request.Context(), id)
+	post, err := h.postSvc.GetPost(c.Request.Context(), id, currency)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get post")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
+		return
+	}
+
+	if etag := setPostETag(c, post); noneMatch(c, etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -86,112 +119,220 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	c.JSON(http.StatusOK, post)
 }
 
-func (h *PostHandler) EditPost(c *gin.Context) {
-	var req struct {
-		Header  string  `json:"header" binding:"omitempty,min=1,max=100"`
-		Content string  `json:"content" binding:"omitempty,min=1,max=1000"`
-		Image   string  `json:"image" binding:"omitempty,url"`
-		Price   float64 `json:"price" binding:"omitempty,gt=0"`
+func (h *PostHandler) ListPostRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Invalid edit post request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+	revisions, err := h.postSvc.ListPostRevisions(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list post revisions")
+		c.Error(err)
 		return
 	}
 
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+	}).Info("Post revisions listed via handler")
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// DiffPostRevisions сравнивает ревизии из query-параметров from и to.
+func (h *PostHandler) DiffPostRevisions(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid post ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apperror.Validation("invalid post ID"))
 		return
 	}
 
-	userID, ok := c.Request.Context().Value("user_id").(uuid.UUID)
-	if !ok {
-		h.logger.Error("Failed to get user_id from context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	var v apperror.Violations
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		v.Add("from", "from must be a positive revision number")
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to < 1 {
+		v.Add("to", "to must be a positive revision number")
+	}
+	if err := v.Err(); err != nil {
+		h.logger.WithError(err).Error("Invalid diff post revisions request")
+		c.Error(err)
 		return
 	}
 
-	post, err := h.postSvc.GetPost(c.Request.Context(), id)
+	diff, err := h.postSvc.DiffPostRevisions(c.Request.Context(), id, from, to)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get post for edit")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		h.logger.WithError(err).Error("Failed to diff post revisions")
+		c.Error(err)
 		return
 	}
-	if post.AuthorID != userID {
-		h.logger.WithFields(logrus.Fields{
-			"user_id": userID,
-			"post_id": id,
-		}).Warn("User is not the author of the post")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+		"from":    from,
+		"to":      to,
+	}).Info("Post revisions compared via handler")
+	c.JSON(http.StatusOK, diff)
+}
+
+func (h *PostHandler) EditPost(c *gin.Context) {
+	var req struct {
+		Header       string            `json:"header" binding:"omitempty,min=1,max=100"`
+		Content      string            `json:"content" binding:"omitempty,min=1,max=1000"`
+		ImageIDs     []uuid.UUID       `json:"image_ids" binding:"omitempty,max=10"`
+		CoverImageID *uuid.UUID        `json:"cover_image_id"`
+		Price        *entity.Money     `json:"price"`
+		CategoryID   *uuid.UUID        `json:"category_id"`
+		Tags         []string          `json:"tags" binding:"omitempty,max=10"`
+		Attributes   entity.Attributes `json:"attributes"`
+		Lat          *float64          `json:"lat"`
+		Lng          *float64          `json:"lng"`
+		City         string            `json:"city"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid edit post request")
+		c.Error(httperror.Binding(err))
 		return
 	}
 
-	updatedPost, err := h.postSvc.EditPost(c.Request.Context(), id, req.Header, req.Content, req.Image, req.Price)
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.logger.WithError(err).Warn("Edit post without a valid If-Match")
+		c.Error(err)
+		return
+	}
+
+	updatedPost, err := h.postSvc.EditPost(c.Request.Context(), id, version, entity.PostParams{
+		Header:       req.Header,
+		Content:      req.Content,
+		ImageIDs:     req.ImageIDs,
+		CoverImageID: req.CoverImageID,
+		Price:        req.Price,
+		CategoryID:   req.CategoryID,
+		Tags:         req.Tags,
+		Attributes:   req.Attributes,
+		Lat:          req.Lat,
+		Lng:          req.Lng,
+		City:         req.City,
+	})
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to edit post")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
+		c.Error(err)
 		return
 	}
 
 	h.logger.WithFields(logrus.Fields{
 		"post_id": id,
+		"version": updatedPost.Version,
 	}).Info("Post edited via handler")
+	setPostETag(c, updatedPost)
 	c.JSON(http.StatusOK, updatedPost)
 }
 
-func (h *PostHandler) DeletePost(c *gin.Context) {
+func (h *PostHandler) PatchPost(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid post ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apperror.Validation("invalid post ID"))
 		return
 	}
 
-	userID, ok := c.Request.Context().Value("user_id").(uuid.UUID)
-	if !ok {
-		h.logger.Error("Failed to get user_id from context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.logger.WithError(err).Warn("Patch post without a valid If-Match")
+		c.Error(err)
 		return
 	}
 
-	post, err := h.postSvc.GetPost(c.Request.Context(), id)
+	patch, err := mergepatch.ReadRequest(c.Writer, c.Request)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get post for delete")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		h.logger.WithError(err).Error("Invalid patch post request")
+		c.Error(httperror.MergePatch(err))
 		return
 	}
-	if post.AuthorID != userID {
-		h.logger.WithFields(logrus.Fields{
-			"user_id": userID,
-			"post_id": id,
-		}).Warn("User is not the author of the post")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+
+	updatedPost, err := h.postSvc.PatchPost(c.Request.Context(), id, version, patch)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to patch post")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+		"version": updatedPost.Version,
+	}).Info("Post patched via handler")
+	setPostETag(c, updatedPost)
+	c.JSON(http.StatusOK, updatedPost)
+}
+
+func (h *PostHandler) PublishPost(c *gin.Context) {
+	h.changeStatus(c, entity.PostPublished)
+}
+
+func (h *PostHandler) ReservePost(c *gin.Context) {
+	h.changeStatus(c, entity.PostReserved)
+}
+
+func (h *PostHandler) MarkPostSold(c *gin.Context) {
+	h.changeStatus(c, entity.PostSold)
+}
+
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	h.changeStatus(c, entity.PostArchived)
+}
+
+func (h *PostHandler) changeStatus(c *gin.Context, status entity.PostStatus) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	post, err := h.postSvc.ChangePostStatus(c.Request.Context(), id, status)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to change post status")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+		"status":  status,
+	}).Info("Post status changed via handler")
+	setPostETag(c, post)
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
 		return
 	}
 
 	if err := h.postSvc.DeletePost(c.Request.Context(), id); err != nil {
 		h.logger.WithError(err).Error("Failed to delete post")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -201,12 +342,110 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
 }
 
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	post, err := h.postSvc.RestorePost(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to restore post")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+	}).Info("Post restored via handler")
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *PostHandler) FavoritePost(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	post, err := h.postSvc.FavoritePost(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to add post to favorites")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+	}).Info("Post added to favorites via handler")
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *PostHandler) UnfavoritePost(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post ID")
+		c.Error(apperror.Validation("invalid post ID"))
+		return
+	}
+
+	if err := h.postSvc.UnfavoritePost(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to remove post from favorites")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"post_id": id,
+	}).Info("Post removed from favorites via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Post removed from favorites"})
+}
+
+func (h *PostHandler) ListFavorites(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid currency")
+		c.Error(err)
+		return
+	}
+
+	posts, total, err := h.postSvc.ListFavorites(c.Request.Context(), page, pageSize, currency)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list favorites")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"page":        page,
+		"page_size":   pageSize,
+		"total_posts": total,
+	}).Info("Favorites listed via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"posts":     posts,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
 func (h *PostHandler) ListPosts(c *gin.Context) {
 	pageStr := c.Query("page")
 	pageSizeStr := c.Query("pageSize")
 	sortBy := c.Query("sortBy")
-	minPrice := c.Query("min_price")
-	maxPrice := c.Query("max_price")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -217,34 +456,25 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 		pageSize = 10
 	}
 
-	filter := make(map[string]string)
-	if minPrice != "" {
-		filter["min_price"] = minPrice
-	}
-	if maxPrice != "" {
-		filter["max_price"] = maxPrice
+	filter, err := listFilter(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid list posts filter")
+		c.Error(err)
+		return
 	}
 
 	posts, total, err := h.postSvc.ListPosts(c.Request.Context(), page, pageSize, sortBy, filter)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list posts")
-		if strings.Contains(err.Error(), "invalid") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
 		return
 	}
 
-	userID, _ := c.Request.Context().Value("user_id").(uuid.UUID)
-	for _, post := range posts {
-		post.IsOwnPost = post.AuthorID == userID
-		if post.IsOwnPost {
-			user, err := h.userSvc.GetUser(c.Request.Context(), post.AuthorID)
-			if err == nil {
-				post.AuthorUsername = user.Username
-			}
-		}
+	facets, err := h.postSvc.CategoryFacets(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get category facets")
+		c.Error(err)
+		return
 	}
 
 	h.logger.WithFields(logrus.Fields{
@@ -257,6 +487,7 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 		"total":     total,
 		"page":      page,
 		"page_size": pageSize,
+		"facets":    gin.H{"categories": facets},
 	})
 }
 
@@ -265,15 +496,13 @@ func (h *PostHandler) ListPostsByAuthor(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid user ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(apperror.Validation("invalid user ID"))
 		return
 	}
 
 	pageStr := c.Query("page")
 	pageSizeStr := c.Query("pageSize")
 	sortBy := c.Query("sortBy")
-	minPrice := c.Query("min_price")
-	maxPrice := c.Query("max_price")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -284,34 +513,25 @@ func (h *PostHandler) ListPostsByAuthor(c *gin.Context) {
 		pageSize = 10
 	}
 
-	filter := make(map[string]string)
-	if minPrice != "" {
-		filter["min_price"] = minPrice
-	}
-	if maxPrice != "" {
-		filter["max_price"] = maxPrice
+	filter, err := listFilter(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid list posts filter")
+		c.Error(err)
+		return
 	}
 
 	posts, total, err := h.postSvc.ListPostsByAuthor(c.Request.Context(), id, page, pageSize, sortBy, filter)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list posts by author")
-		if strings.Contains(err.Error(), "invalid") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
 		return
 	}
 
-	userID, _ := c.Request.Context().Value("user_id").(uuid.UUID)
-	for _, post := range posts {
-		post.IsOwnPost = post.AuthorID == userID
-		if post.IsOwnPost {
-			user, err := h.userSvc.GetUser(c.Request.Context(), post.AuthorID)
-			if err == nil {
-				post.AuthorUsername = user.Username
-			}
-		}
+	facets, err := h.postSvc.CategoryFacetsByAuthor(c.Request.Context(), id, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get category facets by author")
+		c.Error(err)
+		return
 	}
 
 	h.logger.WithFields(logrus.Fields{
@@ -325,5 +545,6 @@ func (h *PostHandler) ListPostsByAuthor(c *gin.Context) {
 		"total":     total,
 		"page":      page,
 		"page_size": pageSize,
+		"facets":    gin.H{"categories": facets},
 	})
 }
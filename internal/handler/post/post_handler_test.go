@@ -6,10 +6,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,18 +26,28 @@ type MockPostService struct {
 	mock.Mock
 }
 
-func (m *MockPostService) CreatePost(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	args := m.Called(ctx, authorID, header, content, image, price)
+func (m *MockPostService) CreatePost(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error) {
+	args := m.Called(ctx, authorID, params)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostService) GetPost(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
-	args := m.Called(ctx, id)
+func (m *MockPostService) GetPost(ctx context.Context, id uuid.UUID, currency entity.Currency) (*entity.Post, error) {
+	args := m.Called(ctx, id, currency)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostService) EditPost(ctx context.Context, id uuid.UUID, version int, params entity.PostParams) (*entity.Post, error) {
+	args := m.Called(ctx, id, version, params)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostService) PatchPost(ctx context.Context, id uuid.UUID, version int, patch []byte) (*entity.Post, error) {
+	args := m.Called(ctx, id, version, patch)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostService) EditPost(ctx context.Context, id uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	args := m.Called(ctx, id, header, content, image, price)
+func (m *MockPostService) ChangePostStatus(ctx context.Context, id uuid.UUID, status entity.PostStatus) (*entity.Post, error) {
+	args := m.Called(ctx, id, status)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
@@ -42,6 +56,36 @@ func (m *MockPostService) DeletePost(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockPostService) RestorePost(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostService) FavoritePost(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostService) UnfavoritePost(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPostService) ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	args := m.Called(ctx, page, pageSize, currency)
+	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
+}
+
+func (m *MockPostService) ListPostRevisions(ctx context.Context, id uuid.UUID) ([]*entity.PostRevision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostService) DiffPostRevisions(ctx context.Context, id uuid.UUID, from, to int) (*entity.RevisionDiff, error) {
+	args := m.Called(ctx, id, from, to)
+	return args.Get(0).(*entity.RevisionDiff), args.Error(1)
+}
+
 func (m *MockPostService) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	args := m.Called(ctx, page, pageSize, sortBy, filter)
 	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
@@ -52,6 +96,22 @@ func (m *MockPostService) ListPostsByAuthor(ctx context.Context, authorID uuid.U
 	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
 }
 
+func (m *MockPostService) StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error) {
+	args := m.Called(ctx, filter, lastEventID)
+	stream, _ := args.Get(0).(*usecaseRealtime.PostStream)
+	return stream, args.Error(1)
+}
+
+func (m *MockPostService) CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entity.CategoryFacet), args.Error(1)
+}
+
+func (m *MockPostService) CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	args := m.Called(ctx, authorID, filter)
+	return args.Get(0).([]*entity.CategoryFacet), args.Error(1)
+}
+
 func TestCreatePostHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
@@ -62,11 +122,12 @@ func TestCreatePostHandler(t *testing.T) {
 
 	r.POST("/posts", handler.CreatePost)
 
+	imageID := uuid.New()
 	reqBody := map[string]interface{}{
-		"header":  "Test Post",
-		"content": "This is a test post.",
-		"image":   "http://example.com/image.jpg",
-		"price":   99.99,
+		"header":    "Test Post",
+		"content":   "This is a test post.",
+		"image_ids": []uuid.UUID{imageID},
+		"price":     map[string]interface{}{"amount": 9999, "currency": "RUB"},
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -84,17 +145,362 @@ func TestCreatePostHandler(t *testing.T) {
 		ID:             uuid.New(),
 		Header:         "Test Post",
 		Content:        "This is a test post.",
-		Image:          "http://example.com/image.jpg",
-		Price:          99.99,
+		Image:          entity.ImageURL(imageID),
+		Images:         []entity.PostImage{{ImageID: imageID, URL: entity.ImageURL(imageID), IsCover: true}},
+		Price:          entity.Money{Amount: 9999, Currency: "RUB"},
 		AuthorID:       userID,
 		CreatedAt:      time.Now(),
 		AuthorUsername: "",
 		IsOwnPost:      true,
 	}
-	mockPostSvc.On("CreatePost", ctx, userID, "Test Post", "This is a test post.", "http://example.com/image.jpg", 99.99).
-		Return(expectedPost, nil)
+	mockPostSvc.On("CreatePost", ctx, userID, entity.PostParams{
+		Header:   "Test Post",
+		Content:  "This is a test post.",
+		ImageIDs: []uuid.UUID{imageID},
+		Price:    &entity.Money{Amount: 9999, Currency: "RUB"},
+	}).Return(expectedPost, nil)
 
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 }
+
+func TestListPostsHandler_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.GET("/posts", handler.ListPosts)
+
+	filter := map[string]string{
+		"min_price": "10",
+		"q":         "bike",
+		"category":  "bicycles",
+		"tag":       "red,kids",
+		"status":    "sold",
+		"attr.size": "42",
+		"lat":       "55.75",
+		"lng":       "37.62",
+		"radius_km": "5",
+	}
+	posts := []*entity.Post{{ID: uuid.New(), AuthorID: uuid.New(), Header: "Red bike", Tags: []string{"red", "kids"}}}
+	facets := []*entity.CategoryFacet{{CategoryID: uuid.New(), Slug: "bicycles", Name: "Bicycles", Count: 1}}
+	mockPostSvc.On("ListPosts", mock.Anything, 1, 10, "", filter).Return(posts, 1, nil)
+	mockPostSvc.On("CategoryFacets", mock.Anything, filter).Return(facets, nil)
+
+	req, _ := http.NewRequest("GET", "/posts?min_price=10&q=bike&category=bicycles&tag=Red&tag=kids,red&status=sold&attr.size=42&attr.color=&lat=55.75&lng=37.62&radius_km=5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Total  int `json:"total"`
+		Facets struct {
+			Categories []*entity.CategoryFacet `json:"categories"`
+		} `json:"facets"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, facets, resp.Facets.Categories)
+	mockPostSvc.AssertExpectations(t)
+}
+
+func TestChangePostStatusHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.POST("/posts/:id/reserve", handler.ReservePost)
+	r.POST("/posts/:id/mark-sold", handler.MarkPostSold)
+
+	postID := uuid.New()
+	mockPostSvc.On("ChangePostStatus", mock.Anything, postID, entity.PostReserved).
+		Return(&entity.Post{ID: postID, Status: entity.PostReserved}, nil)
+	mockPostSvc.On("ChangePostStatus", mock.Anything, postID, entity.PostSold).
+		Return(&entity.Post{ID: postID, Status: entity.PostSold}, nil)
+
+	for _, tt := range []struct {
+		path   string
+		status entity.PostStatus
+	}{
+		{"/posts/" + postID.String() + "/reserve", entity.PostReserved},
+		{"/posts/" + postID.String() + "/mark-sold", entity.PostSold},
+	} {
+		req, _ := http.NewRequest("POST", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var post entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &post))
+		assert.Equal(t, tt.status, post.Status)
+	}
+	mockPostSvc.AssertExpectations(t)
+}
+
+func TestFavoritesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.POST("/posts/:id/favorite", handler.FavoritePost)
+	r.DELETE("/posts/:id/favorite", handler.UnfavoritePost)
+	r.GET("/users/me/favorites", handler.ListFavorites)
+
+	postID := uuid.New()
+	favorited := &entity.Post{ID: postID, FavoritesCount: 3, IsFavorited: true}
+	mockPostSvc.On("FavoritePost", mock.Anything, postID).Return(favorited, nil)
+	mockPostSvc.On("UnfavoritePost", mock.Anything, postID).Return(nil)
+	mockPostSvc.On("ListFavorites", mock.Anything, 2, 5, entity.Currency("USD")).Return([]*entity.Post{favorited}, 6, nil)
+
+	req, _ := http.NewRequest("POST", "/posts/"+postID.String()+"/favorite", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var post entity.Post
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &post))
+	assert.True(t, post.IsFavorited)
+	assert.Equal(t, 3, post.FavoritesCount)
+
+	req, _ = http.NewRequest("DELETE", "/posts/"+postID.String()+"/favorite", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("GET", "/users/me/favorites?page=2&pageSize=5&currency=usd", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Posts []*entity.Post `json:"posts"`
+		Total int            `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 6, resp.Total)
+	assert.Len(t, resp.Posts, 1)
+
+	req, _ = http.NewRequest("POST", "/posts/not-a-uuid/favorite", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockPostSvc.AssertExpectations(t)
+}
+
+func TestDiffPostRevisionsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(httperror.Middleware(logrus.New()))
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.GET("/posts/:id/revisions/diff", handler.DiffPostRevisions)
+
+	postID := uuid.New()
+	diff := &entity.RevisionDiff{PostID: postID, From: 1, To: 2, Changes: []entity.FieldChange{{Field: "price", From: 100.0, To: 80.0}}}
+	mockPostSvc.On("DiffPostRevisions", mock.Anything, postID, 1, 2).Return(diff, nil)
+
+	req, _ := http.NewRequest("GET", "/posts/"+postID.String()+"/revisions/diff?from=1&to=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp entity.RevisionDiff
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, *diff, resp)
+
+	req, _ = http.NewRequest("GET", "/posts/"+postID.String()+"/revisions/diff?from=0", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var problem httperror.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Len(t, problem.Errors, 2)
+	mockPostSvc.AssertExpectations(t)
+}
+
+func TestGetPostHandler_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.GET("/posts/:id", handler.GetPost)
+
+	postID := uuid.New()
+	post := &entity.Post{ID: postID, Version: 3, FavoritesCount: 1}
+	mockPostSvc.On("GetPost", mock.Anything, postID, entity.Currency("")).Return(post, nil)
+
+	req, _ := http.NewRequest("GET", "/posts/"+postID.String(), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"3-[0-9a-f]{16}"$`, etag)
+	assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+
+	req, _ = http.NewRequest("GET", "/posts/"+postID.String(), nil)
+	req.Header.Set("If-None-Match", `"2", W/`+etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// Избранное не меняет версию поста, но меняет тело и ETag.
+	post.FavoritesCount = 2
+	req, _ = http.NewRequest("GET", "/posts/"+postID.String(), nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.Regexp(t, `^"3-`, w.Header().Get("ETag"))
+}
+
+func TestEditPostHandler_IfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(httperror.Middleware(logrus.New()))
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.PUT("/posts/:id", handler.EditPost)
+
+	postID := uuid.New()
+	price := entity.Money{Amount: 8000, Currency: "RUB"}
+	params := entity.PostParams{Price: &price}
+	mockPostSvc.On("EditPost", mock.Anything, postID, 3, params).Return(&entity.Post{ID: postID, Price: price, Version: 4}, nil)
+	mockPostSvc.On("EditPost", mock.Anything, postID, 2, params).Return((*entity.Post)(nil), apperror.PreconditionFailed("post has been modified: current version is 3"))
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		etag    string
+	}{
+		{"current version", `"3"`, http.StatusOK, `"4-`},
+		{"etag from GET", `"3-0123456789abcdef"`, http.StatusOK, `"4-`},
+		{"stale version", `"2"`, http.StatusPreconditionFailed, ""},
+		{"missing header", "", http.StatusPreconditionRequired, ""},
+		{"weak etag", `W/"3"`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/posts/"+postID.String(), bytes.NewBufferString(`{"price":{"amount":8000,"currency":"RUB"}}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.etag == "" {
+				assert.Empty(t, w.Header().Get("ETag"))
+			} else {
+				assert.True(t, strings.HasPrefix(w.Header().Get("ETag"), tt.etag), w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestPatchPostHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(httperror.Middleware(logrus.New()))
+
+	mockPostSvc := new(MockPostService)
+	logger := logrus.New()
+	handler := NewPostHandler(mockPostSvc, nil, logger)
+
+	r.PATCH("/posts/:id", handler.PatchPost)
+
+	postID := uuid.New()
+	patch := `{"price":{"amount":0},"category_id":null}`
+	mockPostSvc.On("PatchPost", mock.Anything, postID, 3, []byte(patch)).Return(&entity.Post{ID: postID, Price: entity.Money{Currency: "RUB"}, Version: 4}, nil)
+
+	tests := []struct {
+		name        string
+		contentType string
+		ifMatch     string
+		status      int
+	}{
+		{"merge patch", "application/merge-patch+json", `"3"`, http.StatusOK},
+		{"plain json", "application/json", `"3"`, http.StatusOK},
+		{"unsupported content type", "application/json-patch+json", `"3"`, http.StatusUnsupportedMediaType},
+		{"missing If-Match", "application/merge-patch+json", "", http.StatusPreconditionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PATCH", "/posts/"+postID.String(), bytes.NewBufferString(patch))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Regexp(t, `^"4-`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestStreamPostsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(httperror.Middleware(logrus.New()))
+
+	mockPostSvc := new(MockPostService)
+	handler := NewPostHandler(mockPostSvc, nil, logrus.New())
+	r.GET("/posts/stream", handler.StreamPosts)
+
+	post := &entity.Post{ID: uuid.New(), Header: "Bike", Status: entity.PostPublished}
+	entry := &usecaseRealtime.FeedEntry{ID: "1700000000000000-" + post.ID.String(), Post: post}
+	filter := map[string]string{"tag": "bike"}
+	mockPostSvc.On("StreamPosts", mock.Anything, filter, "1699999999000000-"+uuid.Nil.String()).
+		Return(&usecaseRealtime.PostStream{Filter: filter, Replay: []*usecaseRealtime.FeedEntry{entry}}, nil)
+
+	// Отменённый запрос: обработчик отдаёт пропущенные посты и выходит.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/posts/stream?tag=Bike", nil)
+	req.Header.Set("Last-Event-ID", "1699999999000000-"+uuid.Nil.String())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	data, _ := json.Marshal(post)
+	assert.Equal(t, "id: "+entry.ID+"\nevent: post\ndata: "+string(data)+"\n\n", w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/posts/stream?currency=XYZ", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockPostSvc.AssertExpectations(t)
+}
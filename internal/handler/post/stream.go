@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive — как часто в ленту пишется комментарий, чтобы прокси не
+// закрывали простаивающее соединение.
+const streamKeepAlive = 30 * time.Second
+
+// StreamPosts отдаёт новые посты, подходящие под фильтры списка постов, как
+// server-sent events "post". Клиент, переподключившийся с Last-Event-ID,
+// сначала получает посты, пропущенные после этого события.
+func (h *PostHandler) StreamPosts(c *gin.Context) {
+	filter, err := listFilter(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid post stream filter")
+		c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	stream, err := h.postSvc.StreamPosts(ctx, filter, c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to subscribe to post stream")
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, entry := range stream.Replay {
+		if err := writePostEvent(c.Writer, entry); err != nil {
+			h.logger.WithError(err).Warn("Failed to write post stream event")
+			return
+		}
+	}
+	c.Writer.Flush()
+	h.logger.WithField("replay", len(stream.Replay)).Info("Post stream opened via handler")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry, ok := <-stream.Events():
+			if !ok {
+				// Лента отключила отстающего клиента: он переподключится с
+				// Last-Event-ID и получит пропущенное из буфера.
+				return
+			}
+			if err := writePostEvent(c.Writer, entry); err != nil {
+				h.logger.WithError(err).Warn("Failed to write post stream event")
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writePostEvent(w io.Writer, entry *usecaseRealtime.FeedEntry) error {
+	data, err := json.Marshal(entry.Post)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: post\ndata: %s\n\n", entry.ID, data)
+	return err
+}
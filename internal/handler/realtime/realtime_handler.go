@@ -0,0 +1,7 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type RealtimeHandlerInterface interface {
+	Connect(c *gin.Context)
+}
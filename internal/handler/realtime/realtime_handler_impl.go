@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	handlerAuth "marketplace/internal/handler/auth"
+	serviceAuth "marketplace/internal/service/auth"
+	service "marketplace/internal/service/realtime"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// pingInterval — как часто слать клиенту ping, чтобы прокси не закрывали
+	// простаивающее соединение, и перепроверять сессию.
+	pingInterval = 30 * time.Second
+	writeTimeout = 10 * time.Second
+	// maxClientFrame ограничивает кадры от клиента: сервер их не ждёт.
+	maxClientFrame = 4096
+)
+
+// clientEvent — событие в том виде, в каком его получает клиент.
+type clientEvent struct {
+	Type entity.EventType `json:"type"`
+	Data json.RawMessage  `json:"data,omitempty"`
+}
+
+type RealtimeHandler struct {
+	authSvc      serviceAuth.AuthServiceInterface
+	realtimeSvc  service.RealtimeServiceInterface
+	pingInterval time.Duration
+	logger       *logrus.Logger
+}
+
+func NewRealtimeHandler(authSvc serviceAuth.AuthServiceInterface, realtimeSvc service.RealtimeServiceInterface, logger *logrus.Logger) *RealtimeHandler {
+	return &RealtimeHandler{
+		authSvc:      authSvc,
+		realtimeSvc:  realtimeSvc,
+		pingInterval: pingInterval,
+		logger:       logger,
+	}
+}
+
+// Connect открывает WebSocket-соединение, по которому сервер присылает
+// события текущего пользователя. Браузер не может передать заголовок
+// Authorization при открытии WebSocket, поэтому токен принимается и в
+// параметре access_token. Соединение закрывается, когда истекает срок токена
+// или сессия отозвана.
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	claims, err := handlerAuth.Authenticate(c, h.authSvc, h.logger, true)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	ctx := c.Request.Context()
+	if !c.IsWebsocket() {
+		h.logger.Warn("Request is not a WebSocket upgrade")
+		c.Error(apperror.Validation("websocket upgrade required"))
+		return
+	}
+
+	subscription, err := h.realtimeSvc.Subscribe(ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to subscribe to events")
+		c.Error(err)
+		return
+	}
+	defer h.realtimeSvc.Unsubscribe(subscription)
+
+	server := websocket.Server{
+		// Соединение защищено токеном, а не cookie, поэтому Origin не
+		// проверяется.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.stream(ctx, conn, subscription, claims)
+		},
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id": subscription.UserID,
+	}).Info("WebSocket connected via handler")
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// stream пересылает события клиенту, пока тот не закроет соединение или хаб
+// не отключит его. Сессия токена перепроверяется при каждом ping, а по
+// истечении токена соединение закрывается: клиент переподключается с новым.
+func (h *RealtimeHandler) stream(ctx context.Context, conn *websocket.Conn, subscription *usecaseRealtime.Subscription, claims *entity.TokenClaims) {
+	conn.MaxPayloadBytes = maxClientFrame
+
+	// Клиент ничего не присылает; чтение нужно, чтобы заметить, что он
+	// закрыл соединение.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message string
+		for websocket.Message.Receive(conn, &message) == nil {
+		}
+	}()
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	var expired <-chan time.Time
+	if !claims.ExpiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(claims.ExpiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		var out clientEvent
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				h.logger.WithField("user_id", subscription.UserID).Warn("WebSocket dropped by event hub")
+				return
+			}
+			out = clientEvent{Type: event.Type, Data: event.Data}
+		case <-expired:
+			h.logger.WithField("user_id", subscription.UserID).Info("WebSocket token expired")
+			return
+		case <-ticker.C:
+			revoked, err := h.authSvc.IsSessionRevoked(ctx, claims.SessionID)
+			if err != nil || revoked {
+				h.logger.WithError(err).WithFields(logrus.Fields{
+					"user_id":    claims.UserID,
+					"session_id": claims.SessionID,
+				}).Warn("WebSocket session is revoked")
+				return
+			}
+			out = clientEvent{Type: "ping"}
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := websocket.JSON.Send(conn, out); err != nil {
+			h.logger.WithError(err).WithField("user_id", subscription.UserID).Warn("Failed to send event over WebSocket")
+			return
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/realtime"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) ValidateJWT(token string) (*entity.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*entity.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockAuthService) GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error) {
+	args := m.Called(userID, sessionID, role)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) JWKS() *entity.JSONWebKeySet {
+	args := m.Called()
+	return args.Get(0).(*entity.JSONWebKeySet)
+}
+
+func (m *MockAuthService) GeneratePasswordHash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) VerifyPassword(hashedPassword, inputPassword string) error {
+	args := m.Called(hashedPassword, inputPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	tokens, _ := args.Get(0).(*entity.TokenPair)
+	return tokens, args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepository) GetMessage(ctx context.Context, id uuid.UUID) (*entity.Message, error) {
+	args := m.Called(ctx, id)
+	message, _ := args.Get(0).(*entity.Message)
+	return message, args.Error(1)
+}
+
+func TestConnectHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	logger := logrus.New()
+	mockAuthSvc := new(MockAuthService)
+	messageRepo := new(MockMessageRepository)
+	hub := usecaseRealtime.NewHub(nil, nil, messageRepo, nil, nil, logger)
+	handler := NewRealtimeHandler(mockAuthSvc, service.NewRealtimeService(hub, logger), logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/ws", handler.Connect)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	userID, sessionID := uuid.New(), uuid.New()
+	mockAuthSvc.On("ValidateJWT", "valid_token").Return(&entity.TokenClaims{UserID: userID, SessionID: sessionID}, nil)
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(false, nil)
+	mockAuthSvc.On("ValidateJWT", "invalid_token").Return(nil, fmt.Errorf("invalid token"))
+
+	resp, err := http.Get(server.URL + "/ws?access_token=invalid_token")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?access_token=valid_token"
+	conn, err := websocket.Dial(wsURL, "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Хаб подписывает клиента при открытии соединения; ждём подписки, чтобы
+	// событие не разослалось раньше.
+	message := &entity.Message{ID: uuid.New(), SenderID: userID, Body: "Ещё продаёте?"}
+	messageRepo.On("GetMessage", mock.Anything, message.ID).Return(message, nil)
+	event := entity.NewUserEvent(entity.EventMessage, message.ID, userID)
+	var got clientEvent
+	require.Eventually(t, func() bool {
+		hub.Dispatch(context.Background(), event)
+		conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		return websocket.JSON.Receive(conn, &got) == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, entity.EventMessage, got.Type)
+	var data entity.Message
+	require.NoError(t, json.Unmarshal(got.Data, &data))
+	assert.Equal(t, message.ID, data.ID)
+	assert.Equal(t, "Ещё продаёте?", data.Body)
+}
+
+// serveRealtime поднимает сервер с обработчиком Connect и подключается к нему
+// с токеном token.
+func serveRealtime(t *testing.T, handler *RealtimeHandler, token string) *websocket.Conn {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(httperror.Middleware(handler.logger))
+	r.GET("/ws", handler.Connect)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?access_token="+token, "", server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// waitClosed читает кадры, пока сервер не закроет соединение.
+func waitClosed(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var got clientEvent
+		if err := websocket.JSON.Receive(conn, &got); err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			return
+		}
+	}
+}
+
+func TestConnectHandler_ClosesAtTokenExpiry(t *testing.T) {
+	logger := logrus.New()
+	mockAuthSvc := new(MockAuthService)
+	hub := usecaseRealtime.NewHub(nil, nil, nil, nil, nil, logger)
+	handler := NewRealtimeHandler(mockAuthSvc, service.NewRealtimeService(hub, logger), logger)
+
+	sessionID := uuid.New()
+	claims := &entity.TokenClaims{UserID: uuid.New(), SessionID: sessionID, ExpiresAt: time.Now().Add(200 * time.Millisecond)}
+	mockAuthSvc.On("ValidateJWT", "short_token").Return(claims, nil)
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(false, nil)
+
+	waitClosed(t, serveRealtime(t, handler, "short_token"))
+}
+
+func TestConnectHandler_ClosesRevokedSession(t *testing.T) {
+	logger := logrus.New()
+	mockAuthSvc := new(MockAuthService)
+	hub := usecaseRealtime.NewHub(nil, nil, nil, nil, nil, logger)
+	handler := NewRealtimeHandler(mockAuthSvc, service.NewRealtimeService(hub, logger), logger)
+	handler.pingInterval = 20 * time.Millisecond
+
+	sessionID := uuid.New()
+	claims := &entity.TokenClaims{UserID: uuid.New(), SessionID: sessionID, ExpiresAt: time.Now().Add(time.Hour)}
+	mockAuthSvc.On("ValidateJWT", "valid_token").Return(claims, nil)
+	// Сессию отзывают после подключения: её замечает проверка при ping.
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(false, nil).Twice()
+	mockAuthSvc.On("IsSessionRevoked", mock.Anything, sessionID).Return(true, nil)
+
+	waitClosed(t, serveRealtime(t, handler, "valid_token"))
+	mockAuthSvc.AssertNumberOfCalls(t, "IsSessionRevoked", 3)
+}
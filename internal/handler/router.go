@@ -1,45 +1,126 @@
 package handler
 
 import (
+	"marketplace/internal/entity"
 	handlerAuth "marketplace/internal/handler/auth"
+	handlerCategory "marketplace/internal/handler/category"
+	handlerConversation "marketplace/internal/handler/conversation"
+	handlerExchangeRate "marketplace/internal/handler/exchangerate"
+	"marketplace/internal/handler/httperror"
+	handlerImage "marketplace/internal/handler/image"
+	handlerNotification "marketplace/internal/handler/notification"
 	handlerPost "marketplace/internal/handler/post"
+	handlerRealtime "marketplace/internal/handler/realtime"
+	handlerSavedSearch "marketplace/internal/handler/savedsearch"
 	handlerUser "marketplace/internal/handler/user"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 type Router struct {
-	userHandler handlerUser.UserHandlerInterface
-	postHandler handlerPost.PostHandlerInterface
-	authHandler handlerAuth.AuthHandlerInterface
+	userHandler         handlerUser.UserHandlerInterface
+	postHandler         handlerPost.PostHandlerInterface
+	authHandler         handlerAuth.AuthHandlerInterface
+	categoryHandler     handlerCategory.CategoryHandlerInterface
+	imageHandler        handlerImage.ImageHandlerInterface
+	rateHandler         handlerExchangeRate.ExchangeRateHandlerInterface
+	searchHandler       handlerSavedSearch.SavedSearchHandlerInterface
+	notificationHandler handlerNotification.NotificationHandlerInterface
+	conversationHandler handlerConversation.ConversationHandlerInterface
+	realtimeHandler     handlerRealtime.RealtimeHandlerInterface
+	logger              *logrus.Logger
 }
 
-func NewRouter(userHandler handlerUser.UserHandlerInterface, postHandler handlerPost.PostHandlerInterface, authHandler handlerAuth.AuthHandlerInterface) *Router {
+func NewRouter(userHandler handlerUser.UserHandlerInterface, postHandler handlerPost.PostHandlerInterface, authHandler handlerAuth.AuthHandlerInterface, categoryHandler handlerCategory.CategoryHandlerInterface, imageHandler handlerImage.ImageHandlerInterface, rateHandler handlerExchangeRate.ExchangeRateHandlerInterface, searchHandler handlerSavedSearch.SavedSearchHandlerInterface, notificationHandler handlerNotification.NotificationHandlerInterface, conversationHandler handlerConversation.ConversationHandlerInterface, realtimeHandler handlerRealtime.RealtimeHandlerInterface, logger *logrus.Logger) *Router {
 	return &Router{
-		userHandler: userHandler,
-		postHandler: postHandler,
-		authHandler: authHandler,
+		userHandler:         userHandler,
+		postHandler:         postHandler,
+		authHandler:         authHandler,
+		categoryHandler:     categoryHandler,
+		imageHandler:        imageHandler,
+		rateHandler:         rateHandler,
+		searchHandler:       searchHandler,
+		notificationHandler: notificationHandler,
+		conversationHandler: conversationHandler,
+		realtimeHandler:     realtimeHandler,
+		logger:              logger,
 	}
 }
 
 func (r *Router) SetupRoutes() *gin.Engine {
 	ginRouter := gin.New()
-	ginRouter.Use(gin.Logger(), gin.Recovery())
+	ginRouter.Use(gin.Logger(), gin.Recovery(), httperror.Middleware(r.logger))
 
 	ginRouter.POST("/users/register", r.userHandler.Register)
 	ginRouter.POST("/users/login", r.userHandler.Login)
-	ginRouter.GET("/posts/:id", r.postHandler.GetPost)
-	ginRouter.GET("/posts", r.postHandler.ListPosts)
+	ginRouter.POST("/users/restore", r.userHandler.RestoreAccount)
+	ginRouter.POST("/auth/refresh", r.authHandler.Refresh)
+	ginRouter.GET("/.well-known/jwks.json", r.authHandler.JWKS)
+	// Лента новых постов (server-sent events) с фильтрами списка постов
+	ginRouter.GET("/posts/stream", r.postHandler.StreamPosts)
+	ginRouter.GET("/posts/:id", r.authHandler.OptionalAuthMiddleware(), r.postHandler.GetPost)
+	ginRouter.GET("/posts/:id/revisions", r.authHandler.OptionalAuthMiddleware(), r.postHandler.ListPostRevisions)
+	ginRouter.GET("/posts/:id/revisions/diff", r.authHandler.OptionalAuthMiddleware(), r.postHandler.DiffPostRevisions)
+	ginRouter.GET("/posts", r.authHandler.OptionalAuthMiddleware(), r.postHandler.ListPosts)
+	ginRouter.GET("/categories", r.categoryHandler.ListCategories)
+	ginRouter.GET("/categories/:id", r.categoryHandler.GetCategory)
+	ginRouter.GET("/images/:id", r.imageHandler.GetImage)
+	ginRouter.GET("/images/:id/:variant", r.imageHandler.GetImageVariant)
+	ginRouter.GET("/exchange-rates", r.rateHandler.ListExchangeRates)
+	// Токен проверяет сам обработчик: браузер передаёт его в access_token.
+	ginRouter.GET("/ws", r.realtimeHandler.Connect)
 
 	private := ginRouter.Group("/", r.authHandler.AuthMiddleware())
 	{
+		private.POST("/auth/logout", r.authHandler.Logout)
 		private.GET("/users/:id", r.userHandler.GetUser)
-		private.PUT("/users/:id", r.authHandler.OwnerMiddleware("id"), r.userHandler.UpdateUser)
-		private.DELETE("/users/:id", r.authHandler.OwnerMiddleware("id"), r.userHandler.DeleteUser)
+		private.PUT("/users/:id", r.userHandler.UpdateUser)
+		private.PATCH("/users/:id", r.userHandler.PatchUser)
+		private.DELETE("/users/:id", r.userHandler.DeleteUser)
+		private.POST("/users/:id/restore", r.authHandler.RequireRole(entity.RoleAdmin), r.userHandler.RestoreUser)
+		private.PUT("/users/:id/role", r.authHandler.RequireRole(entity.RoleAdmin), r.userHandler.ChangeRole)
+		private.POST("/images", r.imageHandler.UploadImage)
 		private.POST("/posts", r.postHandler.CreatePost)
 		private.PUT("/posts/:id", r.postHandler.EditPost)
+		private.PATCH("/posts/:id", r.postHandler.PatchPost)
 		private.DELETE("/posts/:id", r.postHandler.DeletePost)
+		private.POST("/posts/:id/restore", r.postHandler.RestorePost)
+		private.POST("/posts/:id/publish", r.postHandler.PublishPost)
+		private.POST("/posts/:id/reserve", r.postHandler.ReservePost)
+		private.POST("/posts/:id/mark-sold", r.postHandler.MarkPostSold)
+		private.POST("/posts/:id/archive", r.postHandler.ArchivePost)
 		private.GET("/users/:id/posts", r.postHandler.ListPostsByAuthor)
+		private.GET("/users/me/favorites", r.postHandler.ListFavorites)
+		private.POST("/posts/:id/favorite", r.postHandler.FavoritePost)
+		private.DELETE("/posts/:id/favorite", r.postHandler.UnfavoritePost)
+		private.POST("/saved-searches", r.searchHandler.CreateSavedSearch)
+		private.GET("/saved-searches", r.searchHandler.ListSavedSearches)
+		private.GET("/saved-searches/:id", r.searchHandler.GetSavedSearch)
+		private.PUT("/saved-searches/:id", r.searchHandler.UpdateSavedSearch)
+		private.DELETE("/saved-searches/:id", r.searchHandler.DeleteSavedSearch)
+		private.GET("/saved-searches/:id/matches", r.searchHandler.ListSavedSearchMatches)
+		private.POST("/saved-searches/:id/seen", r.searchHandler.MarkSavedSearchSeen)
+		private.GET("/notifications", r.notificationHandler.ListNotifications)
+		private.POST("/notifications/read", r.notificationHandler.MarkAllNotificationsRead)
+		private.POST("/notifications/:id/read", r.notificationHandler.MarkNotificationRead)
+		private.GET("/notifications/preferences", r.notificationHandler.GetNotificationPreferences)
+		private.PUT("/notifications/preferences", r.notificationHandler.UpdateNotificationPreferences)
+		private.POST("/posts/:id/conversations", r.conversationHandler.StartConversation)
+		private.GET("/conversations", r.conversationHandler.ListConversations)
+		private.GET("/conversations/:id", r.conversationHandler.GetConversation)
+		private.GET("/conversations/:id/messages", r.conversationHandler.ListMessages)
+		private.POST("/conversations/:id/messages", r.conversationHandler.SendMessage)
+		private.POST("/conversations/:id/read", r.conversationHandler.MarkConversationRead)
+		private.GET("/users/me/blocks", r.conversationHandler.ListBlockedUsers)
+		private.PUT("/users/me/blocks/:id", r.conversationHandler.BlockUser)
+		private.DELETE("/users/me/blocks/:id", r.conversationHandler.UnblockUser)
+		private.POST("/categories", r.authHandler.RequireRole(entity.RoleAdmin), r.categoryHandler.CreateCategory)
+		private.PUT("/categories/:id", r.authHandler.RequireRole(entity.RoleAdmin), r.categoryHandler.UpdateCategory)
+		private.DELETE("/categories/:id", r.authHandler.RequireRole(entity.RoleAdmin), r.categoryHandler.DeleteCategory)
+		private.PUT("/exchange-rates/:base/:quote", r.authHandler.RequireRole(entity.RoleAdmin), r.rateHandler.SetExchangeRate)
+		private.DELETE("/exchange-rates/:base/:quote", r.authHandler.RequireRole(entity.RoleAdmin), r.rateHandler.DeleteExchangeRate)
+		private.POST("/exchange-rates/import", r.authHandler.RequireRole(entity.RoleAdmin), r.rateHandler.ImportExchangeRates)
 	}
 
 	return ginRouter
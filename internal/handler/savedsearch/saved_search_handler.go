@@ -0,0 +1,13 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type SavedSearchHandlerInterface interface {
+	CreateSavedSearch(c *gin.Context)
+	GetSavedSearch(c *gin.Context)
+	ListSavedSearches(c *gin.Context)
+	UpdateSavedSearch(c *gin.Context)
+	DeleteSavedSearch(c *gin.Context)
+	ListSavedSearchMatches(c *gin.Context)
+	MarkSavedSearchSeen(c *gin.Context)
+}
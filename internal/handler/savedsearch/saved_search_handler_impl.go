@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+	service "marketplace/internal/service/savedsearch"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// savedSearchRequest — название поиска и его фильтры в виде query-строки
+// списка постов: "q=велосипед&max_price=5000&tag=kids".
+type savedSearchRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Query string `json:"query" binding:"required"`
+}
+
+type SavedSearchHandler struct {
+	searchSvc service.SavedSearchServiceInterface
+	logger    *logrus.Logger
+}
+
+func NewSavedSearchHandler(searchSvc service.SavedSearchServiceInterface, logger *logrus.Logger) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		searchSvc: searchSvc,
+		logger:    logger,
+	}
+}
+
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid create saved search request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	filter, err := searchFilter(req.Query)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search query")
+		c.Error(err)
+		return
+	}
+
+	search, err := h.searchSvc.CreateSavedSearch(c.Request.Context(), req.Name, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create saved search")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+	}).Info("Saved search created via handler")
+	c.JSON(http.StatusCreated, search)
+}
+
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search ID")
+		c.Error(apperror.Validation("invalid saved search ID"))
+		return
+	}
+
+	search, err := h.searchSvc.GetSavedSearch(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get saved search")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search fetched via handler")
+	c.JSON(http.StatusOK, search)
+}
+
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	searches, err := h.searchSvc.ListSavedSearches(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list saved searches")
+		c.Error(err)
+		return
+	}
+
+	h.logger.Info("Saved searches listed via handler")
+	c.JSON(http.StatusOK, gin.H{"saved_searches": searches})
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid update saved search request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search ID")
+		c.Error(apperror.Validation("invalid saved search ID"))
+		return
+	}
+
+	filter, err := searchFilter(req.Query)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search query")
+		c.Error(err)
+		return
+	}
+
+	search, err := h.searchSvc.UpdateSavedSearch(c.Request.Context(), id, req.Name, filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update saved search")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search updated via handler")
+	c.JSON(http.StatusOK, search)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search ID")
+		c.Error(apperror.Validation("invalid saved search ID"))
+		return
+	}
+
+	if err := h.searchSvc.DeleteSavedSearch(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to delete saved search")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search deleted via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+func (h *SavedSearchHandler) ListSavedSearchMatches(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search ID")
+		c.Error(apperror.Validation("invalid saved search ID"))
+		return
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	var currency entity.Currency
+	if code := c.Query("currency"); code != "" {
+		if currency, err = entity.ParseCurrency(code); err != nil {
+			h.logger.WithError(err).Error("Invalid currency")
+			c.Error(apperror.InvalidField("currency", "unsupported currency %q", code))
+			return
+		}
+	}
+
+	posts, total, err := h.searchSvc.ListSavedSearchMatches(c.Request.Context(), id, page, pageSize, currency)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list saved search matches")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+		"page":            page,
+		"page_size":       pageSize,
+		"total_posts":     total,
+	}).Info("Saved search matches listed via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"posts":     posts,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func (h *SavedSearchHandler) MarkSavedSearchSeen(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid saved search ID")
+		c.Error(apperror.Validation("invalid saved search ID"))
+		return
+	}
+
+	seen, err := h.searchSvc.MarkSavedSearchSeen(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to mark saved search matches seen")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+		"seen":            seen,
+	}).Info("Saved search matches marked as seen via handler")
+	c.JSON(http.StatusOK, gin.H{"seen": seen})
+}
+
+// searchFilter разбирает query-строку сохранённого поиска теми же правилами,
+// что и параметры списка постов.
+func searchFilter(query string) (map[string]string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, apperror.InvalidField("query", "query must be a URL query string")
+	}
+	return entity.ParsePostFilter(values)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) CreateSavedSearch(ctx context.Context, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	args := m.Called(ctx, name, filter)
+	search, _ := args.Get(0).(*entity.SavedSearch)
+	return search, args.Error(1)
+}
+
+func (m *MockSavedSearchService) GetSavedSearch(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error) {
+	args := m.Called(ctx, id)
+	search, _ := args.Get(0).(*entity.SavedSearch)
+	return search, args.Error(1)
+}
+
+func (m *MockSavedSearchService) ListSavedSearches(ctx context.Context) ([]*entity.SavedSearch, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entity.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) UpdateSavedSearch(ctx context.Context, id uuid.UUID, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	args := m.Called(ctx, id, name, filter)
+	search, _ := args.Get(0).(*entity.SavedSearch)
+	return search, args.Error(1)
+}
+
+func (m *MockSavedSearchService) DeleteSavedSearch(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) ListSavedSearchMatches(ctx context.Context, id uuid.UUID, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	args := m.Called(ctx, id, page, pageSize, currency)
+	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
+}
+
+func (m *MockSavedSearchService) MarkSavedSearchSeen(ctx context.Context, id uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestCreateSavedSearchHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockSearchSvc := new(MockSavedSearchService)
+	logger := logrus.New()
+	handler := NewSavedSearchHandler(mockSearchSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.POST("/saved-searches", handler.CreateSavedSearch)
+
+	// Фильтры разбираются так же, как параметры GET /posts.
+	filter := map[string]string{"q": "велосипед", "max_price": "5000", "tag": "red,kids", "attr.size": "42"}
+	expected := &entity.SavedSearch{ID: uuid.New(), Name: "Велосипеды", Filter: filter}
+	mockSearchSvc.On("CreateSavedSearch", mock.Anything, "Велосипеды", filter).Return(expected, nil)
+
+	body, _ := json.Marshal(map[string]string{"name": "Велосипеды", "query": "?q=%D0%B2%D0%B5%D0%BB%D0%BE%D1%81%D0%B8%D0%BF%D0%B5%D0%B4&max_price=5000&tag=Red&tag=kids&attr.size=42"})
+	req, _ := http.NewRequest("POST", "/saved-searches", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSearchSvc.AssertExpectations(t)
+
+	for _, query := range []string{"q=%zz", "currency=XXX&max_price=10", "attr.Size=42"} {
+		body, _ := json.Marshal(map[string]string{"name": "Велосипеды", "query": query})
+		req, _ := http.NewRequest("POST", "/saved-searches", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockSearchSvc.AssertNumberOfCalls(t, "CreateSavedSearch", 1)
+}
+
+func TestListSavedSearchMatchesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	mockSearchSvc := new(MockSavedSearchService)
+	logger := logrus.New()
+	handler := NewSavedSearchHandler(mockSearchSvc, logger)
+	r.Use(httperror.Middleware(logger))
+
+	r.GET("/saved-searches/:id/matches", handler.ListSavedSearchMatches)
+
+	id := uuid.New()
+	posts := []*entity.Post{{ID: uuid.New(), Header: "Детский велосипед"}}
+	mockSearchSvc.On("ListSavedSearchMatches", mock.Anything, id, 2, 5, entity.Currency("USD")).Return(posts, 6, nil)
+
+	req, _ := http.NewRequest("GET", "/saved-searches/"+id.String()+"/matches?page=2&pageSize=5&currency=usd", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Posts []*entity.Post `json:"posts"`
+		Total int            `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Posts, 1)
+	assert.Equal(t, 6, resp.Total)
+	mockSearchSvc.AssertExpectations(t)
+}
@@ -7,5 +7,9 @@ type UserHandlerInterface interface {
 	Login(c *gin.Context)
 	GetUser(c *gin.Context)
 	UpdateUser(c *gin.Context)
+	PatchUser(c *gin.Context)
 	DeleteUser(c *gin.Context)
+	RestoreUser(c *gin.Context)
+	RestoreAccount(c *gin.Context)
+	ChangeRole(c *gin.Context)
 }
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/handler/httperror"
 	service "marketplace/internal/service/user"
+	"marketplace/pkg/mergepatch"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -29,14 +32,14 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Invalid register request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(httperror.Binding(err))
 		return
 	}
 
-	user, token, err := h.userSvc.Register(c.Request.Context(), req.Username, req.Password)
+	user, tokens, err := h.userSvc.Register(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to register user")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -44,7 +47,12 @@ func (h *UserHandler) Register(c *gin.Context) {
 		"user_id":  user.ID,
 		"username": user.Username,
 	}).Info("User registered via handler")
-	c.JSON(http.StatusCreated, gin.H{"user": user, "token": token})
+	c.JSON(http.StatusCreated, gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
 }
 
 func (h *UserHandler) Login(c *gin.Context) {
@@ -54,14 +62,14 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Invalid login request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(httperror.Binding(err))
 		return
 	}
 
-	user, token, err := h.userSvc.Login(c.Request.Context(), req.Username, req.Password)
+	user, tokens, err := h.userSvc.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to login user")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -69,7 +77,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 		"user_id":  user.ID,
 		"username": user.Username,
 	}).Info("User logged in via handler")
-	c.JSON(http.StatusOK, gin.H{"user": user, "token": token})
+	c.JSON(http.StatusOK, gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
 }
 
 func (h *UserHandler) GetUser(c *gin.Context) {
@@ -77,18 +90,14 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid user ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(apperror.Validation("invalid user ID"))
 		return
 	}
 
 	user, err := h.userSvc.GetUser(c.Request.Context(), id)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get user")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -105,7 +114,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Invalid update user request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(httperror.Binding(err))
 		return
 	}
 
@@ -113,17 +122,13 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid user ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(apperror.Validation("invalid user ID"))
 		return
 	}
 
 	if err := h.userSvc.UpdateUser(c.Request.Context(), id, req.Username, req.Password); err != nil {
 		h.logger.WithError(err).Error("Failed to update user")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -133,22 +138,46 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
 }
 
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid user ID")
+		c.Error(apperror.Validation("invalid user ID"))
+		return
+	}
+
+	patch, err := mergepatch.ReadRequest(c.Writer, c.Request)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid patch user request")
+		c.Error(httperror.MergePatch(err))
+		return
+	}
+
+	if err := h.userSvc.PatchUser(c.Request.Context(), id, patch); err != nil {
+		h.logger.WithError(err).Error("Failed to patch user")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id": id,
+	}).Info("User patched via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		h.logger.WithError(err).Error("Invalid user ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(apperror.Validation("invalid user ID"))
 		return
 	}
 
 	if err := h.userSvc.DeleteUser(c.Request.Context(), id); err != nil {
 		h.logger.WithError(err).Error("Failed to delete user")
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -157,3 +186,87 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	}).Info("User deleted via handler")
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
+
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid user ID")
+		c.Error(apperror.Validation("invalid user ID"))
+		return
+	}
+
+	if err := h.userSvc.RestoreUser(c.Request.Context(), id); err != nil {
+		h.logger.WithError(err).Error("Failed to restore user")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id": id,
+	}).Info("User restored via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "User restored successfully"})
+}
+
+// RestoreAccount восстанавливает собственный удалённый аккаунт по логину и
+// паролю и сразу начинает новую сессию, как Login.
+func (h *UserHandler) RestoreAccount(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required,min=3,max=50"`
+		Password string `json:"password" binding:"required,min=8,max=100"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid restore account request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	user, tokens, err := h.userSvc.RestoreAccount(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to restore user account")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id":  user.ID,
+		"username": user.Username,
+	}).Info("User account restored via handler")
+	c.JSON(http.StatusOK, gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	var req struct {
+		Role entity.Role `json:"role" binding:"required,oneof=user moderator admin"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Invalid change role request")
+		c.Error(httperror.Binding(err))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.logger.WithError(err).Error("Invalid user ID")
+		c.Error(apperror.Validation("invalid user ID"))
+		return
+	}
+
+	if err := h.userSvc.ChangeUserRole(c.Request.Context(), id, req.Role); err != nil {
+		h.logger.WithError(err).Error("Failed to change user role")
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id": id,
+		"role":    req.Role,
+	}).Info("User role changed via handler")
+	c.JSON(http.StatusOK, gin.H{"message": "User role changed successfully"})
+}
@@ -21,14 +21,14 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (m *MockUserService) Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	args := m.Called(ctx, username, password)
-	return args.Get(0).(*entity.UserDTO), args.String(1), args.Error(2)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
 }
 
-func (m *MockUserService) Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (m *MockUserService) Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	args := m.Called(ctx, username, password)
-	return args.Get(0).(*entity.UserDTO), args.String(1), args.Error(2)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
 }
 
 func (m *MockUserService) GetUser(ctx context.Context, id uuid.UUID) (*entity.UserDTO, error) {
@@ -41,11 +41,31 @@ func (m *MockUserService) UpdateUser(ctx context.Context, id uuid.UUID, username
 	return args.Error(0)
 }
 
+func (m *MockUserService) PatchUser(ctx context.Context, id uuid.UUID, patch []byte) error {
+	args := m.Called(ctx, id, patch)
+	return args.Error(0)
+}
+
 func (m *MockUserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
+}
+
+func (m *MockUserService) ChangeUserRole(ctx context.Context, id uuid.UUID, role entity.Role) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
 func TestRegisterUserHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
@@ -71,9 +91,9 @@ func TestRegisterUserHandler(t *testing.T) {
 		ID:       uuid.New(),
 		Username: "testuser",
 	}
-	token := "fake-jwt-token"
+	tokens := &entity.TokenPair{AccessToken: "fake-jwt-token", RefreshToken: "fake-refresh-token", ExpiresIn: 900}
 	mockUserSvc.On("Register", mock.Anything, "testuser", "SecurePass123!").
-		Return(user, token, nil)
+		Return(user, tokens, nil)
 
 	r.ServeHTTP(w, req)
 
@@ -1,12 +1,19 @@
 package service
 
 import (
+	"context"
+	"marketplace/internal/entity"
+
 	"github.com/google/uuid"
 )
 
 type AuthServiceInterface interface {
-	GenerateJWT(userID uuid.UUID) (string, error)
-	ValidateJWT(tokenString string) (uuid.UUID, error)
+	GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error)
+	ValidateJWT(tokenString string) (*entity.TokenClaims, error)
+	JWKS() *entity.JSONWebKeySet
 	VerifyPassword(hashedPassword, inputPassword string) error
 	GeneratePasswordHash(password string) (string, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
 }
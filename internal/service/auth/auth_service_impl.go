@@ -1,7 +1,9 @@
 package service
 
 import (
-	"fmt"
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
 	usecase "marketplace/internal/usecase/auth"
 
 	"github.com/google/uuid"
@@ -9,23 +11,25 @@ import (
 )
 
 type AuthService struct {
-	authRepo usecase.AuthService
-	logger   *logrus.Logger
+	authRepo    usecase.AuthService
+	sessionRepo usecase.SessionUseCaseRepo
+	logger      *logrus.Logger
 }
 
-func NewAuthService(authRepo usecase.AuthService, logger *logrus.Logger) *AuthService {
+func NewAuthService(authRepo usecase.AuthService, sessionRepo usecase.SessionUseCaseRepo, logger *logrus.Logger) *AuthService {
 	return &AuthService{
-		authRepo: authRepo,
-		logger:   logger,
+		authRepo:    authRepo,
+		sessionRepo: sessionRepo,
+		logger:      logger,
 	}
 }
 
-func (s *AuthService) GenerateJWT(userID uuid.UUID) (string, error) {
-	if userID == uuid.Nil {
-		return "", fmt.Errorf("userID cannot be empty")
+func (s *AuthService) GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error) {
+	if userID == uuid.Nil || sessionID == uuid.Nil {
+		return "", apperror.Validation("userID and sessionID cannot be empty")
 	}
 
-	token, err := s.authRepo.GenerateJWT(userID)
+	token, err := s.authRepo.GenerateJWT(userID, sessionID, role)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to generate JWT")
 		return "", err
@@ -38,27 +42,32 @@ func (s *AuthService) GenerateJWT(userID uuid.UUID) (string, error) {
 	return token, nil
 }
 
-func (s *AuthService) ValidateJWT(tokenString string) (uuid.UUID, error) {
+func (s *AuthService) ValidateJWT(tokenString string) (*entity.TokenClaims, error) {
 	if tokenString == "" {
-		return uuid.Nil, fmt.Errorf("token cannot be empty")
+		return nil, apperror.Validation("token cannot be empty")
 	}
 
-	userID, err := s.authRepo.ValidateJWT(tokenString)
+	claims, err := s.authRepo.ValidateJWT(tokenString)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to validate JWT")
-		return uuid.Nil, err
+		return nil, err
 	}
 
 	s.logger.WithFields(logrus.Fields{
-		"user_id": userID,
+		"user_id":    claims.UserID,
+		"session_id": claims.SessionID,
 	}).Info("JWT validated successfully")
 
-	return userID, nil
+	return claims, nil
+}
+
+func (s *AuthService) JWKS() *entity.JSONWebKeySet {
+	return s.authRepo.JWKS()
 }
 
 func (s *AuthService) VerifyPassword(hashedPassword, inputPassword string) error {
 	if hashedPassword == "" || inputPassword == "" {
-		return fmt.Errorf("hashed password and input password cannot be empty")
+		return apperror.Validation("hashed password and input password cannot be empty")
 	}
 
 	err := s.authRepo.VerifyPassword(hashedPassword, inputPassword)
@@ -73,7 +82,7 @@ func (s *AuthService) VerifyPassword(hashedPassword, inputPassword string) error
 
 func (s *AuthService) GeneratePasswordHash(password string) (string, error) {
 	if password == "" {
-		return "", fmt.Errorf("password cannot be empty")
+		return "", apperror.Validation("password cannot be empty")
 	}
 
 	hashedPassword, err := s.authRepo.GeneratePasswordHash(password)
@@ -85,3 +94,44 @@ func (s *AuthService) GeneratePasswordHash(password string) (string, error) {
 	s.logger.Info("Password hash generated successfully")
 	return hashedPassword, nil
 }
+
+func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, apperror.Validation("refresh token cannot be empty")
+	}
+
+	tokens, err := s.sessionRepo.Refresh(ctx, refreshToken)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to refresh tokens")
+		return nil, err
+	}
+
+	s.logger.Info("Tokens refreshed successfully")
+	return tokens, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	if sessionID == uuid.Nil {
+		return apperror.Validation("sessionID cannot be empty")
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		s.logger.WithError(err).Error("Failed to logout")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"session_id": sessionID,
+	}).Info("Logged out successfully")
+
+	return nil
+}
+
+func (s *AuthService) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	revoked, err := s.sessionRepo.IsRevoked(ctx, sessionID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to check session revocation")
+		return false, err
+	}
+	return revoked, nil
+}
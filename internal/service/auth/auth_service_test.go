@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"testing"
 
+	"marketplace/internal/entity"
+
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -13,14 +16,19 @@ type MockAuthUseCase struct {
 	mock.Mock
 }
 
-func (m *MockAuthUseCase) GenerateJWT(userID uuid.UUID) (string, error) {
-	args := m.Called(userID)
+func (m *MockAuthUseCase) GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error) {
+	args := m.Called(userID, sessionID, role)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthUseCase) ValidateJWT(tokenString string) (uuid.UUID, error) {
+func (m *MockAuthUseCase) ValidateJWT(tokenString string) (*entity.TokenClaims, error) {
 	args := m.Called(tokenString)
-	return args.Get(0).(uuid.UUID), args.Error(1)
+	return args.Get(0).(*entity.TokenClaims), args.Error(1)
+}
+
+func (m *MockAuthUseCase) JWKS() *entity.JSONWebKeySet {
+	args := m.Called()
+	return args.Get(0).(*entity.JSONWebKeySet)
 }
 
 func (m *MockAuthUseCase) VerifyPassword(hashedPassword, inputPassword string) error {
@@ -33,17 +41,42 @@ func (m *MockAuthUseCase) GeneratePasswordHash(password string) (string, error)
 	return args.String(0), args.Error(1)
 }
 
+type MockSessionUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSessionUseCase) Start(ctx context.Context, user *entity.User) (*entity.TokenPair, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(*entity.TokenPair), args.Error(1)
+}
+
+func (m *MockSessionUseCase) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(*entity.TokenPair), args.Error(1)
+}
+
+func (m *MockSessionUseCase) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionUseCase) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestGenerateJWT(t *testing.T) {
 	mockUsecase := new(MockAuthUseCase)
 	logger := logrus.New()
-	authService := NewAuthService(mockUsecase, logger)
+	authService := NewAuthService(mockUsecase, new(MockSessionUseCase), logger)
 
 	userID := uuid.New()
+	sessionID := uuid.New()
 	expectedToken := "fake-jwt-token"
 
-	mockUsecase.On("GenerateJWT", userID).Return(expectedToken, nil)
+	mockUsecase.On("GenerateJWT", userID, sessionID, entity.RoleUser).Return(expectedToken, nil)
 
-	token, err := authService.GenerateJWT(userID)
+	token, err := authService.GenerateJWT(userID, sessionID, entity.RoleUser)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedToken, token)
 	mockUsecase.AssertExpectations(t)
@@ -52,23 +85,23 @@ func TestGenerateJWT(t *testing.T) {
 func TestValidateJWT(t *testing.T) {
 	mockUsecase := new(MockAuthUseCase)
 	logger := logrus.New()
-	authService := NewAuthService(mockUsecase, logger)
+	authService := NewAuthService(mockUsecase, new(MockSessionUseCase), logger)
 
 	token := "valid-jwt-token"
-	expectedUserID := uuid.New()
+	expectedClaims := &entity.TokenClaims{UserID: uuid.New(), SessionID: uuid.New()}
 
-	mockUsecase.On("ValidateJWT", token).Return(expectedUserID, nil)
+	mockUsecase.On("ValidateJWT", token).Return(expectedClaims, nil)
 
-	userID, err := authService.ValidateJWT(token)
+	claims, err := authService.ValidateJWT(token)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedUserID, userID)
+	assert.Equal(t, expectedClaims, claims)
 	mockUsecase.AssertExpectations(t)
 }
 
 func TestVerifyPassword(t *testing.T) {
 	mockUsecase := new(MockAuthUseCase)
 	logger := logrus.New()
-	authService := NewAuthService(mockUsecase, logger)
+	authService := NewAuthService(mockUsecase, new(MockSessionUseCase), logger)
 
 	hashedPassword := "hashed-pass"
 	inputPassword := "input-pass"
@@ -83,7 +116,7 @@ func TestVerifyPassword(t *testing.T) {
 func TestGeneratePasswordHash(t *testing.T) {
 	mockUsecase := new(MockAuthUseCase)
 	logger := logrus.New()
-	authService := NewAuthService(mockUsecase, logger)
+	authService := NewAuthService(mockUsecase, new(MockSessionUseCase), logger)
 
 	password := "SecurePass123!"
 	expectedHash := "hashed-password"
@@ -95,3 +128,35 @@ func TestGeneratePasswordHash(t *testing.T) {
 	assert.Equal(t, expectedHash, hash)
 	mockUsecase.AssertExpectations(t)
 }
+
+func TestRefreshTokens(t *testing.T) {
+	mockSession := new(MockSessionUseCase)
+	logger := logrus.New()
+	authService := NewAuthService(new(MockAuthUseCase), mockSession, logger)
+
+	expectedTokens := &entity.TokenPair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}
+
+	mockSession.On("Refresh", mock.Anything, "old-refresh").Return(expectedTokens, nil)
+
+	tokens, err := authService.RefreshTokens(context.Background(), "old-refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTokens, tokens)
+
+	_, err = authService.RefreshTokens(context.Background(), "")
+	assert.Error(t, err)
+	mockSession.AssertExpectations(t)
+}
+
+func TestLogout(t *testing.T) {
+	mockSession := new(MockSessionUseCase)
+	logger := logrus.New()
+	authService := NewAuthService(new(MockAuthUseCase), mockSession, logger)
+
+	sessionID := uuid.New()
+
+	mockSession.On("Revoke", mock.Anything, sessionID).Return(nil)
+
+	err := authService.Logout(context.Background(), sessionID)
+	assert.NoError(t, err)
+	mockSession.AssertExpectations(t)
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type CategoryServiceInterface interface {
+	CreateCategory(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*entity.Category, error)
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseCategory "marketplace/internal/usecase/category"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryService struct {
+	categoryUsecase usecaseCategory.CategoryUseCaseRepo
+	logger          *logrus.Logger
+}
+
+func NewCategoryService(categoryUsecase usecaseCategory.CategoryUseCaseRepo, logger *logrus.Logger) *CategoryService {
+	return &CategoryService{
+		categoryUsecase: categoryUsecase,
+		logger:          logger,
+	}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	if name == "" || slug == "" {
+		return nil, apperror.Validation("name and slug are required")
+	}
+
+	category, err := s.categoryUsecase.Create(ctx, name, slug, parentID, attributes)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create category")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"category_id": category.ID,
+		"slug":        category.Slug,
+	}).Info("Category created successfully")
+
+	return category, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	category, err := s.categoryUsecase.Get(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get category")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category fetched successfully")
+
+	return category, nil
+}
+
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := s.categoryUsecase.Tree(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list categories")
+		return nil, err
+	}
+
+	s.logger.Info("Categories listed successfully")
+
+	return categories, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	if name == "" || slug == "" {
+		return nil, apperror.Validation("name and slug are required")
+	}
+
+	category, err := s.categoryUsecase.Update(ctx, id, name, slug, parentID, attributes)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to update category")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category updated successfully")
+
+	return category, nil
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if err := s.categoryUsecase.Delete(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to delete category")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"category_id": id,
+	}).Info("Category deleted successfully")
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCategoryUseCase) Create(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	args := m.Called(ctx, name, slug, parentID, attributes)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Get(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	args := m.Called(ctx, id)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Tree(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Update(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	args := m.Called(ctx, id, name, slug, parentID, attributes)
+	category, _ := args.Get(0).(*entity.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateCategory(t *testing.T) {
+	mockUsecase := new(MockCategoryUseCase)
+	logger := logrus.New()
+	categoryService := NewCategoryService(mockUsecase, logger)
+
+	parentID := uuid.New()
+	expected := &entity.Category{ID: uuid.New(), ParentID: &parentID, Name: "Bicycles", Slug: "bicycles", CreatedAt: time.Now()}
+	mockUsecase.On("Create", mock.Anything, "Bicycles", "bicycles", &parentID, []entity.Attribute(nil)).Return(expected, nil)
+
+	result, err := categoryService.CreateCategory(context.Background(), "Bicycles", "bicycles", &parentID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	_, err = categoryService.CreateCategory(context.Background(), "", "bicycles", nil, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestListCategories(t *testing.T) {
+	mockUsecase := new(MockCategoryUseCase)
+	logger := logrus.New()
+	categoryService := NewCategoryService(mockUsecase, logger)
+
+	tree := []*entity.Category{{ID: uuid.New(), Name: "Sport", Slug: "sport"}}
+	mockUsecase.On("Tree", mock.Anything).Return(tree, nil)
+
+	result, err := categoryService.ListCategories(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, tree, result)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	mockUsecase := new(MockCategoryUseCase)
+	logger := logrus.New()
+	categoryService := NewCategoryService(mockUsecase, logger)
+
+	id := uuid.New()
+	mockUsecase.On("Delete", mock.Anything, id).Return(apperror.Conflict("category has subcategories or posts"))
+
+	err := categoryService.DeleteCategory(context.Background(), id)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type ConversationServiceInterface interface {
+	StartConversation(ctx context.Context, postID uuid.UUID, body string) (*entity.Conversation, *entity.Message, error)
+	ListConversations(ctx context.Context, page, pageSize int) ([]*entity.Conversation, int, error)
+	GetConversation(ctx context.Context, id uuid.UUID) (*entity.Conversation, error)
+	SendMessage(ctx context.Context, id uuid.UUID, body string) (*entity.Message, error)
+	ListMessages(ctx context.Context, id uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, *entity.MessageCursor, error)
+	MarkConversationRead(ctx context.Context, id uuid.UUID) (int64, error)
+	BlockUser(ctx context.Context, userID uuid.UUID) error
+	UnblockUser(ctx context.Context, userID uuid.UUID) error
+	ListBlockedUsers(ctx context.Context) ([]*entity.BlockedUser, error)
+}
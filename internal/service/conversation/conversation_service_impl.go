@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseConversation "marketplace/internal/usecase/conversation"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ConversationService struct {
+	conversationUsecase usecaseConversation.ConversationUseCaseRepo
+	logger              *logrus.Logger
+}
+
+func NewConversationService(conversationUsecase usecaseConversation.ConversationUseCaseRepo, logger *logrus.Logger) *ConversationService {
+	return &ConversationService{
+		conversationUsecase: conversationUsecase,
+		logger:              logger,
+	}
+}
+
+func (s *ConversationService) StartConversation(ctx context.Context, postID uuid.UUID, body string) (*entity.Conversation, *entity.Message, error) {
+	conversation, message, err := s.conversationUsecase.Start(ctx, postID, body)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to start conversation")
+		return nil, nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"conversation_id": conversation.ID,
+		"post_id":         postID,
+	}).Info("Conversation started successfully")
+
+	return conversation, message, nil
+}
+
+func (s *ConversationService) ListConversations(ctx context.Context, page, pageSize int) ([]*entity.Conversation, int, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, apperror.Validation("invalid pagination parameters")
+	}
+
+	conversations, total, err := s.conversationUsecase.List(ctx, page, pageSize)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list conversations")
+		return nil, 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	}).Info("Conversations listed successfully")
+
+	return conversations, total, nil
+}
+
+func (s *ConversationService) GetConversation(ctx context.Context, id uuid.UUID) (*entity.Conversation, error) {
+	conversation, err := s.conversationUsecase.Get(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get conversation")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+	}).Info("Conversation retrieved successfully")
+
+	return conversation, nil
+}
+
+func (s *ConversationService) SendMessage(ctx context.Context, id uuid.UUID, body string) (*entity.Message, error) {
+	message, err := s.conversationUsecase.SendMessage(ctx, id, body)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to send message")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"message_id":      message.ID,
+	}).Info("Message sent successfully")
+
+	return message, nil
+}
+
+func (s *ConversationService) ListMessages(ctx context.Context, id uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, *entity.MessageCursor, error) {
+	if limit < 1 || limit > entity.MaxMessagePageSize {
+		return nil, nil, apperror.InvalidField("limit", "limit must be between 1 and %d", entity.MaxMessagePageSize)
+	}
+
+	messages, next, err := s.conversationUsecase.ListMessages(ctx, id, cursor, limit)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list messages")
+		return nil, nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"limit":           limit,
+		"count":           len(messages),
+	}).Info("Messages listed successfully")
+
+	return messages, next, nil
+}
+
+func (s *ConversationService) MarkConversationRead(ctx context.Context, id uuid.UUID) (int64, error) {
+	count, err := s.conversationUsecase.MarkRead(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to mark conversation read")
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"conversation_id": id,
+		"count":           count,
+	}).Info("Conversation marked as read successfully")
+
+	return count, nil
+}
+
+func (s *ConversationService) BlockUser(ctx context.Context, userID uuid.UUID) error {
+	if err := s.conversationUsecase.Block(ctx, userID); err != nil {
+		s.logger.WithError(err).Error("Failed to block user")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"blocked_user_id": userID,
+	}).Info("User blocked successfully")
+
+	return nil
+}
+
+func (s *ConversationService) UnblockUser(ctx context.Context, userID uuid.UUID) error {
+	if err := s.conversationUsecase.Unblock(ctx, userID); err != nil {
+		s.logger.WithError(err).Error("Failed to unblock user")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"blocked_user_id": userID,
+	}).Info("User unblocked successfully")
+
+	return nil
+}
+
+func (s *ConversationService) ListBlockedUsers(ctx context.Context) ([]*entity.BlockedUser, error) {
+	blocked, err := s.conversationUsecase.ListBlocked(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list blocked users")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(blocked),
+	}).Info("Blocked users listed successfully")
+
+	return blocked, nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/entity"
+)
+
+type ExchangeRateServiceInterface interface {
+	ListExchangeRates(ctx context.Context) ([]*entity.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, base, quote entity.Currency, rate string) (*entity.ExchangeRate, error)
+	ImportExchangeRates(ctx context.Context, r io.Reader) (int, error)
+	DeleteExchangeRate(ctx context.Context, base, quote entity.Currency) error
+}
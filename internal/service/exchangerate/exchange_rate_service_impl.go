@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseExchangeRate "marketplace/internal/usecase/exchangerate"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ExchangeRateService struct {
+	rateUsecase usecaseExchangeRate.ExchangeRateUseCaseRepo
+	logger      *logrus.Logger
+}
+
+func NewExchangeRateService(rateUsecase usecaseExchangeRate.ExchangeRateUseCaseRepo, logger *logrus.Logger) *ExchangeRateService {
+	return &ExchangeRateService{
+		rateUsecase: rateUsecase,
+		logger:      logger,
+	}
+}
+
+func (s *ExchangeRateService) ListExchangeRates(ctx context.Context) ([]*entity.ExchangeRate, error) {
+	rates, err := s.rateUsecase.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list exchange rates")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(rates),
+	}).Info("Exchange rates listed successfully")
+
+	return rates, nil
+}
+
+func (s *ExchangeRateService) SetExchangeRate(ctx context.Context, base, quote entity.Currency, rate string) (*entity.ExchangeRate, error) {
+	if rate == "" {
+		return nil, apperror.Validation("rate is required")
+	}
+
+	exchangeRate, err := s.rateUsecase.Set(ctx, base, quote, rate)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to set exchange rate")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"base":  base,
+		"quote": quote,
+	}).Info("Exchange rate set successfully")
+
+	return exchangeRate, nil
+}
+
+func (s *ExchangeRateService) ImportExchangeRates(ctx context.Context, r io.Reader) (int, error) {
+	count, err := s.rateUsecase.Import(ctx, r)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to import exchange rates")
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": count,
+	}).Info("Exchange rates imported successfully")
+
+	return count, nil
+}
+
+func (s *ExchangeRateService) DeleteExchangeRate(ctx context.Context, base, quote entity.Currency) error {
+	if err := s.rateUsecase.Delete(ctx, base, quote); err != nil {
+		s.logger.WithError(err).Error("Failed to delete exchange rate")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"base":  base,
+		"quote": quote,
+	}).Info("Exchange rate deleted successfully")
+
+	return nil
+}
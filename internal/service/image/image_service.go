@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type ImageServiceInterface interface {
+	UploadImage(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error)
+	GetImage(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error)
+	GetImageVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseImage "marketplace/internal/usecase/image"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ImageService struct {
+	imageUsecase usecaseImage.ImageUseCaseRepo
+	logger       *logrus.Logger
+}
+
+func NewImageService(imageUsecase usecaseImage.ImageUseCaseRepo, logger *logrus.Logger) *ImageService {
+	return &ImageService{
+		imageUsecase: imageUsecase,
+		logger:       logger,
+	}
+}
+
+func (s *ImageService) UploadImage(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error) {
+	if len(data) == 0 {
+		return nil, apperror.InvalidField("file", "file can't be empty")
+	}
+
+	image, err := s.imageUsecase.Upload(ctx, ownerID, data)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to upload image")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"image_id": image.ID,
+		"owner_id": ownerID,
+	}).Info("Image uploaded successfully")
+
+	return image, nil
+}
+
+func (s *ImageService) GetImage(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error) {
+	image, reader, err := s.imageUsecase.Open(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get image")
+		return nil, nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"image_id": id,
+	}).Info("Image fetched successfully")
+
+	return image, reader, nil
+}
+
+func (s *ImageService) GetImageVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error) {
+	variant, reader, err := s.imageUsecase.OpenVariant(ctx, id, width, format)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get image variant")
+		return nil, nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"image_id": id,
+		"width":    width,
+		"format":   format,
+	}).Info("Image variant fetched successfully")
+
+	return variant, reader, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImageUseCase) Upload(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error) {
+	args := m.Called(ctx, ownerID, data)
+	image, _ := args.Get(0).(*entity.Image)
+	return image, args.Error(1)
+}
+
+func (m *MockImageUseCase) Open(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error) {
+	args := m.Called(ctx, id)
+	image, _ := args.Get(0).(*entity.Image)
+	reader, _ := args.Get(1).(io.ReadCloser)
+	return image, reader, args.Error(2)
+}
+
+func (m *MockImageUseCase) OpenVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error) {
+	args := m.Called(ctx, id, width, format)
+	variant, _ := args.Get(0).(*entity.ImageVariant)
+	reader, _ := args.Get(1).(io.ReadCloser)
+	return variant, reader, args.Error(2)
+}
+
+func TestUploadImage(t *testing.T) {
+	mockUsecase := new(MockImageUseCase)
+	logger := logrus.New()
+	imageService := NewImageService(mockUsecase, logger)
+
+	ownerID := uuid.New()
+	data := []byte("\x89PNG\r\n\x1a\n")
+	expected := &entity.Image{ID: uuid.New(), OwnerID: ownerID, ContentType: entity.ImagePNG}
+	mockUsecase.On("Upload", mock.Anything, ownerID, data).Return(expected, nil)
+
+	result, err := imageService.UploadImage(context.Background(), ownerID, data)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	_, err = imageService.UploadImage(context.Background(), ownerID, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetImage_NotFound(t *testing.T) {
+	mockUsecase := new(MockImageUseCase)
+	logger := logrus.New()
+	imageService := NewImageService(mockUsecase, logger)
+
+	id := uuid.New()
+	mockUsecase.On("Open", mock.Anything, id).Return(nil, nil, apperror.NotFound("image not found"))
+
+	_, _, err := imageService.GetImage(context.Background(), id)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	mockUsecase.AssertExpectations(t)
+}
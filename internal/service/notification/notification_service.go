@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type NotificationServiceInterface interface {
+	ListNotifications(ctx context.Context, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, int, error)
+	MarkNotificationRead(ctx context.Context, id uuid.UUID) error
+	MarkAllNotificationsRead(ctx context.Context) (int64, error)
+	GetNotificationPreferences(ctx context.Context) (entity.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, preferences entity.NotificationPreferences) (entity.NotificationPreferences, error)
+}
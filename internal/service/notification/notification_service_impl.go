@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseNotification "marketplace/internal/usecase/notification"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationService struct {
+	notificationUsecase usecaseNotification.NotificationUseCaseRepo
+	logger              *logrus.Logger
+}
+
+func NewNotificationService(notificationUsecase usecaseNotification.NotificationUseCaseRepo, logger *logrus.Logger) *NotificationService {
+	return &NotificationService{
+		notificationUsecase: notificationUsecase,
+		logger:              logger,
+	}
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, int, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, 0, apperror.Validation("invalid pagination parameters")
+	}
+
+	notifications, total, unread, err := s.notificationUsecase.List(ctx, unreadOnly, page, pageSize)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list notifications")
+		return nil, 0, 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+		"unread":    unread,
+	}).Info("Notifications listed successfully")
+
+	return notifications, total, unread, nil
+}
+
+func (s *NotificationService) MarkNotificationRead(ctx context.Context, id uuid.UUID) error {
+	if err := s.notificationUsecase.MarkRead(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to mark notification read")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"notification_id": id,
+	}).Info("Notification marked as read successfully")
+
+	return nil
+}
+
+func (s *NotificationService) MarkAllNotificationsRead(ctx context.Context) (int64, error) {
+	count, err := s.notificationUsecase.MarkAllRead(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to mark all notifications read")
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": count,
+	}).Info("All notifications marked as read successfully")
+
+	return count, nil
+}
+
+func (s *NotificationService) GetNotificationPreferences(ctx context.Context) (entity.NotificationPreferences, error) {
+	preferences, err := s.notificationUsecase.GetPreferences(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get notification preferences")
+		return nil, err
+	}
+
+	s.logger.Info("Notification preferences fetched successfully")
+	return preferences, nil
+}
+
+func (s *NotificationService) UpdateNotificationPreferences(ctx context.Context, preferences entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	if len(preferences) == 0 {
+		return nil, apperror.Validation("at least one notification kind is required")
+	}
+
+	updated, err := s.notificationUsecase.UpdatePreferences(ctx, preferences)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to update notification preferences")
+		return nil, err
+	}
+
+	s.logger.Info("Notification preferences updated successfully")
+	return updated, nil
+}
@@ -3,15 +3,27 @@ package service
 import (
 	"context"
 	"marketplace/internal/entity"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
 
 	"github.com/google/uuid"
 )
 
 type PostServiceInterface interface {
-	CreatePost(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error)
-	EditPost(ctx context.Context, postID uuid.UUID, header, content, image string, price float64) (*entity.Post, error)
+	CreatePost(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error)
+	EditPost(ctx context.Context, postID uuid.UUID, version int, params entity.PostParams) (*entity.Post, error)
+	PatchPost(ctx context.Context, postID uuid.UUID, version int, patch []byte) (*entity.Post, error)
+	ChangePostStatus(ctx context.Context, postID uuid.UUID, status entity.PostStatus) (*entity.Post, error)
 	DeletePost(ctx context.Context, postID uuid.UUID) error
-	GetPost(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	RestorePost(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	GetPost(ctx context.Context, postID uuid.UUID, currency entity.Currency) (*entity.Post, error)
+	ListPostRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error)
+	DiffPostRevisions(ctx context.Context, postID uuid.UUID, from, to int) (*entity.RevisionDiff, error)
 	ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	ListPostsByAuthor(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
+	StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error)
+	FavoritePost(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	UnfavoritePost(ctx context.Context, postID uuid.UUID) error
+	ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error)
+	CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error)
+	CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error)
 }
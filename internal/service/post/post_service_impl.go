@@ -1,10 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
 	usecasePost "marketplace/internal/usecase/post"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -22,12 +24,12 @@ func NewPostService(postUsecase usecasePost.PostUseCaseRepo, logger *logrus.Logg
 	}
 }
 
-func (s *PostService) CreatePost(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	if header == "" || content == "" || price <= 0 {
-		return nil, fmt.Errorf("header, content, and valid price are required")
+func (s *PostService) CreatePost(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error) {
+	if params.Header == "" || params.Content == "" || params.Price == nil {
+		return nil, apperror.Validation("header, content, and price are required")
 	}
 
-	post, err := s.postUsecase.Publish(ctx, authorID, header, content, image, price)
+	post, err := s.postUsecase.Publish(ctx, authorID, params)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to create post")
 		return nil, err
@@ -36,18 +38,18 @@ func (s *PostService) CreatePost(ctx context.Context, authorID uuid.UUID, header
 	s.logger.WithFields(logrus.Fields{
 		"post_id":   post.ID,
 		"author_id": authorID,
-		"header":    header,
+		"header":    params.Header,
 	}).Info("Post created successfully")
 
 	return post, nil
 }
 
-func (s *PostService) EditPost(ctx context.Context, postID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	if header == "" && content == "" && image == "" && price <= 0 {
-		return nil, fmt.Errorf("no fields to update")
+func (s *PostService) EditPost(ctx context.Context, postID uuid.UUID, version int, params entity.PostParams) (*entity.Post, error) {
+	if params.Header == "" && params.Content == "" && params.ImageIDs == nil && params.CoverImageID == nil && params.Price == nil && params.CategoryID == nil && params.Tags == nil {
+		return nil, apperror.Validation("no fields to update")
 	}
 
-	post, err := s.postUsecase.Edit(ctx, postID, header, content, image, price)
+	post, err := s.postUsecase.Edit(ctx, postID, version, params)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to edit post")
 		return nil, err
@@ -60,6 +62,39 @@ func (s *PostService) EditPost(ctx context.Context, postID uuid.UUID, header, co
 	return post, nil
 }
 
+func (s *PostService) PatchPost(ctx context.Context, postID uuid.UUID, version int, patch []byte) (*entity.Post, error) {
+	if len(bytes.TrimSpace(patch)) == 0 {
+		return nil, apperror.Validation("request body is empty")
+	}
+
+	post, err := s.postUsecase.Patch(ctx, postID, version, patch)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to patch post")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+	}).Info("Post patched successfully")
+
+	return post, nil
+}
+
+func (s *PostService) ChangePostStatus(ctx context.Context, postID uuid.UUID, status entity.PostStatus) (*entity.Post, error) {
+	post, err := s.postUsecase.ChangeStatus(ctx, postID, status)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to change post status")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+		"status":  status,
+	}).Info("Post status changed successfully")
+
+	return post, nil
+}
+
 func (s *PostService) DeletePost(ctx context.Context, postID uuid.UUID) error {
 	if err := s.postUsecase.Delete(ctx, postID); err != nil {
 		s.logger.WithError(err).Error("Failed to delete post")
@@ -73,8 +108,118 @@ func (s *PostService) DeletePost(ctx context.Context, postID uuid.UUID) error {
 	return nil
 }
 
-func (s *PostService) GetPost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
-	post, err := s.postUsecase.GetPost(ctx, postID)
+func (s *PostService) ListPostRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error) {
+	revisions, err := s.postUsecase.ListRevisions(ctx, postID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list post revisions")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+	}).Info("Post revisions listed successfully")
+
+	return revisions, nil
+}
+
+func (s *PostService) DiffPostRevisions(ctx context.Context, postID uuid.UUID, from, to int) (*entity.RevisionDiff, error) {
+	if from < 1 || to < 1 {
+		return nil, apperror.Validation("revision numbers must be positive")
+	}
+
+	diff, err := s.postUsecase.DiffRevisions(ctx, postID, from, to)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to diff post revisions")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+		"from":    from,
+		"to":      to,
+	}).Info("Post revisions compared successfully")
+
+	return diff, nil
+}
+
+func (s *PostService) RestorePost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := s.postUsecase.Restore(ctx, postID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to restore post")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+	}).Info("Post restored successfully")
+
+	return post, nil
+}
+
+func (s *PostService) StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error) {
+	stream, err := s.postUsecase.StreamPosts(ctx, filter, lastEventID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to subscribe to post stream")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"filter": filter,
+		"replay": len(stream.Replay),
+	}).Info("Post stream subscribed successfully")
+
+	return stream, nil
+}
+
+func (s *PostService) FavoritePost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := s.postUsecase.Favorite(ctx, postID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to add post to favorites")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+	}).Info("Post added to favorites successfully")
+
+	return post, nil
+}
+
+func (s *PostService) UnfavoritePost(ctx context.Context, postID uuid.UUID) error {
+	if err := s.postUsecase.Unfavorite(ctx, postID); err != nil {
+		s.logger.WithError(err).Error("Failed to remove post from favorites")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+	}).Info("Post removed from favorites successfully")
+
+	return nil
+}
+
+func (s *PostService) ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, apperror.Validation("invalid pagination parameters")
+	}
+
+	posts, total, err := s.postUsecase.ListFavorites(ctx, page, pageSize, currency)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list favorites")
+		return nil, 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"page":        page,
+		"page_size":   pageSize,
+		"total_posts": total,
+	}).Info("Favorites listed successfully")
+
+	return posts, total, nil
+}
+
+func (s *PostService) GetPost(ctx context.Context, postID uuid.UUID, currency entity.Currency) (*entity.Post, error) {
+	post, err := s.postUsecase.GetPost(ctx, postID, currency)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to get post")
 		return nil, err
@@ -89,7 +234,7 @@ func (s *PostService) GetPost(ctx context.Context, postID uuid.UUID) (*entity.Po
 
 func (s *PostService) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	if page < 1 || pageSize < 1 {
-		return nil, 0, fmt.Errorf("invalid pagination parameters")
+		return nil, 0, apperror.Validation("invalid pagination parameters")
 	}
 
 	posts, total, err := s.postUsecase.ListPosts(ctx, page, pageSize, sortBy, filter)
@@ -109,7 +254,7 @@ func (s *PostService) ListPosts(ctx context.Context, page, pageSize int, sortBy
 
 func (s *PostService) ListPostsByAuthor(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	if page < 1 || pageSize < 1 {
-		return nil, 0, fmt.Errorf("invalid pagination parameters")
+		return nil, 0, apperror.Validation("invalid pagination parameters")
 	}
 
 	posts, total, err := s.postUsecase.ListPostsByAuthor(ctx, authorID, page, pageSize, sortBy, filter)
@@ -127,3 +272,21 @@ func (s *PostService) ListPostsByAuthor(ctx context.Context, authorID uuid.UUID,
 
 	return posts, total, nil
 }
+
+func (s *PostService) CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	facets, err := s.postUsecase.CategoryFacets(ctx, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get category facets")
+		return nil, err
+	}
+	return facets, nil
+}
+
+func (s *PostService) CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	facets, err := s.postUsecase.CategoryFacetsByAuthor(ctx, authorID, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get category facets by author")
+		return nil, err
+	}
+	return facets, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"marketplace/internal/entity"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -17,13 +18,23 @@ type MockPostUseCase struct {
 	mock.Mock
 }
 
-func (m *MockPostUseCase) Publish(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	args := m.Called(ctx, authorID, header, content, image, price)
+func (m *MockPostUseCase) Publish(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error) {
+	args := m.Called(ctx, authorID, params)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostUseCase) Edit(ctx context.Context, postID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	args := m.Called(ctx, postID, header, content, image, price)
+func (m *MockPostUseCase) Edit(ctx context.Context, postID uuid.UUID, version int, params entity.PostParams) (*entity.Post, error) {
+	args := m.Called(ctx, postID, version, params)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostUseCase) Patch(ctx context.Context, postID uuid.UUID, version int, patch []byte) (*entity.Post, error) {
+	args := m.Called(ctx, postID, version, patch)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostUseCase) ChangeStatus(ctx context.Context, postID uuid.UUID, status entity.PostStatus) (*entity.Post, error) {
+	args := m.Called(ctx, postID, status)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
@@ -32,11 +43,41 @@ func (m *MockPostUseCase) Delete(ctx context.Context, postID uuid.UUID) error {
 	return args.Error(0)
 }
 
-func (m *MockPostUseCase) GetPost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+func (m *MockPostUseCase) Restore(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, postID)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostUseCase) Favorite(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
 	args := m.Called(ctx, postID)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
+func (m *MockPostUseCase) Unfavorite(ctx context.Context, postID uuid.UUID) error {
+	args := m.Called(ctx, postID)
+	return args.Error(0)
+}
+
+func (m *MockPostUseCase) ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	args := m.Called(ctx, page, pageSize, currency)
+	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
+}
+
+func (m *MockPostUseCase) GetPost(ctx context.Context, postID uuid.UUID, currency entity.Currency) (*entity.Post, error) {
+	args := m.Called(ctx, postID, currency)
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostUseCase) ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error) {
+	args := m.Called(ctx, postID)
+	return args.Get(0).([]*entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostUseCase) DiffRevisions(ctx context.Context, postID uuid.UUID, from, to int) (*entity.RevisionDiff, error) {
+	args := m.Called(ctx, postID, from, to)
+	return args.Get(0).(*entity.RevisionDiff), args.Error(1)
+}
+
 func (m *MockPostUseCase) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	args := m.Called(ctx, page, pageSize, sortBy, filter)
 	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
@@ -46,6 +87,22 @@ func (m *MockPostUseCase) ListPostsByAuthor(ctx context.Context, authorID uuid.U
 	args := m.Called(ctx, authorID, page, pageSize, sortBy, filter)
 	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
 }
+
+func (m *MockPostUseCase) StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error) {
+	args := m.Called(ctx, filter, lastEventID)
+	stream, _ := args.Get(0).(*usecaseRealtime.PostStream)
+	return stream, args.Error(1)
+}
+
+func (m *MockPostUseCase) CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entity.CategoryFacet), args.Error(1)
+}
+
+func (m *MockPostUseCase) CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	args := m.Called(ctx, authorID, filter)
+	return args.Get(0).([]*entity.CategoryFacet), args.Error(1)
+}
 func TestEditPost(t *testing.T) {
 	mockUsecase := new(MockPostUseCase)
 	logger := logrus.New()
@@ -54,23 +111,25 @@ func TestEditPost(t *testing.T) {
 	postID := uuid.New()
 	header := "Updated Header"
 	content := "Updated Content"
-	image := "http://example.com/new-image.jpg"
-	price := 89.99
+	imageID := uuid.New()
+	price := entity.Money{Amount: 8999, Currency: "RUB"}
 
 	expectedPost := &entity.Post{
 		ID:        postID,
 		Header:    header,
 		Content:   content,
-		Image:     image,
+		Image:     entity.ImageURL(imageID),
+		Images:    []entity.PostImage{{ImageID: imageID, URL: entity.ImageURL(imageID), IsCover: true}},
 		Price:     price,
 		AuthorID:  uuid.New(),
 		CreatedAt: time.Now(),
 	}
 
-	mockUsecase.On("Edit", mock.Anything, postID, header, content, image, price).
+	params := entity.PostParams{Header: header, Content: content, ImageIDs: []uuid.UUID{imageID}, Price: &price}
+	mockUsecase.On("Edit", mock.Anything, postID, 2, params).
 		Return(expectedPost, nil)
 
-	result, err := postService.EditPost(context.Background(), postID, header, content, image, price)
+	result, err := postService.EditPost(context.Background(), postID, 2, params)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPost, result)
 	mockUsecase.AssertExpectations(t)
@@ -97,23 +156,25 @@ func TestCreatePost(t *testing.T) {
 	authorID := uuid.New()
 	header := "Test Post"
 	content := "This is a test post."
-	image := "http://example.com/image.jpg"
-	price := 99.99
+	imageID := uuid.New()
+	price := entity.Money{Amount: 9999, Currency: "RUB"}
 
 	expectedPost := &entity.Post{
 		ID:        uuid.New(),
 		Header:    header,
 		Content:   content,
-		Image:     image,
+		Image:     entity.ImageURL(imageID),
+		Images:    []entity.PostImage{{ImageID: imageID, URL: entity.ImageURL(imageID), IsCover: true}},
 		Price:     price,
 		AuthorID:  authorID,
 		CreatedAt: time.Now(),
 	}
 
-	mockUsecase.On("Publish", mock.Anything, authorID, header, content, image, price).
+	params := entity.PostParams{Header: header, Content: content, ImageIDs: []uuid.UUID{imageID}, Price: &price, Tags: []string{"books"}}
+	mockUsecase.On("Publish", mock.Anything, authorID, params).
 		Return(expectedPost, nil)
 
-	result, err := postService.CreatePost(context.Background(), authorID, header, content, image, price)
+	result, err := postService.CreatePost(context.Background(), authorID, params)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPost, result)
 	mockUsecase.AssertExpectations(t)
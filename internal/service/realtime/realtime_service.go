@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+)
+
+type RealtimeServiceInterface interface {
+	Subscribe(ctx context.Context) (*usecaseRealtime.Subscription, error)
+	Unsubscribe(subscription *usecaseRealtime.Subscription)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RealtimeService struct {
+	realtimeUsecase usecaseRealtime.RealtimeUseCaseRepo
+	logger          *logrus.Logger
+}
+
+func NewRealtimeService(realtimeUsecase usecaseRealtime.RealtimeUseCaseRepo, logger *logrus.Logger) *RealtimeService {
+	return &RealtimeService{
+		realtimeUsecase: realtimeUsecase,
+		logger:          logger,
+	}
+}
+
+func (s *RealtimeService) Subscribe(ctx context.Context) (*usecaseRealtime.Subscription, error) {
+	subscription, err := s.realtimeUsecase.Subscribe(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to subscribe to events")
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *RealtimeService) Unsubscribe(subscription *usecaseRealtime.Subscription) {
+	s.realtimeUsecase.Unsubscribe(subscription)
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": subscription.UserID,
+	}).Info("Unsubscribed from events successfully")
+}
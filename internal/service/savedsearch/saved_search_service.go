@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SavedSearchServiceInterface interface {
+	CreateSavedSearch(ctx context.Context, name string, filter map[string]string) (*entity.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error)
+	ListSavedSearches(ctx context.Context) ([]*entity.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, id uuid.UUID, name string, filter map[string]string) (*entity.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id uuid.UUID) error
+	ListSavedSearchMatches(ctx context.Context, id uuid.UUID, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error)
+	MarkSavedSearchSeen(ctx context.Context, id uuid.UUID) (int64, error)
+}
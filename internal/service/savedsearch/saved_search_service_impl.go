@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseSavedSearch "marketplace/internal/usecase/savedsearch"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchService struct {
+	searchUsecase usecaseSavedSearch.SavedSearchUseCaseRepo
+	logger        *logrus.Logger
+}
+
+func NewSavedSearchService(searchUsecase usecaseSavedSearch.SavedSearchUseCaseRepo, logger *logrus.Logger) *SavedSearchService {
+	return &SavedSearchService{
+		searchUsecase: searchUsecase,
+		logger:        logger,
+	}
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	search, err := s.searchUsecase.Create(ctx, name, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create saved search")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+	}).Info("Saved search created successfully")
+
+	return search, nil
+}
+
+func (s *SavedSearchService) GetSavedSearch(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error) {
+	search, err := s.searchUsecase.Get(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get saved search")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search fetched successfully")
+
+	return search, nil
+}
+
+func (s *SavedSearchService) ListSavedSearches(ctx context.Context) ([]*entity.SavedSearch, error) {
+	searches, err := s.searchUsecase.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list saved searches")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(searches),
+	}).Info("Saved searches listed successfully")
+
+	return searches, nil
+}
+
+func (s *SavedSearchService) UpdateSavedSearch(ctx context.Context, id uuid.UUID, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	search, err := s.searchUsecase.Update(ctx, id, name, filter)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to update saved search")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search updated successfully")
+
+	return search, nil
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, id uuid.UUID) error {
+	if err := s.searchUsecase.Delete(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to delete saved search")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+	}).Info("Saved search deleted successfully")
+
+	return nil
+}
+
+func (s *SavedSearchService) ListSavedSearchMatches(ctx context.Context, id uuid.UUID, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, apperror.Validation("invalid pagination parameters")
+	}
+
+	posts, total, err := s.searchUsecase.ListMatches(ctx, id, page, pageSize, currency)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list saved search matches")
+		return nil, 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+		"page":            page,
+		"page_size":       pageSize,
+		"total_posts":     total,
+	}).Info("Saved search matches listed successfully")
+
+	return posts, total, nil
+}
+
+func (s *SavedSearchService) MarkSavedSearchSeen(ctx context.Context, id uuid.UUID) (int64, error) {
+	seen, err := s.searchUsecase.MarkSeen(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to mark saved search matches seen")
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"saved_search_id": id,
+		"seen":            seen,
+	}).Info("Saved search matches marked as seen successfully")
+
+	return seen, nil
+}
@@ -8,9 +8,13 @@ import (
 )
 
 type UserServiceInterface interface {
-	Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error)
-	Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error)
+	Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
+	Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
 	GetUser(ctx context.Context, id uuid.UUID) (*entity.UserDTO, error)
 	UpdateUser(ctx context.Context, id uuid.UUID, username, password string) error
+	PatchUser(ctx context.Context, id uuid.UUID, patch []byte) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	RestoreUser(ctx context.Context, id uuid.UUID) error
+	RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
+	ChangeUserRole(ctx context.Context, id uuid.UUID, role entity.Role) error
 }
@@ -1,8 +1,9 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
 	usecase "marketplace/internal/usecase/user"
 
@@ -22,15 +23,15 @@ func NewUserService(userUsecase usecase.UserUseCaseRepo, logger *logrus.Logger)
 	}
 }
 
-func (s *UserService) Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (s *UserService) Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	if username == "" || password == "" {
-		return nil, "", fmt.Errorf("username and password are required")
+		return nil, nil, apperror.Validation("username and password are required")
 	}
 
-	userDTO, token, err := s.userUsecase.Register(ctx, username, password)
+	userDTO, tokens, err := s.userUsecase.Register(ctx, username, password)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to register user")
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -38,18 +39,18 @@ func (s *UserService) Register(ctx context.Context, username, password string) (
 		"user_id":  userDTO.ID,
 	}).Info("User registered successfully")
 
-	return userDTO, token, nil
+	return userDTO, tokens, nil
 }
 
-func (s *UserService) Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (s *UserService) Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	if username == "" || password == "" {
-		return nil, "", fmt.Errorf("username and password are required")
+		return nil, nil, apperror.Validation("username and password are required")
 	}
 
-	user, token, err := s.userUsecase.Login(ctx, username, password)
+	user, tokens, err := s.userUsecase.Login(ctx, username, password)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to login user")
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -57,7 +58,7 @@ func (s *UserService) Login(ctx context.Context, username, password string) (*en
 		"user_id":  user.ID,
 	}).Info("User logged in successfully")
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*entity.UserDTO, error) {
@@ -76,7 +77,7 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*entity.UserDT
 
 func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, username, password string) error {
 	if username == "" && password == "" {
-		return fmt.Errorf("no fields to update")
+		return apperror.Validation("no fields to update")
 	}
 
 	if err := s.userUsecase.Update(ctx, id, username, password); err != nil {
@@ -91,6 +92,23 @@ func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, username, pa
 	return nil
 }
 
+func (s *UserService) PatchUser(ctx context.Context, id uuid.UUID, patch []byte) error {
+	if len(bytes.TrimSpace(patch)) == 0 {
+		return apperror.Validation("request body is empty")
+	}
+
+	if err := s.userUsecase.Patch(ctx, id, patch); err != nil {
+		s.logger.WithError(err).Error("Failed to patch user")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": id,
+	}).Info("User patched successfully")
+
+	return nil
+}
+
 func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	if err := s.userUsecase.Delete(ctx, id); err != nil {
 		s.logger.WithError(err).Error("Failed to delete user")
@@ -103,3 +121,53 @@ func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+func (s *UserService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	if err := s.userUsecase.Restore(ctx, id); err != nil {
+		s.logger.WithError(err).Error("Failed to restore user")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": id,
+	}).Info("User restored successfully")
+
+	return nil
+}
+
+func (s *UserService) RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
+	if username == "" || password == "" {
+		return nil, nil, apperror.Validation("username and password are required")
+	}
+
+	user, tokens, err := s.userUsecase.RestoreAccount(ctx, username, password)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to restore user account")
+		return nil, nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"username": username,
+		"user_id":  user.ID,
+	}).Info("User account restored successfully")
+
+	return user, tokens, nil
+}
+
+func (s *UserService) ChangeUserRole(ctx context.Context, id uuid.UUID, role entity.Role) error {
+	if !role.IsValid() {
+		return apperror.Validation("invalid role %q", role)
+	}
+
+	if err := s.userUsecase.ChangeRole(ctx, id, role); err != nil {
+		s.logger.WithError(err).Error("Failed to change user role")
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": id,
+		"role":    role,
+	}).Info("User role changed successfully")
+
+	return nil
+}
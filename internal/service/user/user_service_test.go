@@ -16,14 +16,14 @@ type MockUserUseCase struct {
 	mock.Mock
 }
 
-func (m *MockUserUseCase) Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (m *MockUserUseCase) Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	args := m.Called(ctx, username, password)
-	return args.Get(0).(*entity.UserDTO), args.String(1), args.Error(2)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
 }
 
-func (m *MockUserUseCase) Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (m *MockUserUseCase) Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	args := m.Called(ctx, username, password)
-	return args.Get(0).(*entity.UserDTO), args.String(1), args.Error(2)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
 }
 
 func (m *MockUserUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
@@ -36,11 +36,31 @@ func (m *MockUserUseCase) Update(ctx context.Context, id uuid.UUID, username, pa
 	return args.Error(0)
 }
 
+func (m *MockUserUseCase) Patch(ctx context.Context, id uuid.UUID, patch []byte) error {
+	args := m.Called(ctx, id, patch)
+	return args.Error(0)
+}
+
 func (m *MockUserUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserUseCase) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserUseCase) RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(*entity.UserDTO), args.Get(1).(*entity.TokenPair), args.Error(2)
+}
+
+func (m *MockUserUseCase) ChangeRole(ctx context.Context, id uuid.UUID, role entity.Role) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
 func TestRegister(t *testing.T) {
 	mockUsecase := new(MockUserUseCase)
 	logger := logrus.New()
@@ -52,15 +72,15 @@ func TestRegister(t *testing.T) {
 		ID:       uuid.New(),
 		Username: username,
 	}
-	expectedToken := "fake-jwt-token"
+	expectedTokens := &entity.TokenPair{AccessToken: "fake-jwt-token", RefreshToken: "fake-refresh-token", ExpiresIn: 900}
 
 	mockUsecase.On("Register", mock.Anything, username, password).
-		Return(expectedUser, expectedToken, nil)
+		Return(expectedUser, expectedTokens, nil)
 
-	user, token, err := userService.Register(context.Background(), username, password)
+	user, tokens, err := userService.Register(context.Background(), username, password)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedTokens, tokens)
 	mockUsecase.AssertExpectations(t)
 }
 
@@ -75,15 +95,15 @@ func TestLogin(t *testing.T) {
 		ID:       uuid.New(),
 		Username: username,
 	}
-	expectedToken := "fake-jwt-token"
+	expectedTokens := &entity.TokenPair{AccessToken: "fake-jwt-token", RefreshToken: "fake-refresh-token", ExpiresIn: 900}
 
 	mockUsecase.On("Login", mock.Anything, username, password).
-		Return(expectedUser, expectedToken, nil)
+		Return(expectedUser, expectedTokens, nil)
 
-	user, token, err := userService.Login(context.Background(), username, password)
+	user, tokens, err := userService.Login(context.Background(), username, password)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedTokens, tokens)
 	mockUsecase.AssertExpectations(t)
 }
 
@@ -137,3 +157,22 @@ func TestDeleteUser(t *testing.T) {
 	assert.NoError(t, err)
 	mockUsecase.AssertExpectations(t)
 }
+
+func TestRestoreAccount(t *testing.T) {
+	mockUsecase := new(MockUserUseCase)
+	logger := logrus.New()
+	userService := NewUserService(mockUsecase, logger)
+
+	userDTO := &entity.UserDTO{ID: uuid.New(), Username: "testuser"}
+	tokens := &entity.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	mockUsecase.On("RestoreAccount", mock.Anything, "testuser", "Password123!").Return(userDTO, tokens, nil)
+
+	user, restoredTokens, err := userService.RestoreAccount(context.Background(), "testuser", "Password123!")
+	assert.NoError(t, err)
+	assert.Equal(t, userDTO, user)
+	assert.Equal(t, tokens, restoredTokens)
+
+	_, _, err = userService.RestoreAccount(context.Background(), "", "")
+	assert.Error(t, err)
+	mockUsecase.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"marketplace/internal/entity"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,50 +11,82 @@ import (
 )
 
 type AuthImpl struct {
-	secretKey []byte
+	keys      *KeySet
+	accessTTL time.Duration
 }
 
-func NewAuthImpl(secretKey string) *AuthImpl {
+func NewAuthImpl(keys *KeySet, accessTTL time.Duration) *AuthImpl {
 	return &AuthImpl{
-		secretKey: []byte(secretKey),
+		keys:      keys,
+		accessTTL: accessTTL,
 	}
 }
 
-func (a *AuthImpl) GenerateJWT(userID uuid.UUID) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func (a *AuthImpl) GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error) {
+	now := time.Now()
+	return a.keys.sign(jwt.MapClaims{
 		"user_id": userID.String(),
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"sid":     sessionID.String(),
+		"role":    string(role),
+		"jti":     uuid.NewString(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(a.accessTTL).Unix(),
 	})
-	return token.SignedString(a.secretKey)
 }
 
-func (a *AuthImpl) ValidateJWT(tokenString string) (uuid.UUID, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return a.secretKey, nil
-	})
+func (a *AuthImpl) ValidateJWT(tokenString string) (*entity.TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, a.keys.keyFunc)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return uuid.Nil, fmt.Errorf("invalid token claims")
+		return nil, fmt.Errorf("invalid token claims")
 	}
 
 	userIDStr, ok := claims["user_id"].(string)
 	if !ok {
-		return uuid.Nil, fmt.Errorf("invalid user_id in token")
+		return nil, fmt.Errorf("invalid user_id in token")
 	}
-
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user_id format: %w", err)
+		return nil, fmt.Errorf("invalid user_id format: %w", err)
+	}
+
+	sessionIDStr, ok := claims["sid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid sid in token")
+	}
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sid format: %w", err)
 	}
 
-	return userID, nil
+	role := entity.Role(fmt.Sprint(claims["role"]))
+	if !role.IsValid() {
+		role = entity.RoleUser
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("invalid exp in token: %w", err)
+	}
+	var expiresAt time.Time
+	if exp != nil {
+		expiresAt = exp.Time
+	}
+
+	return &entity.TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (a *AuthImpl) JWKS() *entity.JSONWebKeySet {
+	return a.keys.JWKS()
 }
 
 func (a *AuthImpl) GeneratePasswordHash(password string) (string, error) {
@@ -1,10 +1,15 @@
 package usecase
 
-import "github.com/google/uuid"
+import (
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
 
 type AuthService interface {
 	GeneratePasswordHash(password string) (string, error)
 	VerifyPassword(hashedPassword, inputPassword string) error
-	GenerateJWT(userID uuid.UUID) (string, error)
-	ValidateJWT(tokenString string) (uuid.UUID, error)
+	GenerateJWT(userID, sessionID uuid.UUID, role entity.Role) (string, error)
+	ValidateJWT(tokenString string) (*entity.TokenClaims, error)
+	JWKS() *entity.JSONWebKeySet
 }
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writePEM(t, dir, "old.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	oldKeys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	oldAuth := NewAuthImpl(oldKeys, time.Minute)
+
+	userID, sessionID := uuid.New(), uuid.New()
+	oldToken, err := oldAuth.GenerateJWT(userID, sessionID, entity.RoleModerator)
+	require.NoError(t, err)
+
+	// Ротация: новый Ed25519-ключ подписывает, от старого остаётся только публичная часть.
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	writePEM(t, dir, "new.pem", "PRIVATE KEY", edDER)
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(dir, "old.pem")))
+	writePEM(t, dir, "old.pem", "PUBLIC KEY", pubDER)
+
+	newKeys, err := LoadKeySet(dir, "new")
+	require.NoError(t, err)
+	newAuth := NewAuthImpl(newKeys, time.Minute)
+
+	claims, err := newAuth.ValidateJWT(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, sessionID, claims.SessionID)
+	assert.Equal(t, entity.RoleModerator, claims.Role)
+
+	newToken, err := newAuth.GenerateJWT(userID, sessionID, entity.RoleUser)
+	require.NoError(t, err)
+	_, err = newAuth.ValidateJWT(newToken)
+	assert.NoError(t, err)
+
+	// Токен нового ключа неизвестен старому набору.
+	_, err = oldAuth.ValidateJWT(newToken)
+	assert.Error(t, err)
+
+	jwks := newAuth.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	assert.Equal(t, "old", jwks.Keys[1].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+	assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+}
+
+func TestKeySetRejectsPublicOnlySigningKey(t *testing.T) {
+	dir := t.TempDir()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	writePEM(t, dir, "verify-only.pem", "PUBLIC KEY", der)
+
+	_, err = LoadKeySet(dir, "verify-only")
+	assert.Error(t, err)
+}
+
+func TestHMACKeySetIsNotPublished(t *testing.T) {
+	auth := NewAuthImpl(NewHMACKeySet("secret"), time.Minute)
+
+	token, err := auth.GenerateJWT(uuid.New(), uuid.New(), entity.RoleUser)
+	require.NoError(t, err)
+	_, err = auth.ValidateJWT(token)
+	assert.NoError(t, err)
+
+	assert.Empty(t, auth.JWKS().Keys)
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *entity.Session) error
+	GetSession(ctx context.Context, id uuid.UUID) (*entity.Session, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type UserReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+}
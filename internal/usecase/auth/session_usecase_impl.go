@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionUseCase struct {
+	sessionRepo SessionRepository
+	userRepo    UserReader
+	authRepo    AuthService
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	logger      *logrus.Logger
+}
+
+func NewSessionUseCase(sessionRepo SessionRepository, userRepo UserReader, authRepo AuthService, accessTTL, refreshTTL time.Duration, logger *logrus.Logger) *SessionUseCase {
+	return &SessionUseCase{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		authRepo:    authRepo,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+		logger:      logger,
+	}
+}
+
+func (uc *SessionUseCase) Start(ctx context.Context, user *entity.User) (*entity.TokenPair, error) {
+	session := &entity.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	tokens, err := uc.issue(ctx, session, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"user_id":    user.ID,
+	}).Info("Session started")
+
+	return tokens, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Каждый refresh-токен
+// одноразовый: повторное предъявление уже использованного токена считается
+// признаком утечки, и вся сессия отзывается.
+func (uc *SessionUseCase) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, apperror.Unauthorized("invalid refresh token")
+	}
+
+	token, err := uc.sessionRepo.GetRefreshTokenByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.Unauthorized("invalid refresh token")
+		}
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+
+	session, err := uc.sessionRepo.GetSession(ctx, token.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	if session.RevokedAt != nil {
+		return nil, apperror.Unauthorized("refresh token has been revoked")
+	}
+
+	if token.UsedAt != nil {
+		return nil, uc.revokeOnReuse(ctx, session)
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, apperror.Unauthorized("refresh token has expired")
+	}
+
+	marked, err := uc.sessionRepo.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("mark refresh token used: %w", err)
+	}
+	if !marked {
+		return nil, uc.revokeOnReuse(ctx, session)
+	}
+
+	// Роль берётся из базы, чтобы её изменение применялось при следующем обновлении токенов.
+	user, err := uc.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	tokens, err := uc.issue(ctx, session, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"user_id":    session.UserID,
+	}).Info("Session refreshed")
+
+	return tokens, nil
+}
+
+func (uc *SessionUseCase) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+	if err := uc.sessionRepo.RevokeSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"session_id": sessionID,
+	}).Info("Session revoked")
+
+	return nil
+}
+
+func (uc *SessionUseCase) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	session, err := uc.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return true, nil
+		}
+		return false, fmt.Errorf("get session: %w", err)
+	}
+	return session.RevokedAt != nil, nil
+}
+
+func (uc *SessionUseCase) issue(ctx context.Context, session *entity.Session, role entity.Role) (*entity.TokenPair, error) {
+	accessToken, err := uc.authRepo.GenerateJWT(session.UserID, session.ID, role)
+	if err != nil {
+		return nil, fmt.Errorf("generate jwt: %w", err)
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	record := &entity.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(uc.refreshTTL),
+		CreatedAt: now,
+	}
+	if err := uc.sessionRepo.CreateRefreshToken(ctx, record); err != nil {
+		return nil, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(uc.accessTTL.Seconds()),
+	}, nil
+}
+
+func (uc *SessionUseCase) revokeOnReuse(ctx context.Context, session *entity.Session) error {
+	uc.logger.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"user_id":    session.UserID,
+	}).Warn("Refresh token reuse detected, revoking session")
+
+	if err := uc.sessionRepo.RevokeSession(ctx, session.ID); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	return apperror.Unauthorized("refresh token reuse detected, session revoked")
+}
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
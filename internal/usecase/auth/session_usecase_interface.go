@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SessionUseCaseRepo interface {
+	Start(ctx context.Context, user *entity.User) (*entity.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	Revoke(ctx context.Context, sessionID uuid.UUID) error
+	IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *entity.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetSession(ctx context.Context, id uuid.UUID) (*entity.Session, error) {
+	args := m.Called(ctx, id)
+	session, _ := args.Get(0).(*entity.Session)
+	return session, args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, hash)
+	token, _ := args.Get(0).(*entity.RefreshToken)
+	return token, args.Error(1)
+}
+
+func (m *MockSessionRepository) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockUserReader struct {
+	mock.Mock
+}
+
+func (m *MockUserReader) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*entity.User)
+	return user, args.Error(1)
+}
+
+type sessionFixture struct {
+	uc       *SessionUseCase
+	repo     *MockSessionRepository
+	users    *MockUserReader
+	auth     *AuthImpl
+	session  *entity.Session
+	token    *entity.RefreshToken
+	rawToken string
+}
+
+// newSessionFixture готовит сессию с действующим refresh-токеном rawToken.
+func newSessionFixture(t *testing.T) *sessionFixture {
+	t.Helper()
+	repo := new(MockSessionRepository)
+	users := new(MockUserReader)
+	auth := NewAuthImpl(NewHMACKeySet("test-secret"), time.Minute)
+
+	rawToken, err := generateRefreshToken()
+	require.NoError(t, err)
+	session := &entity.Session{ID: uuid.New(), UserID: uuid.New(), CreatedAt: time.Now()}
+	token := &entity.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		TokenHash: hashRefreshToken(rawToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+	repo.On("GetRefreshTokenByHash", mock.Anything, token.TokenHash).Return(token, nil)
+	repo.On("GetSession", mock.Anything, session.ID).Return(session, nil)
+
+	return &sessionFixture{
+		uc:       NewSessionUseCase(repo, users, auth, time.Minute, time.Hour, logrus.New()),
+		repo:     repo,
+		users:    users,
+		auth:     auth,
+		session:  session,
+		token:    token,
+		rawToken: rawToken,
+	}
+}
+
+func TestSessionUseCase_RefreshRotatesToken(t *testing.T) {
+	f := newSessionFixture(t)
+	f.repo.On("MarkRefreshTokenUsed", mock.Anything, f.token.ID).Return(true, nil)
+	f.users.On("GetByID", mock.Anything, f.session.UserID).Return(&entity.User{ID: f.session.UserID, Role: entity.RoleModerator}, nil)
+
+	var stored *entity.RefreshToken
+	f.repo.On("CreateRefreshToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.RefreshToken)
+	}).Return(nil)
+
+	tokens, err := f.uc.Refresh(context.Background(), f.rawToken)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, f.rawToken, tokens.RefreshToken)
+	require.NotNil(t, stored)
+	assert.Equal(t, f.session.ID, stored.SessionID)
+	assert.Equal(t, hashRefreshToken(tokens.RefreshToken), stored.TokenHash)
+
+	claims, err := f.auth.ValidateJWT(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, f.session.ID, claims.SessionID)
+	assert.Equal(t, entity.RoleModerator, claims.Role)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiresAt, 2*time.Second)
+	f.repo.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything)
+}
+
+func TestSessionUseCase_RefreshReuseRevokesSession(t *testing.T) {
+	f := newSessionFixture(t)
+	usedAt := time.Now().Add(-time.Minute)
+	f.token.UsedAt = &usedAt
+	f.repo.On("RevokeSession", mock.Anything, f.session.ID).Return(nil).Once()
+
+	_, err := f.uc.Refresh(context.Background(), f.rawToken)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+	f.repo.AssertExpectations(t)
+	f.repo.AssertNotCalled(t, "MarkRefreshTokenUsed", mock.Anything, mock.Anything)
+	f.repo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestSessionUseCase_RefreshConcurrentReuseRevokesSession(t *testing.T) {
+	f := newSessionFixture(t)
+	// Токен успел использовать параллельный запрос.
+	f.repo.On("MarkRefreshTokenUsed", mock.Anything, f.token.ID).Return(false, nil)
+	f.repo.On("RevokeSession", mock.Anything, f.session.ID).Return(nil).Once()
+
+	_, err := f.uc.Refresh(context.Background(), f.rawToken)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+	f.repo.AssertExpectations(t)
+	f.repo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestSessionUseCase_RefreshExpiredToken(t *testing.T) {
+	f := newSessionFixture(t)
+	f.token.ExpiresAt = time.Now().Add(-time.Second)
+
+	_, err := f.uc.Refresh(context.Background(), f.rawToken)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+	f.repo.AssertNotCalled(t, "MarkRefreshTokenUsed", mock.Anything, mock.Anything)
+	f.repo.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything)
+	f.repo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestSessionUseCase_RefreshRevokedSession(t *testing.T) {
+	f := newSessionFixture(t)
+	revokedAt := time.Now().Add(-time.Minute)
+	f.session.RevokedAt = &revokedAt
+
+	_, err := f.uc.Refresh(context.Background(), f.rawToken)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+	f.repo.AssertNotCalled(t, "MarkRefreshTokenUsed", mock.Anything, mock.Anything)
+	f.repo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestSessionUseCase_RefreshUnknownToken(t *testing.T) {
+	f := newSessionFixture(t)
+	f.repo.On("GetRefreshTokenByHash", mock.Anything, hashRefreshToken("unknown")).Return(nil, apperror.ErrNotFound)
+
+	_, err := f.uc.Refresh(context.Background(), "unknown")
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/usecase/policy"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryUsecase struct {
+	categoryRepo CategoryRepository
+	logger       *logrus.Logger
+}
+
+func NewCategoryUsecase(categoryRepo CategoryRepository, logger *logrus.Logger) *CategoryUsecase {
+	return &CategoryUsecase{
+		categoryRepo: categoryRepo,
+		logger:       logger,
+	}
+}
+
+func (uc *CategoryUsecase) Create(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	actor, err := policy.AuthorizeManageCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &entity.Category{
+		ID:         uuid.New(),
+		ParentID:   parentID,
+		Name:       name,
+		Slug:       slug,
+		Attributes: attributes,
+		CreatedAt:  time.Now(),
+	}
+	if err := category.Validate(); err != nil {
+		return nil, fmt.Errorf("validate category: %w", err)
+	}
+
+	if err := uc.categoryRepo.Create(ctx, category); err != nil {
+		return nil, fmt.Errorf("create category: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"category_id": category.ID,
+		"slug":        category.Slug,
+		"actor_id":    actor.UserID,
+	}).Info("Category created")
+
+	return category, nil
+}
+
+func (uc *CategoryUsecase) Get(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+
+	entity.BuildCategoryTree(categories)
+	for _, category := range categories {
+		if category.ID == id {
+			return category, nil
+		}
+	}
+	return nil, apperror.NotFound("category not found")
+}
+
+func (uc *CategoryUsecase) Tree(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+
+	return entity.BuildCategoryTree(categories), nil
+}
+
+func (uc *CategoryUsecase) Update(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error) {
+	actor, err := policy.AuthorizeManageCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+
+	category.Name = name
+	category.Slug = slug
+	category.ParentID = parentID
+	category.Attributes = attributes
+	if err := category.Validate(); err != nil {
+		return nil, fmt.Errorf("validate category: %w", err)
+	}
+
+	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("update category: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"category_id": id,
+		"actor_id":    actor.UserID,
+	}).Info("Category updated")
+
+	return category, nil
+}
+
+func (uc *CategoryUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	actor, err := policy.AuthorizeManageCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.categoryRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete category: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"category_id": id,
+		"actor_id":    actor.UserID,
+	}).Info("Category deleted")
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type CategoryUseCaseRepo interface {
+	Create(ctx context.Context, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error)
+	Get(ctx context.Context, id uuid.UUID) (*entity.Category, error)
+	Tree(ctx context.Context) ([]*entity.Category, error)
+	Update(ctx context.Context, id uuid.UUID, name, slug string, parentID *uuid.UUID, attributes []entity.Attribute) (*entity.Category, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
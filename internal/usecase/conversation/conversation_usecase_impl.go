@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseNotification "marketplace/internal/usecase/notification"
+	"marketplace/internal/usecase/policy"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ConversationUsecase struct {
+	conversationRepo ConversationRepository
+	postRepo         PostRepository
+	notifier         usecaseNotification.Notifier
+	events           usecaseRealtime.Publisher
+	logger           *logrus.Logger
+}
+
+func NewConversationUsecase(conversationRepo ConversationRepository, postRepo PostRepository, notifier usecaseNotification.Notifier, events usecaseRealtime.Publisher, logger *logrus.Logger) *ConversationUsecase {
+	return &ConversationUsecase{
+		conversationRepo: conversationRepo,
+		postRepo:         postRepo,
+		notifier:         notifier,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+// Start отправляет автору поста первое сообщение. Если у пользователя уже
+// есть переписка по этому посту, сообщение добавляется в неё. Новую
+// переписку нельзя начать с автором, который заблокировал пользователя.
+func (uc *ConversationUsecase) Start(ctx context.Context, postID uuid.UUID, body string) (*entity.Conversation, *entity.Message, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get post by id: %w", err)
+	}
+	if actor, _ := policy.ActorFromContext(ctx); !actor.CanViewPost(post) {
+		return nil, nil, apperror.NotFound("post not found")
+	}
+	actor, err := policy.AuthorizeStartConversation(ctx, post)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message, err := newMessage(actor.UserID, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conversation, err := uc.conversationRepo.GetByPostAndBuyer(ctx, post.ID, actor.UserID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		conversation, err = uc.create(ctx, post, actor.UserID, message.CreatedAt)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.send(ctx, conversation, message); err != nil {
+		return nil, nil, err
+	}
+	return conversation, message, nil
+}
+
+// create начинает переписку покупателя с автором поста. Если переписку
+// одновременно создал другой запрос того же покупателя, возвращается она.
+func (uc *ConversationUsecase) create(ctx context.Context, post *entity.Post, buyerID uuid.UUID, now time.Time) (*entity.Conversation, error) {
+	blocked, err := uc.conversationRepo.IsBlocked(ctx, post.AuthorID, buyerID)
+	if err != nil {
+		return nil, fmt.Errorf("check block: %w", err)
+	}
+	if blocked {
+		return nil, apperror.Forbidden("the author doesn't accept messages from you")
+	}
+
+	conversation := &entity.Conversation{
+		ID:            uuid.New(),
+		PostID:        post.ID,
+		PostHeader:    post.Header,
+		BuyerID:       buyerID,
+		SellerID:      post.AuthorID,
+		CreatedAt:     now,
+		LastMessageAt: now,
+	}
+	err = uc.conversationRepo.Create(ctx, conversation)
+	if errors.Is(err, apperror.ErrConflict) {
+		return uc.conversationRepo.GetByPostAndBuyer(ctx, post.ID, buyerID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create conversation: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"conversation_id": conversation.ID,
+		"post_id":         post.ID,
+		"buyer_id":        buyerID,
+	}).Info("Conversation started")
+
+	return conversation, nil
+}
+
+func (uc *ConversationUsecase) List(ctx context.Context, page, pageSize int) ([]*entity.Conversation, int, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, 0, apperror.Unauthorized("authentication required")
+	}
+
+	conversations, total, err := uc.conversationRepo.ListByUserID(ctx, actor.UserID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list conversations: %w", err)
+	}
+	return conversations, total, nil
+}
+
+// Get возвращает переписку с числом сообщений, которые не прочитал текущий
+// пользователь. Переписка доступна только её участникам.
+func (uc *ConversationUsecase) Get(ctx context.Context, id uuid.UUID) (*entity.Conversation, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	conversation, err := uc.conversationRepo.GetByID(ctx, id, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get conversation: %w", err)
+	}
+	if _, err := policy.AuthorizeAccessConversation(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (uc *ConversationUsecase) SendMessage(ctx context.Context, id uuid.UUID, body string) (*entity.Message, error) {
+	conversation, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actor, _ := policy.ActorFromContext(ctx)
+
+	message, err := newMessage(actor.UserID, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.send(ctx, conversation, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// send сохраняет сообщение в переписке, уведомляет собеседника и доставляет
+// сообщение подключённым клиентам обоих участников.
+func (uc *ConversationUsecase) send(ctx context.Context, conversation *entity.Conversation, message *entity.Message) error {
+	message.ConversationID = conversation.ID
+	if err := uc.conversationRepo.AddMessage(ctx, message); err != nil {
+		return fmt.Errorf("add message: %w", err)
+	}
+	conversation.LastMessageAt = message.CreatedAt
+
+	recipientID := conversation.Peer(message.SenderID)
+	event := entity.NewUserEvent(entity.EventMessage, message.ID, conversation.BuyerID, conversation.SellerID)
+	if err := uc.events.Publish(ctx, event); err != nil {
+		uc.logger.WithError(err).WithField("message_id", message.ID).Error("Failed to publish message event")
+	}
+
+	if err := uc.notifier.Notify(ctx, recipientID, entity.NotificationNewMessage, entity.NotificationData{
+		"conversation_id": conversation.ID,
+		"post_id":         conversation.PostID,
+		"message_id":      message.ID,
+		"sender_id":       message.SenderID,
+	}); err != nil {
+		uc.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id": recipientID,
+			"kind":    entity.NotificationNewMessage,
+		}).Error("Failed to create notification")
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"message_id":      message.ID,
+		"conversation_id": conversation.ID,
+		"sender_id":       message.SenderID,
+	}).Info("Message sent")
+
+	return nil
+}
+
+// ListMessages возвращает до limit сообщений переписки, начиная с последних,
+// и курсор следующей страницы. Курсор пуст, если более ранних сообщений нет.
+func (uc *ConversationUsecase) ListMessages(ctx context.Context, id uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, *entity.MessageCursor, error) {
+	conversation, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Лишнее сообщение показывает, есть ли следующая страница.
+	messages, err := uc.conversationRepo.ListMessages(ctx, conversation.ID, cursor, limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list messages: %w", err)
+	}
+
+	var next *entity.MessageCursor
+	if len(messages) > limit {
+		messages = messages[:limit]
+		last := messages[limit-1].Cursor()
+		next = &last
+	}
+	return messages, next, nil
+}
+
+// MarkRead отмечает прочитанными все сообщения собеседника в переписке и
+// возвращает их число.
+func (uc *ConversationUsecase) MarkRead(ctx context.Context, id uuid.UUID) (int64, error) {
+	conversation, err := uc.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	actor, _ := policy.ActorFromContext(ctx)
+
+	read, err := uc.conversationRepo.MarkRead(ctx, conversation.ID, actor.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("mark messages read: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"conversation_id": conversation.ID,
+		"user_id":         actor.UserID,
+		"read":            read,
+	}).Info("Messages marked as read")
+
+	return read, nil
+}
+
+// Block добавляет пользователя в чёрный список текущего пользователя. Уже
+// начатые переписки с ним остаются, новые он начать не сможет.
+func (uc *ConversationUsecase) Block(ctx context.Context, userID uuid.UUID) error {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return apperror.Unauthorized("authentication required")
+	}
+	if userID == actor.UserID {
+		return apperror.Validation("you can't block yourself")
+	}
+
+	if err := uc.conversationRepo.Block(ctx, actor.UserID, userID); err != nil {
+		return fmt.Errorf("block user: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":         actor.UserID,
+		"blocked_user_id": userID,
+	}).Info("User blocked")
+	return nil
+}
+
+func (uc *ConversationUsecase) Unblock(ctx context.Context, userID uuid.UUID) error {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return apperror.Unauthorized("authentication required")
+	}
+
+	if err := uc.conversationRepo.Unblock(ctx, actor.UserID, userID); err != nil {
+		return fmt.Errorf("unblock user: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":         actor.UserID,
+		"blocked_user_id": userID,
+	}).Info("User unblocked")
+	return nil
+}
+
+func (uc *ConversationUsecase) ListBlocked(ctx context.Context) ([]*entity.BlockedUser, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	blocked, err := uc.conversationRepo.ListBlocked(ctx, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("list blocked users: %w", err)
+	}
+	return blocked, nil
+}
+
+func newMessage(senderID uuid.UUID, body string) (*entity.Message, error) {
+	message := &entity.Message{
+		ID:        uuid.New(),
+		SenderID:  senderID,
+		Body:      entity.NormalizeMessageBody(body),
+		CreatedAt: time.Now(),
+	}
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type ConversationUseCaseRepo interface {
+	Start(ctx context.Context, postID uuid.UUID, body string) (*entity.Conversation, *entity.Message, error)
+	List(ctx context.Context, page, pageSize int) ([]*entity.Conversation, int, error)
+	Get(ctx context.Context, id uuid.UUID) (*entity.Conversation, error)
+	SendMessage(ctx context.Context, id uuid.UUID, body string) (*entity.Message, error)
+	ListMessages(ctx context.Context, id uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, *entity.MessageCursor, error)
+	MarkRead(ctx context.Context, id uuid.UUID) (int64, error)
+	Block(ctx context.Context, userID uuid.UUID) error
+	Unblock(ctx context.Context, userID uuid.UUID) error
+	ListBlocked(ctx context.Context) ([]*entity.BlockedUser, error)
+}
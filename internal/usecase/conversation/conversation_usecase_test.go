@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockConversationRepository struct {
+	mock.Mock
+}
+
+func (m *MockConversationRepository) Create(ctx context.Context, conversation *entity.Conversation) error {
+	args := m.Called(ctx, conversation)
+	return args.Error(0)
+}
+
+func (m *MockConversationRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*entity.Conversation, error) {
+	args := m.Called(ctx, id, userID)
+	conversation, _ := args.Get(0).(*entity.Conversation)
+	return conversation, args.Error(1)
+}
+
+func (m *MockConversationRepository) GetByPostAndBuyer(ctx context.Context, postID, buyerID uuid.UUID) (*entity.Conversation, error) {
+	args := m.Called(ctx, postID, buyerID)
+	conversation, _ := args.Get(0).(*entity.Conversation)
+	return conversation, args.Error(1)
+}
+
+func (m *MockConversationRepository) ListByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Conversation, int, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	return args.Get(0).([]*entity.Conversation), args.Int(1), args.Error(2)
+}
+
+func (m *MockConversationRepository) AddMessage(ctx context.Context, message *entity.Message) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+func (m *MockConversationRepository) ListMessages(ctx context.Context, conversationID uuid.UUID, cursor *entity.MessageCursor, limit int) ([]*entity.Message, error) {
+	args := m.Called(ctx, conversationID, cursor, limit)
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockConversationRepository) MarkRead(ctx context.Context, conversationID, readerID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, conversationID, readerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockConversationRepository) Block(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	args := m.Called(ctx, userID, blockedUserID)
+	return args.Error(0)
+}
+
+func (m *MockConversationRepository) Unblock(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	args := m.Called(ctx, userID, blockedUserID)
+	return args.Error(0)
+}
+
+func (m *MockConversationRepository) IsBlocked(ctx context.Context, userID, blockedUserID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, blockedUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockConversationRepository) ListBlocked(ctx context.Context, userID uuid.UUID) ([]*entity.BlockedUser, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*entity.BlockedUser), args.Error(1)
+}
+
+type MockPostRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	post, _ := args.Get(0).(*entity.Post)
+	return post, args.Error(1)
+}
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, event *entity.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, userID uuid.UUID, kind entity.NotificationKind, data entity.NotificationData) error {
+	args := m.Called(ctx, userID, kind, data)
+	return args.Error(0)
+}
+
+func userContext(userID uuid.UUID) context.Context {
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	return context.WithValue(ctx, "user_role", entity.RoleUser)
+}
+
+func TestConversationUsecase_Start(t *testing.T) {
+	buyerID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), AuthorID: uuid.New(), Header: "Велосипед", Status: entity.PostPublished}
+
+	conversationRepo := new(MockConversationRepository)
+	postRepo := new(MockPostRepository)
+	notifier := new(MockNotifier)
+	postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	conversationRepo.On("GetByPostAndBuyer", mock.Anything, post.ID, buyerID).Return(nil, apperror.NotFound("conversation not found"))
+	conversationRepo.On("IsBlocked", mock.Anything, post.AuthorID, buyerID).Return(false, nil)
+	conversationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Conversation")).Return(nil)
+	conversationRepo.On("AddMessage", mock.Anything, mock.AnythingOfType("*entity.Message")).Return(nil)
+	notifier.On("Notify", mock.Anything, post.AuthorID, entity.NotificationNewMessage, mock.AnythingOfType("entity.NotificationData")).Return(nil)
+	events := new(MockPublisher)
+	events.On("Publish", mock.Anything, mock.MatchedBy(func(event *entity.Event) bool {
+		return event.Type == entity.EventMessage && len(event.UserIDs) == 2 && event.ObjectID != nil
+	})).Return(nil)
+
+	uc := NewConversationUsecase(conversationRepo, postRepo, notifier, events, logrus.New())
+	conversation, message, err := uc.Start(userContext(buyerID), post.ID, "  Ещё продаёте?  ")
+	require.NoError(t, err)
+	assert.Equal(t, buyerID, conversation.BuyerID)
+	assert.Equal(t, post.AuthorID, conversation.SellerID)
+	assert.Equal(t, "Велосипед", conversation.PostHeader)
+	assert.Equal(t, conversation.ID, message.ConversationID)
+	assert.Equal(t, "Ещё продаёте?", message.Body)
+	notifier.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestConversationUsecase_StartBlocked(t *testing.T) {
+	buyerID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), AuthorID: uuid.New(), Status: entity.PostPublished}
+
+	conversationRepo := new(MockConversationRepository)
+	postRepo := new(MockPostRepository)
+	postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	conversationRepo.On("GetByPostAndBuyer", mock.Anything, post.ID, buyerID).Return(nil, apperror.NotFound("conversation not found"))
+	conversationRepo.On("IsBlocked", mock.Anything, post.AuthorID, buyerID).Return(true, nil)
+
+	uc := NewConversationUsecase(conversationRepo, postRepo, new(MockNotifier), new(MockPublisher), logrus.New())
+	_, _, err := uc.Start(userContext(buyerID), post.ID, "Ещё продаёте?")
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	conversationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	conversationRepo.AssertNotCalled(t, "AddMessage", mock.Anything, mock.Anything)
+}
+
+func TestConversationUsecase_SendMessageParticipantsOnly(t *testing.T) {
+	conversation := &entity.Conversation{ID: uuid.New(), BuyerID: uuid.New(), SellerID: uuid.New()}
+	strangerID := uuid.New()
+
+	conversationRepo := new(MockConversationRepository)
+	conversationRepo.On("GetByID", mock.Anything, conversation.ID, strangerID).Return(conversation, nil)
+
+	uc := NewConversationUsecase(conversationRepo, new(MockPostRepository), new(MockNotifier), new(MockPublisher), logrus.New())
+	_, err := uc.SendMessage(userContext(strangerID), conversation.ID, "Привет")
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	conversationRepo.AssertNotCalled(t, "AddMessage", mock.Anything, mock.Anything)
+}
+
+func TestConversationUsecase_ListMessages(t *testing.T) {
+	conversation := &entity.Conversation{ID: uuid.New(), BuyerID: uuid.New(), SellerID: uuid.New()}
+	now := time.Now()
+	messages := []*entity.Message{
+		{ID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	conversationRepo := new(MockConversationRepository)
+	conversationRepo.On("GetByID", mock.Anything, conversation.ID, conversation.BuyerID).Return(conversation, nil)
+	conversationRepo.On("ListMessages", mock.Anything, conversation.ID, (*entity.MessageCursor)(nil), 3).Return(messages, nil)
+
+	uc := NewConversationUsecase(conversationRepo, new(MockPostRepository), new(MockNotifier), new(MockPublisher), logrus.New())
+	page, next, err := uc.ListMessages(userContext(conversation.BuyerID), conversation.ID, nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	require.NotNil(t, next)
+	assert.Equal(t, messages[1].Cursor(), *next)
+
+	cursor := messages[1].Cursor()
+	conversationRepo.On("ListMessages", mock.Anything, conversation.ID, &cursor, 3).Return(messages[2:], nil)
+	page, next, err = uc.ListMessages(userContext(conversation.BuyerID), conversation.ID, &cursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Nil(t, next)
+}
+
+func TestConversationUsecase_BlockSelf(t *testing.T) {
+	userID := uuid.New()
+	conversationRepo := new(MockConversationRepository)
+
+	uc := NewConversationUsecase(conversationRepo, new(MockPostRepository), new(MockNotifier), new(MockPublisher), logrus.New())
+	err := uc.Block(userContext(userID), userID)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	conversationRepo.AssertNotCalled(t, "Block", mock.Anything, mock.Anything, mock.Anything)
+}
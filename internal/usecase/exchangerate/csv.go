@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"strings"
+	"time"
+)
+
+// parseRatesCSV разбирает CSV со строками base,quote,rate. Строка заголовка
+// необязательна. Ошибки собираются по всем строкам с их номерами.
+func parseRatesCSV(r io.Reader, now time.Time) ([]*entity.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var rates []*entity.ExchangeRate
+	seen := make(map[[2]entity.Currency]int)
+	var v apperror.Violations
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, apperror.InvalidField(fmt.Sprintf("line %d", parseErr.Line), "%v", parseErr.Err)
+			}
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(record[0], "base") {
+			continue
+		}
+
+		rate := &entity.ExchangeRate{
+			Base:      entity.Currency(strings.ToUpper(strings.TrimSpace(record[0]))),
+			Quote:     entity.Currency(strings.ToUpper(strings.TrimSpace(record[1]))),
+			Rate:      strings.TrimSpace(record[2]),
+			UpdatedAt: now,
+		}
+		field := fmt.Sprintf("line %d", line)
+		for _, violation := range apperror.ViolationsOf(rate.Validate()) {
+			v.Add(field, "%s", violation.Message)
+		}
+
+		pair := [2]entity.Currency{rate.Base, rate.Quote}
+		if first, ok := seen[pair]; ok {
+			v.Add(field, "rate %s/%s is already set on line %d", rate.Base, rate.Quote, first)
+		}
+		seen[pair] = line
+		rates = append(rates, rate)
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"errors"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRatesCSV(t *testing.T) {
+	now := time.Now()
+	rates, err := parseRatesCSV(strings.NewReader("base,quote,rate\nusd, rub, 92.5\nEUR,USD,1.08\n"), now)
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.ExchangeRate{
+		{Base: "USD", Quote: "RUB", Rate: "92.5", UpdatedAt: now},
+		{Base: "EUR", Quote: "USD", Rate: "1.08", UpdatedAt: now},
+	}, rates)
+
+	rates, err = parseRatesCSV(strings.NewReader("USD,RUB,92.5\n"), now)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 1)
+}
+
+func TestParseRatesCSV_Errors(t *testing.T) {
+	_, err := parseRatesCSV(strings.NewReader("USD,RUB,92.5\nUSD,XXX,1\nUSD,RUB,93\n"), time.Now())
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	assert.Equal(t, []apperror.Violation{
+		{Field: "line 2", Message: `unsupported currency "XXX"`},
+		{Field: "line 3", Message: "rate USD/RUB is already set on line 1"},
+	}, apperror.ViolationsOf(err))
+
+	_, err = parseRatesCSV(strings.NewReader("USD,RUB\n"), time.Now())
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	assert.Equal(t, "line 1", apperror.ViolationsOf(err)[0].Field)
+}
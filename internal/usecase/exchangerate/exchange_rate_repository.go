@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+)
+
+type ExchangeRateRepository interface {
+	List(ctx context.Context) ([]*entity.ExchangeRate, error)
+	Upsert(ctx context.Context, rates []*entity.ExchangeRate) error
+	Delete(ctx context.Context, base, quote entity.Currency) error
+}
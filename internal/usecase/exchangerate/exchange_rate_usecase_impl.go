@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/usecase/policy"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ExchangeRateUsecase struct {
+	rateRepo ExchangeRateRepository
+	logger   *logrus.Logger
+}
+
+func NewExchangeRateUsecase(rateRepo ExchangeRateRepository, logger *logrus.Logger) *ExchangeRateUsecase {
+	return &ExchangeRateUsecase{
+		rateRepo: rateRepo,
+		logger:   logger,
+	}
+}
+
+func (uc *ExchangeRateUsecase) List(ctx context.Context) ([]*entity.ExchangeRate, error) {
+	rates, err := uc.rateRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+func (uc *ExchangeRateUsecase) Set(ctx context.Context, base, quote entity.Currency, rate string) (*entity.ExchangeRate, error) {
+	actor, err := policy.AuthorizeManageExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRate := &entity.ExchangeRate{
+		Base:      base,
+		Quote:     quote,
+		Rate:      rate,
+		UpdatedAt: time.Now(),
+	}
+	if err := exchangeRate.Validate(); err != nil {
+		return nil, fmt.Errorf("validate exchange rate: %w", err)
+	}
+
+	if err := uc.rateRepo.Upsert(ctx, []*entity.ExchangeRate{exchangeRate}); err != nil {
+		return nil, fmt.Errorf("save exchange rate: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"base":     base,
+		"quote":    quote,
+		"rate":     rate,
+		"actor_id": actor.UserID,
+	}).Info("Exchange rate set")
+
+	return exchangeRate, nil
+}
+
+// Import загружает курсы из CSV со строками base,quote,rate. Если хотя бы
+// одна строка неверна, не сохраняется ничего.
+func (uc *ExchangeRateUsecase) Import(ctx context.Context, r io.Reader) (int, error) {
+	actor, err := policy.AuthorizeManageExchangeRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rates, err := parseRatesCSV(r, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("parse exchange rates: %w", err)
+	}
+	if len(rates) == 0 {
+		return 0, apperror.Validation("CSV contains no exchange rates")
+	}
+
+	if err := uc.rateRepo.Upsert(ctx, rates); err != nil {
+		return 0, fmt.Errorf("save exchange rates: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"count":    len(rates),
+		"actor_id": actor.UserID,
+	}).Info("Exchange rates imported")
+
+	return len(rates), nil
+}
+
+func (uc *ExchangeRateUsecase) Delete(ctx context.Context, base, quote entity.Currency) error {
+	actor, err := policy.AuthorizeManageExchangeRates(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rateRepo.Delete(ctx, base, quote); err != nil {
+		return fmt.Errorf("delete exchange rate: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"base":     base,
+		"quote":    quote,
+		"actor_id": actor.UserID,
+	}).Info("Exchange rate deleted")
+
+	return nil
+}
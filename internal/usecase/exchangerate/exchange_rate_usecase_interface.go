@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/entity"
+)
+
+type ExchangeRateUseCaseRepo interface {
+	List(ctx context.Context) ([]*entity.ExchangeRate, error)
+	Set(ctx context.Context, base, quote entity.Currency, rate string) (*entity.ExchangeRate, error)
+	Import(ctx context.Context, r io.Reader) (int, error)
+	Delete(ctx context.Context, base, quote entity.Currency) error
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImageRepository interface {
+	Create(ctx context.Context, image *entity.Image) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Image, error)
+	ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Image, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListPendingVariants(ctx context.Context, limit int) ([]*entity.Image, error)
+	MarkVariantsGenerated(ctx context.Context, id uuid.UUID) error
+	DeferVariants(ctx context.Context, id uuid.UUID, backoff time.Duration) (int, error)
+	SaveVariant(ctx context.Context, variant *entity.ImageVariant) error
+	GetVariant(ctx context.Context, imageID uuid.UUID, width int, format string) (*entity.ImageVariant, error)
+}
+
+// VariantNotifier будит фоновую генерацию уменьшенных копий.
+type VariantNotifier interface {
+	Notify()
+}
+
+// ImageStorage — хранилище файлов изображений (локальный диск или S3).
+type ImageStorage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/pkg/storage"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var imageExtensions = map[string]string{
+	entity.ImageJPEG: ".jpg",
+	entity.ImagePNG:  ".png",
+}
+
+type ImageUsecase struct {
+	imageRepo ImageRepository
+	storage   ImageStorage
+	maxSize   int64
+	maxPixels int
+	logger    *logrus.Logger
+}
+
+func NewImageUsecase(imageRepo ImageRepository, storage ImageStorage, maxSize int64, maxPixels int, logger *logrus.Logger) *ImageUsecase {
+	return &ImageUsecase{
+		imageRepo: imageRepo,
+		storage:   storage,
+		maxSize:   maxSize,
+		maxPixels: maxPixels,
+		logger:    logger,
+	}
+}
+
+func (uc *ImageUsecase) Upload(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error) {
+	if int64(len(data)) > uc.maxSize {
+		return nil, apperror.TooLarge("file must not exceed %d bytes", uc.maxSize)
+	}
+
+	// Тип определяется по сигнатуре файла, а не по заголовку или расширению.
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, apperror.InvalidField("file", "file must be a PNG or JPEG image")
+	}
+
+	// Размеры читаются из заголовка без декодирования пикселей, чтобы
+	// маленький файл с огромным разрешением не занял всю память.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.InvalidField("file", "file is not a valid image")
+	}
+	if config.Width*config.Height > uc.maxPixels {
+		return nil, apperror.InvalidField("file", "image must not exceed %d pixels", uc.maxPixels)
+	}
+
+	stripped, err := stripMetadata(contentType, data)
+	if err != nil {
+		return nil, apperror.InvalidField("file", "file is not a valid image")
+	}
+
+	// Ширина и высота — как снимок будет показан с учётом Orientation.
+	width, height := orientedSize(config.Width, config.Height, jpegOrientation(stripped))
+
+	id := uuid.New()
+	img := &entity.Image{
+		ID:          id,
+		OwnerID:     ownerID,
+		ContentType: contentType,
+		Size:        int64(len(stripped)),
+		Width:       width,
+		Height:      height,
+		StorageKey:  "images/" + id.String() + ext,
+		URL:         entity.ImageURL(id),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := uc.storage.Put(ctx, img.StorageKey, stripped, contentType); err != nil {
+		return nil, fmt.Errorf("store image: %w", err)
+	}
+	if err := uc.imageRepo.Create(ctx, img); err != nil {
+		if deleteErr := uc.storage.Delete(ctx, img.StorageKey); deleteErr != nil {
+			uc.logger.WithError(deleteErr).WithField("storage_key", img.StorageKey).Warn("Failed to remove orphaned image file")
+		}
+		return nil, fmt.Errorf("create image: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"image_id":     img.ID,
+		"owner_id":     ownerID,
+		"content_type": contentType,
+		"size":         img.Size,
+	}).Info("Image uploaded")
+
+	return img, nil
+}
+
+func (uc *ImageUsecase) Open(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error) {
+	img, err := uc.imageRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get image: %w", err)
+	}
+
+	reader, err := uc.storage.Get(ctx, img.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, apperror.NotFound("image not found")
+		}
+		return nil, nil, fmt.Errorf("read image: %w", err)
+	}
+	img.URL = entity.ImageURL(img.ID)
+
+	return img, reader, nil
+}
+
+func (uc *ImageUsecase) OpenVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error) {
+	variant, err := uc.imageRepo.GetVariant(ctx, id, width, format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get image variant: %w", err)
+	}
+
+	reader, err := uc.storage.Get(ctx, variant.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, apperror.NotFound("image variant not found")
+		}
+		return nil, nil, fmt.Errorf("read image variant: %w", err)
+	}
+
+	return variant, reader, nil
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type ImageUseCaseRepo interface {
+	Upload(ctx context.Context, ownerID uuid.UUID, data []byte) (*entity.Image, error)
+	Open(ctx context.Context, id uuid.UUID) (*entity.Image, io.ReadCloser, error)
+	OpenVariant(ctx context.Context, id uuid.UUID, width int, format string) (*entity.ImageVariant, io.ReadCloser, error)
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) Create(ctx context.Context, image *entity.Image) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Image, error) {
+	args := m.Called(ctx, id)
+	image, _ := args.Get(0).(*entity.Image)
+	return image, args.Error(1)
+}
+
+func (m *MockImageRepository) ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Image, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*entity.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) ListPendingVariants(ctx context.Context, limit int) ([]*entity.Image, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*entity.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) MarkVariantsGenerated(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) DeferVariants(ctx context.Context, id uuid.UUID, backoff time.Duration) (int, error) {
+	args := m.Called(ctx, id, backoff)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImageRepository) SaveVariant(ctx context.Context, variant *entity.ImageVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) GetVariant(ctx context.Context, imageID uuid.UUID, width int, format string) (*entity.ImageVariant, error) {
+	args := m.Called(ctx, imageID, width, format)
+	variant, _ := args.Get(0).(*entity.ImageVariant)
+	return variant, args.Error(1)
+}
+
+type MockImageStorage struct {
+	mock.Mock
+}
+
+func (m *MockImageStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	args := m.Called(ctx, key, data, contentType)
+	return args.Error(0)
+}
+
+func (m *MockImageStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	reader, _ := args.Get(0).(io.ReadCloser)
+	return reader, args.Error(1)
+}
+
+func (m *MockImageStorage) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func TestUpload(t *testing.T) {
+	repo := new(MockImageRepository)
+	storage := new(MockImageStorage)
+	uc := NewImageUsecase(repo, storage, 1<<20, 1000, logrus.New())
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage()))
+	ownerID := uuid.New()
+
+	storage.On("Put", mock.Anything, mock.AnythingOfType("string"), buf.Bytes(), entity.ImagePNG).Return(nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Image")).Return(nil)
+
+	img, err := uc.Upload(context.Background(), ownerID, buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, ownerID, img.OwnerID)
+	assert.Equal(t, entity.ImagePNG, img.ContentType)
+	assert.Equal(t, 4, img.Width)
+	assert.Equal(t, 3, img.Height)
+	assert.Equal(t, "images/"+img.ID.String()+".png", img.StorageKey)
+	assert.Equal(t, "/images/"+img.ID.String(), img.URL)
+	storage.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestUpload_Rejected(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage()))
+
+	tests := []struct {
+		name      string
+		maxSize   int64
+		maxPixels int
+		data      []byte
+		kind      error
+	}{
+		{"too large", 10, 1000, buf.Bytes(), apperror.ErrTooLarge},
+		{"not an image", 1 << 20, 1000, []byte("GIF89a not really"), apperror.ErrValidation},
+		{"disguised text", 1 << 20, 1000, []byte("<html>image.png</html>"), apperror.ErrValidation},
+		{"too many pixels", 1 << 20, 11, buf.Bytes(), apperror.ErrValidation},
+		{"truncated", 1 << 20, 1000, buf.Bytes()[:20], apperror.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockImageRepository)
+			storage := new(MockImageStorage)
+			uc := NewImageUsecase(repo, storage, tt.maxSize, tt.maxPixels, logrus.New())
+
+			_, err := uc.Upload(context.Background(), uuid.New(), tt.data)
+			assert.ErrorIs(t, err, tt.kind)
+			storage.AssertNotCalled(t, "Put")
+		})
+	}
+}
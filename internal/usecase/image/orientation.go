@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// orientationTag — тег EXIF Orientation: как повернуть или отразить снимок,
+// чтобы показать его правильно. Камеры телефонов пишут пиксели как есть и
+// задают поворот этим тегом.
+const orientationTag = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation читает Orientation из содержимого сегмента APP1. Если
+// сегмент не EXIF или тега нет, возвращает 1 — снимок показывается как есть.
+func exifOrientation(payload []byte) int {
+	if !bytes.HasPrefix(payload, exifHeader) {
+		return 1
+	}
+	tiff := payload[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// Значение SHORT лежит в начале четырёхбайтового поля.
+		if order.Uint16(tiff[entry:]) != orientationTag || order.Uint16(tiff[entry+2:]) != 3 {
+			continue
+		}
+		if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+			return orientation
+		}
+		return 1
+	}
+	return 1
+}
+
+// orientationSegment — сегмент APP1 с EXIF, в котором есть только
+// Orientation.
+func orientationSegment(orientation int) []byte {
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	segment = append(segment, exifHeader...)
+	// Заголовок TIFF: порядок байт, 42 и смещение IFD0.
+	segment = append(segment, 'M', 'M', 0, 42, 0, 0, 0, 8)
+	// IFD0 из одной записи SHORT и нулевое смещение следующего IFD.
+	segment = append(segment, 0, 1)
+	segment = append(segment, orientationTag>>8, orientationTag&0xFF, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0)
+	segment = append(segment, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(segment)-2))
+	return segment
+}
+
+// jpegOrientation возвращает Orientation из первого сегмента EXIF файла JPEG
+// или 1, если его нет.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == 0xFF {
+			i++
+			continue
+		}
+		if marker == 0xD9 || marker == 0xDA {
+			return 1
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 && bytes.HasPrefix(data[i+4:end], exifHeader) {
+			return exifOrientation(data[i+4 : end])
+		}
+		i = end
+	}
+	return 1
+}
+
+// orientedSize — размеры снимка после применения orientation.
+func orientedSize(width, height, orientation int) (int, int) {
+	if orientation >= 5 && orientation <= 8 {
+		return height, width
+	}
+	return width, height
+}
+
+// orient поворачивает и отражает пиксели так, как того требует orientation.
+func orient(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	in := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(in, in.Bounds(), src, bounds.Min, draw.Src)
+
+	outWidth, outHeight := orientedSize(width, height, orientation)
+	out := image.NewNRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < outHeight; y++ {
+		for x := 0; x < outWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = width-1-x, y
+			case 3:
+				sx, sy = width-1-x, height-1-y
+			case 4:
+				sx, sy = x, height-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, height-1-x
+			case 7:
+				sx, sy = width-1-y, height-1-x
+			case 8:
+				sx, sy = width-1-y, x
+			}
+			copy(out.Pix[out.PixOffset(x, y):out.PixOffset(x, y)+4], in.Pix[in.PixOffset(sx, sy):in.PixOffset(sx, sy)+4])
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"marketplace/internal/entity"
+)
+
+var errMalformedImage = errors.New("malformed image")
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Чанки PNG с метаданными: EXIF, текстовые комментарии и время изменения.
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripMetadata удаляет из файла EXIF (в том числе геометки) и другие
+// метаданные, не перекодируя изображение.
+func stripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case entity.ImageJPEG:
+		return stripJPEG(data)
+	case entity.ImagePNG:
+		return stripPNG(data)
+	default:
+		return nil, errMalformedImage
+	}
+}
+
+// stripJPEG выбрасывает сегменты APP1 (EXIF, XMP) и APP13 (IPTC). Остальные
+// сегменты, включая цветовой профиль в APP2, копируются как есть. Из EXIF
+// сохраняется только Orientation: без неё снимки с телефона показывались бы
+// повёрнутыми.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errMalformedImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	oriented := false
+	for i := 2; ; {
+		if i+2 > len(data) || data[i] != 0xFF {
+			return nil, errMalformedImage
+		}
+		marker := data[i+1]
+
+		switch {
+		case marker == 0xFF:
+			// Байты-заполнители перед маркером.
+			i++
+			continue
+		case marker == 0xD9:
+			out.Write(data[i : i+2])
+			return out.Bytes(), nil
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			out.Write(data[i : i+2])
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, errMalformedImage
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, errMalformedImage
+		}
+
+		// После SOS идут сжатые данные до конца файла, их копируем целиком.
+		if marker == 0xDA {
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+		if marker != 0xE1 && marker != 0xED {
+			out.Write(data[i:end])
+		}
+		if marker == 0xE1 && !oriented {
+			if orientation := exifOrientation(data[i+4 : end]); orientation > 1 {
+				out.Write(orientationSegment(orientation))
+				oriented = true
+			}
+		}
+		i = end
+	}
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errMalformedImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, errMalformedImage
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		// Длина, тип, данные и CRC.
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errMalformedImage
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out.Write(data[i:end])
+		}
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+		i = end
+	}
+	return nil, errMalformedImage
+}
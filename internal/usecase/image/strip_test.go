@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"marketplace/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 60), G: uint8(y * 80), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+func TestStripMetadata_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(), nil))
+	encoded := buf.Bytes()
+
+	// Вставляем сегмент APP1 с EXIF сразу после SOI.
+	exif := append([]byte("Exif\x00\x00"), []byte("GPS 55.7558N 37.6173E")...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(exif)+2))
+	data := append(append(append([]byte{}, encoded[:2]...), append(segment, exif...)...), encoded[2:]...)
+
+	stripped, err := stripMetadata(entity.ImageJPEG, data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(stripped), "Exif")
+	assert.NotContains(t, string(stripped), "GPS")
+	assert.Equal(t, encoded, stripped)
+
+	_, err = jpeg.Decode(bytes.NewReader(stripped))
+	assert.NoError(t, err)
+}
+
+func TestStripMetadata_JPEGKeepsOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(), nil))
+	encoded := buf.Bytes()
+
+	// EXIF в порядке байт Intel: Orientation=6 (повернуть на 90° по часовой)
+	// и строка с геометкой после IFD0.
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0}
+	tiff = append(tiff, 0x12, 0x01, 3, 0, 1, 0, 0, 0, 6, 0, 0, 0)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, []byte("GPS 55.7558N 37.6173E")...)
+	exif := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(exif)+2))
+	data := append(append(append([]byte{}, encoded[:2]...), append(segment, exif...)...), encoded[2:]...)
+	require.Equal(t, 6, jpegOrientation(data))
+
+	stripped, err := stripMetadata(entity.ImageJPEG, data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(stripped), "GPS")
+	assert.Equal(t, 6, jpegOrientation(stripped))
+
+	decoded, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 3, 4), orient(decoded, jpegOrientation(stripped)).Bounds())
+}
+
+func TestOrient(t *testing.T) {
+	src := testImage()
+	// Левый нижний угол исходника после поворота на 90° по часовой — левый
+	// верхний, правый верхний — правый нижний.
+	rotated := orient(src, 6)
+	assert.Equal(t, image.Rect(0, 0, 3, 4), rotated.Bounds())
+	assert.Equal(t, color.NRGBAModel.Convert(src.At(0, 2)), rotated.At(0, 0))
+	assert.Equal(t, color.NRGBAModel.Convert(src.At(3, 0)), rotated.At(2, 3))
+
+	flipped := orient(src, 3)
+	assert.Equal(t, color.NRGBAModel.Convert(src.At(3, 2)), flipped.At(0, 0))
+
+	assert.Same(t, src, orient(src, 1))
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage()))
+	encoded := buf.Bytes()
+
+	// Вставляем eXIf и tEXt перед IEND (последние 12 байт файла).
+	iend := len(encoded) - 12
+	var data []byte
+	data = append(data, encoded[:iend]...)
+	data = append(data, pngChunk("eXIf", []byte("MM\x00*GPS"))...)
+	data = append(data, pngChunk("tEXt", []byte("Author\x00Alice"))...)
+	data = append(data, encoded[iend:]...)
+
+	stripped, err := stripMetadata(entity.ImagePNG, data)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, stripped)
+
+	_, err = png.Decode(bytes.NewReader(stripped))
+	assert.NoError(t, err)
+}
+
+func TestStripMetadata_Malformed(t *testing.T) {
+	_, err := stripMetadata(entity.ImageJPEG, []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF})
+	assert.Error(t, err)
+
+	_, err = stripMetadata(entity.ImagePNG, append(append([]byte{}, pngSignature...), 0, 0, 0, 100))
+	assert.Error(t, err)
+}
+
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	crc := crc32.ChecksumIEEE(chunk[4:])
+	return binary.BigEndian.AppendUint32(chunk, crc)
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"marketplace/internal/entity"
+	"marketplace/pkg/poller"
+	"marketplace/pkg/storage"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+)
+
+const (
+	variantBatchSize   = 20
+	variantJPEGQuality = 82
+	// maxVariantAttempts — после стольких неудач изображение остаётся без
+	// копий, и в постах показывается оригинал.
+	maxVariantAttempts = 5
+)
+
+// errUndecodable — исходный файл не удалось декодировать; повторять попытку
+// бессмысленно.
+var errUndecodable = errors.New("image can't be decoded")
+
+// VariantWorker в фоне строит уменьшенные копии изображений, прикреплённых к
+// постам. Очередь — изображения без variants_generated_at в базе.
+type VariantWorker struct {
+	imageRepo ImageRepository
+	storage   ImageStorage
+	widths    []int
+	interval  time.Duration
+	poller    *poller.Poller
+	logger    *logrus.Logger
+}
+
+func NewVariantWorker(imageRepo ImageRepository, storage ImageStorage, widths []int, interval time.Duration, logger *logrus.Logger) *VariantWorker {
+	return &VariantWorker{
+		imageRepo: imageRepo,
+		storage:   storage,
+		widths:    widths,
+		interval:  interval,
+		poller:    poller.New(interval),
+		logger:    logger,
+	}
+}
+
+// Notify сообщает воркеру, что появились новые изображения. Не блокируется.
+func (w *VariantWorker) Notify() {
+	w.poller.Notify()
+}
+
+// Run обрабатывает задания, пока не будет отменён контекст.
+func (w *VariantWorker) Run(ctx context.Context) {
+	w.poller.Run(ctx, w.processPending)
+}
+
+func (w *VariantWorker) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		images, err := w.imageRepo.ListPendingVariants(ctx, variantBatchSize)
+		if err != nil {
+			w.logger.WithError(err).Error("Failed to list images pending variants")
+			return
+		}
+
+		for _, img := range images {
+			err := w.Generate(ctx, img)
+			switch {
+			case err == nil:
+			case errors.Is(err, errUndecodable), errors.Is(err, storage.ErrNotFound):
+				// Оригинал испорчен или пропал: повторять бессмысленно.
+				w.logger.WithError(err).WithField("image_id", img.ID).Warn("Skipping image variants")
+			default:
+				// Ошибка хранилища или базы: изображение откладывается, чтобы
+				// не задерживать остальную очередь.
+				if err := w.deferImage(ctx, img, err); err != nil {
+					w.logger.WithError(err).WithField("image_id", img.ID).Error("Failed to defer image variants")
+					return
+				}
+				continue
+			}
+			if err := w.imageRepo.MarkVariantsGenerated(ctx, img.ID); err != nil {
+				w.logger.WithError(err).WithField("image_id", img.ID).Error("Failed to mark image variants generated")
+				return
+			}
+		}
+
+		if len(images) < variantBatchSize {
+			return
+		}
+	}
+}
+
+// deferImage откладывает изображение после неудачной попытки. Когда попытки
+// исчерпаны, изображение снимается с очереди.
+func (w *VariantWorker) deferImage(ctx context.Context, img *entity.Image, cause error) error {
+	attempts, err := w.imageRepo.DeferVariants(ctx, img.ID, w.interval)
+	if err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{"image_id": img.ID, "attempts": attempts}
+	if attempts < maxVariantAttempts {
+		w.logger.WithError(cause).WithFields(fields).Error("Failed to generate image variants, will retry")
+		return nil
+	}
+	w.logger.WithError(cause).WithFields(fields).Warn("Giving up on image variants")
+	return w.imageRepo.MarkVariantsGenerated(ctx, img.ID)
+}
+
+// Generate строит варианты всех настроенных ширин, меньших исходной, в
+// формате оригинала и в WebP.
+func (w *VariantWorker) Generate(ctx context.Context, img *entity.Image) error {
+	reader, err := w.storage.Get(ctx, img.StorageKey)
+	if err != nil {
+		return fmt.Errorf("read image: %w", err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return fmt.Errorf("read image: %w", err)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndecodable, err)
+	}
+	// В копиях нет EXIF, поэтому поворот из Orientation применяется к пикселям.
+	src = orient(src, jpegOrientation(data))
+
+	formats := []string{entity.VariantJPEG, entity.VariantWebP}
+	if img.ContentType == entity.ImagePNG {
+		formats = []string{entity.VariantPNG, entity.VariantWebP}
+	}
+
+	bounds := src.Bounds()
+	for _, width := range w.widths {
+		if width >= bounds.Dx() {
+			continue
+		}
+		height := max(1, bounds.Dy()*width/bounds.Dx())
+		resized := resize(src, width, height)
+
+		for _, format := range formats {
+			if err := w.saveVariant(ctx, img.ID, resized, format); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"image_id": img.ID,
+	}).Info("Image variants generated")
+	return nil
+}
+
+func (w *VariantWorker) saveVariant(ctx context.Context, imageID uuid.UUID, img image.Image, format string) error {
+	data, err := encodeVariant(img, format)
+	if err != nil {
+		return fmt.Errorf("encode %s variant: %w", format, err)
+	}
+
+	bounds := img.Bounds()
+	variant := &entity.ImageVariant{
+		ImageID:     imageID,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Format:      format,
+		ContentType: entity.VariantContentType(format),
+		Size:        int64(len(data)),
+		StorageKey:  "images/" + imageID.String() + "/" + entity.VariantName(bounds.Dx(), format),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := w.storage.Put(ctx, variant.StorageKey, data, variant.ContentType); err != nil {
+		return fmt.Errorf("store variant: %w", err)
+	}
+	if err := w.imageRepo.SaveVariant(ctx, variant); err != nil {
+		return fmt.Errorf("save variant: %w", err)
+	}
+	return nil
+}
+
+func resize(src image.Image, width, height int) image.Image {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+func encodeVariant(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case entity.VariantJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: variantJPEGQuality})
+	case entity.VariantPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(&buf, img)
+	case entity.VariantWebP:
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		err = fmt.Errorf("unknown variant format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"testing"
+	"time"
+
+	"marketplace/internal/entity"
+	"marketplace/pkg/storage"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantWorker_Generate(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil))
+	img := &entity.Image{ID: uuid.New(), ContentType: entity.ImageJPEG, StorageKey: "images/original.jpg"}
+	require.NoError(t, store.Put(context.Background(), img.StorageKey, buf.Bytes(), entity.ImageJPEG))
+
+	repo := new(MockImageRepository)
+	var saved []*entity.ImageVariant
+	repo.On("SaveVariant", mock.Anything, mock.AnythingOfType("*entity.ImageVariant")).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(1).(*entity.ImageVariant)) }).
+		Return(nil)
+
+	worker := NewVariantWorker(repo, store, []int{320, 640, 1280}, 0, logrus.New())
+	require.NoError(t, worker.Generate(context.Background(), img))
+
+	// 1280 шире оригинала, поэтому такой копии нет.
+	require.Len(t, saved, 4)
+	for _, variant := range saved {
+		assert.Contains(t, []int{320, 640}, variant.Width)
+		assert.Equal(t, variant.Width*3/4, variant.Height)
+
+		reader, err := store.Get(context.Background(), variant.StorageKey)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		assert.Equal(t, variant.Size, int64(len(data)))
+
+		var decoded image.Image
+		switch variant.Format {
+		case entity.VariantJPEG:
+			decoded, err = jpeg.Decode(bytes.NewReader(data))
+		case entity.VariantWebP:
+			decoded, err = nativewebp.Decode(bytes.NewReader(data))
+		default:
+			t.Fatalf("unexpected format %s", variant.Format)
+		}
+		require.NoError(t, err)
+		assert.Equal(t, variant.Width, decoded.Bounds().Dx())
+	}
+}
+
+func TestVariantWorker_SkipsUndecodable(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	img := &entity.Image{ID: uuid.New(), ContentType: entity.ImagePNG, StorageKey: "images/broken.png"}
+	require.NoError(t, store.Put(context.Background(), img.StorageKey, []byte("broken"), entity.ImagePNG))
+
+	repo := new(MockImageRepository)
+	repo.On("ListPendingVariants", mock.Anything, variantBatchSize).Return([]*entity.Image{img}, nil)
+	repo.On("MarkVariantsGenerated", mock.Anything, img.ID).Return(nil)
+
+	worker := NewVariantWorker(repo, store, []int{320}, 0, logrus.New())
+	worker.processPending(context.Background())
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "SaveVariant")
+}
+
+func TestVariantWorker_SkipsMissingOriginal(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	img := &entity.Image{ID: uuid.New(), ContentType: entity.ImageJPEG, StorageKey: "images/missing.jpg"}
+
+	repo := new(MockImageRepository)
+	repo.On("ListPendingVariants", mock.Anything, variantBatchSize).Return([]*entity.Image{img}, nil)
+	repo.On("MarkVariantsGenerated", mock.Anything, img.ID).Return(nil)
+
+	worker := NewVariantWorker(repo, store, []int{320}, 0, logrus.New())
+	worker.processPending(context.Background())
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DeferVariants", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// failingStorage не может прочитать файл failKey, остальные читает как обычно.
+type failingStorage struct {
+	ImageStorage
+	failKey string
+}
+
+func (s *failingStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if key == s.failKey {
+		return nil, errors.New("storage unavailable")
+	}
+	return s.ImageStorage.Get(ctx, key)
+}
+
+func TestVariantWorker_DefersFailedImage(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10)), nil))
+
+	failing := &entity.Image{ID: uuid.New(), ContentType: entity.ImageJPEG, StorageKey: "images/failing.jpg"}
+	next := &entity.Image{ID: uuid.New(), ContentType: entity.ImageJPEG, StorageKey: "images/next.jpg"}
+	require.NoError(t, local.Put(context.Background(), next.StorageKey, buf.Bytes(), entity.ImageJPEG))
+	store := &failingStorage{ImageStorage: local, failKey: failing.StorageKey}
+
+	repo := new(MockImageRepository)
+	repo.On("ListPendingVariants", mock.Anything, variantBatchSize).Return([]*entity.Image{failing, next}, nil).Once()
+	repo.On("DeferVariants", mock.Anything, failing.ID, time.Minute).Return(1, nil).Once()
+	repo.On("MarkVariantsGenerated", mock.Anything, next.ID).Return(nil).Once()
+
+	worker := NewVariantWorker(repo, store, []int{320}, time.Minute, logrus.New())
+	worker.processPending(context.Background())
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkVariantsGenerated", mock.Anything, failing.ID)
+
+	// Последняя попытка снимает изображение с очереди.
+	repo.On("ListPendingVariants", mock.Anything, variantBatchSize).Return([]*entity.Image{failing}, nil).Once()
+	repo.On("DeferVariants", mock.Anything, failing.ID, time.Minute).Return(maxVariantAttempts, nil).Once()
+	repo.On("MarkVariantsGenerated", mock.Anything, failing.ID).Return(nil).Once()
+	worker.processPending(context.Background())
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *entity.Notification) error
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (entity.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID uuid.UUID, preferences entity.NotificationPreferences) error
+}
+
+// Notifier создаёт уведомления из других сценариев. Уведомления видов,
+// отключённых пользователем в настройках, не создаются.
+type Notifier interface {
+	Notify(ctx context.Context, userID uuid.UUID, kind entity.NotificationKind, data entity.NotificationData) error
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/usecase/policy"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationUsecase struct {
+	notificationRepo NotificationRepository
+	events           usecaseRealtime.Publisher
+	logger           *logrus.Logger
+}
+
+func NewNotificationUsecase(notificationRepo NotificationRepository, events usecaseRealtime.Publisher, logger *logrus.Logger) *NotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepo: notificationRepo,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+func (uc *NotificationUsecase) Notify(ctx context.Context, userID uuid.UUID, kind entity.NotificationKind, data entity.NotificationData) error {
+	preferences, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get notification preferences: %w", err)
+	}
+	if !preferences.Enabled(kind) {
+		return nil
+	}
+
+	notification := &entity.Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Kind:      kind,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.notificationRepo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"notification_id": notification.ID,
+		"user_id":         userID,
+		"kind":            kind,
+	}).Info("Notification created")
+
+	// Уведомление уже сохранено, поэтому сбой доставки в реальном времени
+	// только логируется: клиент увидит его в списке.
+	event := entity.NewUserEvent(entity.EventNotification, notification.ID, userID)
+	if err := uc.events.Publish(ctx, event); err != nil {
+		uc.logger.WithError(err).WithField("notification_id", notification.ID).Error("Failed to publish notification event")
+	}
+
+	return nil
+}
+
+// List возвращает страницу уведомлений текущего пользователя, их общее число
+// и число непрочитанных.
+func (uc *NotificationUsecase) List(ctx context.Context, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, int, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, 0, 0, apperror.Unauthorized("authentication required")
+	}
+
+	notifications, total, err := uc.notificationRepo.List(ctx, actor.UserID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("list notifications: %w", err)
+	}
+	unread := total
+	if !unreadOnly {
+		if unread, err = uc.notificationRepo.CountUnread(ctx, actor.UserID); err != nil {
+			return nil, 0, 0, fmt.Errorf("count unread notifications: %w", err)
+		}
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":   actor.UserID,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+		"unread":    unread,
+	}).Info("Notifications listed")
+
+	return notifications, total, unread, nil
+}
+
+func (uc *NotificationUsecase) MarkRead(ctx context.Context, id uuid.UUID) error {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return apperror.Unauthorized("authentication required")
+	}
+
+	if err := uc.notificationRepo.MarkRead(ctx, actor.UserID, id); err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"notification_id": id,
+		"user_id":         actor.UserID,
+	}).Info("Notification marked as read")
+
+	return nil
+}
+
+func (uc *NotificationUsecase) MarkAllRead(ctx context.Context) (int64, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return 0, apperror.Unauthorized("authentication required")
+	}
+
+	count, err := uc.notificationRepo.MarkAllRead(ctx, actor.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("mark all notifications read: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id": actor.UserID,
+		"count":   count,
+	}).Info("All notifications marked as read")
+
+	return count, nil
+}
+
+// GetPreferences возвращает настройки текущего пользователя по всем видам
+// уведомлений.
+func (uc *NotificationUsecase) GetPreferences(ctx context.Context) (entity.NotificationPreferences, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	preferences, err := uc.notificationRepo.GetPreferences(ctx, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	return preferences.WithDefaults(), nil
+}
+
+// UpdatePreferences меняет только переданные виды уведомлений. Уже созданные
+// уведомления остаются.
+func (uc *NotificationUsecase) UpdatePreferences(ctx context.Context, preferences entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	if err := uc.notificationRepo.SetPreferences(ctx, actor.UserID, preferences); err != nil {
+		return nil, fmt.Errorf("set notification preferences: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":     actor.UserID,
+		"preferences": preferences,
+	}).Info("Notification preferences updated")
+
+	return uc.GetPreferences(ctx)
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type NotificationUseCaseRepo interface {
+	List(ctx context.Context, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, int, error)
+	MarkRead(ctx context.Context, id uuid.UUID) error
+	MarkAllRead(ctx context.Context) (int64, error)
+	GetPreferences(ctx context.Context) (entity.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, preferences entity.NotificationPreferences) (entity.NotificationPreferences, error)
+}
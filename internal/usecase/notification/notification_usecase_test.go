@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int, error) {
+	args := m.Called(ctx, userID, unreadOnly, page, pageSize)
+	return args.Get(0).([]*entity.Notification), args.Int(1), args.Error(2)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (entity.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	preferences, _ := args.Get(0).(entity.NotificationPreferences)
+	return preferences, args.Error(1)
+}
+
+func (m *MockNotificationRepository) SetPreferences(ctx context.Context, userID uuid.UUID, preferences entity.NotificationPreferences) error {
+	args := m.Called(ctx, userID, preferences)
+	return args.Error(0)
+}
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, event *entity.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func TestNotificationUsecase_NotifyRespectsPreferences(t *testing.T) {
+	userID := uuid.New()
+	repo := new(MockNotificationRepository)
+	repo.On("GetPreferences", mock.Anything, userID).Return(entity.NotificationPreferences{entity.NotificationPostFavorited: false}, nil)
+	var created *entity.Notification
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Notification")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*entity.Notification) }).
+		Return(nil)
+
+	events := new(MockPublisher)
+	var published *entity.Event
+	events.On("Publish", mock.Anything, mock.AnythingOfType("*entity.Event")).
+		Run(func(args mock.Arguments) { published = args.Get(1).(*entity.Event) }).
+		Return(nil)
+
+	uc := NewNotificationUsecase(repo, events, logrus.New())
+	require.NoError(t, uc.Notify(context.Background(), userID, entity.NotificationPostFavorited, entity.NotificationData{"post_id": uuid.New()}))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	data := entity.NotificationData{"post_id": uuid.New(), "action": "deleted"}
+	require.NoError(t, uc.Notify(context.Background(), userID, entity.NotificationPostModerated, data))
+	require.NotNil(t, created)
+	assert.Equal(t, userID, created.UserID)
+	assert.Equal(t, entity.NotificationPostModerated, created.Kind)
+	assert.Equal(t, data, created.Data)
+	assert.Nil(t, created.ReadAt)
+
+	events.AssertNumberOfCalls(t, "Publish", 1)
+	require.NotNil(t, published)
+	assert.Equal(t, entity.EventNotification, published.Type)
+	require.NotNil(t, published.ObjectID)
+	assert.Equal(t, created.ID, *published.ObjectID)
+	assert.Equal(t, []uuid.UUID{userID}, published.UserIDs)
+}
+
+func TestNotificationUsecase_List(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	notifications := []*entity.Notification{{ID: uuid.New(), UserID: userID, Kind: entity.NotificationNewMessage}}
+
+	repo := new(MockNotificationRepository)
+	repo.On("List", mock.Anything, userID, false, 1, 20).Return(notifications, 7, nil)
+	repo.On("CountUnread", mock.Anything, userID).Return(3, nil)
+
+	uc := NewNotificationUsecase(repo, new(MockPublisher), logrus.New())
+	got, total, unread, err := uc.List(ctx, false, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, notifications, got)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, 3, unread)
+
+	_, _, _, err = uc.List(context.Background(), false, 1, 20)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
@@ -0,0 +1,229 @@
+package policy
+
+import (
+	"context"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// Actor — пользователь, от имени которого выполняется запрос.
+type Actor struct {
+	UserID uuid.UUID
+	Role   entity.Role
+}
+
+// ActorFromContext достаёт пользователя, которого AuthMiddleware положил в контекст.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	userID, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return Actor{}, false
+	}
+	role, ok := ctx.Value("user_role").(entity.Role)
+	if !ok {
+		role = entity.RoleUser
+	}
+	return Actor{UserID: userID, Role: role}, true
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == entity.RoleAdmin
+}
+
+func (a Actor) IsModerator() bool {
+	return a.Role == entity.RoleModerator || a.Role == entity.RoleAdmin
+}
+
+// CanEditPost: автор поста, модератор или администратор.
+func (a Actor) CanEditPost(post *entity.Post) bool {
+	return post.AuthorID == a.UserID || a.IsModerator()
+}
+
+// CanDeletePost: автор поста, модератор или администратор.
+func (a Actor) CanDeletePost(post *entity.Post) bool {
+	return post.AuthorID == a.UserID || a.IsModerator()
+}
+
+// CanViewPost: опубликованные, зарезервированные и проданные посты видны
+// всем, черновики и архив — только автору и модераторам.
+func (a Actor) CanViewPost(post *entity.Post) bool {
+	return post.Status.IsPublic() || a.CanViewAllPostsOf(post.AuthorID)
+}
+
+// CanViewAllPostsOf: посты в любом статусе видят автор и модераторы.
+func (a Actor) CanViewAllPostsOf(authorID uuid.UUID) bool {
+	return a.UserID == authorID || a.IsModerator()
+}
+
+// CanManageUser: сам пользователь или администратор.
+func (a Actor) CanManageUser(userID uuid.UUID) bool {
+	return a.UserID == userID || a.IsAdmin()
+}
+
+func (a Actor) CanChangeRoles() bool {
+	return a.IsAdmin()
+}
+
+func (a Actor) CanManageCategories() bool {
+	return a.IsAdmin()
+}
+
+func (a Actor) CanManageExchangeRates() bool {
+	return a.IsAdmin()
+}
+
+// CanFavoritePost: любой, кому виден пост, кроме его автора.
+func (a Actor) CanFavoritePost(post *entity.Post) bool {
+	return post.AuthorID != a.UserID && a.CanViewPost(post)
+}
+
+// CanManageSavedSearch: только владелец. Сохранённые поиски не видны даже
+// администраторам.
+func (a Actor) CanManageSavedSearch(search *entity.SavedSearch) bool {
+	return search.UserID == a.UserID
+}
+
+// CanStartConversation: любой, кроме автора, по опубликованному или
+// зарезервированному посту.
+func (a Actor) CanStartConversation(post *entity.Post) bool {
+	return post.AuthorID != a.UserID && (post.Status == entity.PostPublished || post.Status == entity.PostReserved)
+}
+
+// CanAccessConversation: только участники переписки. Чужие переписки не
+// видны даже администраторам.
+func (a Actor) CanAccessConversation(conversation *entity.Conversation) bool {
+	return conversation.HasParticipant(a.UserID)
+}
+
+func AuthorizeEditPost(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanEditPost(post) {
+		return actor, apperror.Forbidden("not allowed to edit the post")
+	}
+	return actor, nil
+}
+
+func AuthorizeDeletePost(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanDeletePost(post) {
+		return actor, apperror.Forbidden("not allowed to delete the post")
+	}
+	return actor, nil
+}
+
+func AuthorizeRestorePost(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanDeletePost(post) {
+		return actor, apperror.Forbidden("not allowed to restore the post")
+	}
+	return actor, nil
+}
+
+func AuthorizeChangePostStatus(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanEditPost(post) {
+		return actor, apperror.Forbidden("not allowed to change the post status")
+	}
+	return actor, nil
+}
+
+func AuthorizeManageUser(ctx context.Context, userID uuid.UUID) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanManageUser(userID) {
+		return actor, apperror.Forbidden("not allowed to manage the user")
+	}
+	return actor, nil
+}
+
+func AuthorizeChangeRoles(ctx context.Context) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanChangeRoles() {
+		return actor, apperror.Forbidden("only admins can change roles")
+	}
+	return actor, nil
+}
+
+func AuthorizeManageCategories(ctx context.Context) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanManageCategories() {
+		return actor, apperror.Forbidden("only admins can manage categories")
+	}
+	return actor, nil
+}
+
+func AuthorizeManageExchangeRates(ctx context.Context) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanManageExchangeRates() {
+		return actor, apperror.Forbidden("only admins can manage exchange rates")
+	}
+	return actor, nil
+}
+
+func AuthorizeFavoritePost(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanFavoritePost(post) {
+		return actor, apperror.Forbidden("you can't add this post to favorites")
+	}
+	return actor, nil
+}
+
+func AuthorizeManageSavedSearch(ctx context.Context, search *entity.SavedSearch) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanManageSavedSearch(search) {
+		return actor, apperror.Forbidden("you can't access this saved search")
+	}
+	return actor, nil
+}
+
+func AuthorizeStartConversation(ctx context.Context, post *entity.Post) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanStartConversation(post) {
+		return actor, apperror.Forbidden("you can't start a conversation about this post")
+	}
+	return actor, nil
+}
+
+func AuthorizeAccessConversation(ctx context.Context, conversation *entity.Conversation) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, apperror.Unauthorized("authentication required")
+	}
+	if !actor.CanAccessConversation(conversation) {
+		return actor, apperror.Forbidden("you can't access this conversation")
+	}
+	return actor, nil
+}
@@ -0,0 +1,138 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func actorContext(userID uuid.UUID, role entity.Role) context.Context {
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	return context.WithValue(ctx, "user_role", role)
+}
+
+func TestAuthorizeEditPost(t *testing.T) {
+	authorID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), AuthorID: authorID}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"author", actorContext(authorID, entity.RoleUser), nil},
+		{"moderator", actorContext(uuid.New(), entity.RoleModerator), nil},
+		{"admin", actorContext(uuid.New(), entity.RoleAdmin), nil},
+		{"other user", actorContext(uuid.New(), entity.RoleUser), apperror.ErrForbidden},
+		{"anonymous", context.Background(), apperror.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AuthorizeEditPost(tt.ctx, post)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+			}
+		})
+	}
+}
+
+func TestCanViewPost(t *testing.T) {
+	authorID := uuid.New()
+	draft := &entity.Post{AuthorID: authorID, Status: entity.PostDraft}
+	sold := &entity.Post{AuthorID: authorID, Status: entity.PostSold}
+
+	assert.True(t, Actor{UserID: authorID, Role: entity.RoleUser}.CanViewPost(draft))
+	assert.True(t, Actor{UserID: uuid.New(), Role: entity.RoleModerator}.CanViewPost(draft))
+	assert.False(t, Actor{UserID: uuid.New(), Role: entity.RoleUser}.CanViewPost(draft))
+	assert.False(t, Actor{}.CanViewPost(draft))
+	assert.True(t, Actor{}.CanViewPost(sold))
+}
+
+func TestAuthorizeManageUser(t *testing.T) {
+	userID := uuid.New()
+
+	_, err := AuthorizeManageUser(actorContext(userID, entity.RoleUser), userID)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeManageUser(actorContext(uuid.New(), entity.RoleAdmin), userID)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeManageUser(actorContext(uuid.New(), entity.RoleModerator), userID)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+}
+
+func TestAuthorizeManageCategories(t *testing.T) {
+	_, err := AuthorizeManageCategories(actorContext(uuid.New(), entity.RoleAdmin))
+	assert.NoError(t, err)
+
+	_, err = AuthorizeManageCategories(actorContext(uuid.New(), entity.RoleModerator))
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+
+	_, err = AuthorizeManageCategories(context.Background())
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
+
+func TestAuthorizeFavoritePost(t *testing.T) {
+	authorID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), AuthorID: authorID, Status: entity.PostPublished}
+
+	_, err := AuthorizeFavoritePost(actorContext(uuid.New(), entity.RoleUser), post)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeFavoritePost(actorContext(authorID, entity.RoleUser), post)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+
+	_, err = AuthorizeFavoritePost(context.Background(), post)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
+
+func TestAuthorizeManageSavedSearch(t *testing.T) {
+	ownerID := uuid.New()
+	search := &entity.SavedSearch{ID: uuid.New(), UserID: ownerID}
+
+	_, err := AuthorizeManageSavedSearch(actorContext(ownerID, entity.RoleUser), search)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeManageSavedSearch(actorContext(uuid.New(), entity.RoleAdmin), search)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+
+	_, err = AuthorizeManageSavedSearch(context.Background(), search)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
+
+func TestAuthorizeStartConversation(t *testing.T) {
+	authorID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), AuthorID: authorID, Status: entity.PostReserved}
+
+	_, err := AuthorizeStartConversation(actorContext(uuid.New(), entity.RoleUser), post)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeStartConversation(actorContext(authorID, entity.RoleUser), post)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+
+	post.Status = entity.PostSold
+	_, err = AuthorizeStartConversation(actorContext(uuid.New(), entity.RoleUser), post)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+}
+
+func TestAuthorizeAccessConversation(t *testing.T) {
+	conversation := &entity.Conversation{ID: uuid.New(), BuyerID: uuid.New(), SellerID: uuid.New()}
+
+	_, err := AuthorizeAccessConversation(actorContext(conversation.SellerID, entity.RoleUser), conversation)
+	assert.NoError(t, err)
+
+	_, err = AuthorizeAccessConversation(actorContext(uuid.New(), entity.RoleAdmin), conversation)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+
+	_, err = AuthorizeAccessConversation(context.Background(), conversation)
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
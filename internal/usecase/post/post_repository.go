@@ -8,11 +8,22 @@ import (
 )
 
 type PostRepository interface {
-	Create(ctx context.Context, post *entity.Post) error
+	Create(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
 	ListByAuthorID(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	GetByHeaderAndContent(ctx context.Context, header, content string) (*entity.Post, error)
-	Update(ctx context.Context, post *entity.Post) error
+	Update(ctx context.Context, post *entity.Post, revision *entity.PostRevision) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.PostStatus) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID uuid.UUID, number int) (*entity.PostRevision, error)
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	AddFavorite(ctx context.Context, userID, postID uuid.UUID) (int, bool, error)
+	RemoveFavorite(ctx context.Context, userID, postID uuid.UUID) (int, error)
+	FavoritePostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	ListFavorites(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error)
+	CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error)
+	CategoryFacetsByAuthorID(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error)
 }
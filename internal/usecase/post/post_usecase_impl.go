@@ -2,12 +2,21 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
 	usecaseAuth "marketplace/internal/usecase/auth"
+	usecaseCategory "marketplace/internal/usecase/category"
+	usecaseExchangeRate "marketplace/internal/usecase/exchangerate"
+	usecaseImage "marketplace/internal/usecase/image"
+	usecaseNotification "marketplace/internal/usecase/notification"
+	"marketplace/internal/usecase/policy"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
+	usecaseSavedSearch "marketplace/internal/usecase/savedsearch"
 	usecase "marketplace/internal/usecase/user"
+	"reflect"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,158 +24,385 @@ import (
 )
 
 type PostUsecase struct {
-	postRepo PostRepository
-	userRepo usecase.UserRepository
-	authRepo usecaseAuth.AuthService
+	postRepo     PostRepository
+	userRepo     usecase.UserRepository
+	categoryRepo usecaseCategory.CategoryRepository
+	imageRepo    usecaseImage.ImageRepository
+	variants     usecaseImage.VariantNotifier
+	matches      usecaseSavedSearch.MatchNotifier
+	notifier     usecaseNotification.Notifier
+	events       usecaseRealtime.Publisher
+	feed         usecaseRealtime.PostStreamer
+	rateRepo     usecaseExchangeRate.ExchangeRateRepository
+	authRepo     usecaseAuth.AuthService
+	// currency — валюта фильтров и сортировки по цене, если покупатель не
+	// выбрал свою.
+	currency entity.Currency
 	logger   *logrus.Logger
 }
 
-func NewPostUsecase(postRepo PostRepository, userRepo usecase.UserRepository, authRepo usecaseAuth.AuthService, logger *logrus.Logger) *PostUsecase {
+func NewPostUsecase(postRepo PostRepository, userRepo usecase.UserRepository, categoryRepo usecaseCategory.CategoryRepository, imageRepo usecaseImage.ImageRepository, variants usecaseImage.VariantNotifier, matches usecaseSavedSearch.MatchNotifier, notifier usecaseNotification.Notifier, events usecaseRealtime.Publisher, feed usecaseRealtime.PostStreamer, rateRepo usecaseExchangeRate.ExchangeRateRepository, authRepo usecaseAuth.AuthService, currency entity.Currency, logger *logrus.Logger) *PostUsecase {
 	return &PostUsecase{
-		postRepo: postRepo,
-		userRepo: userRepo,
-		authRepo: authRepo,
-		logger:   logger,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		imageRepo:    imageRepo,
+		variants:     variants,
+		matches:      matches,
+		notifier:     notifier,
+		events:       events,
+		feed:         feed,
+		rateRepo:     rateRepo,
+		authRepo:     authRepo,
+		currency:     currency,
+		logger:       logger,
 	}
 }
 
-func (uc *PostUsecase) Publish(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
+func (uc *PostUsecase) Publish(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error) {
 	_, err := uc.userRepo.GetByID(ctx, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("get user: %w", err)
 	}
 
 	post := &entity.Post{
-		ID:        uuid.New(),
-		Header:    header,
-		Content:   content,
-		Image:     image,
-		Price:     price,
-		AuthorID:  authorID,
-		CreatedAt: time.Now(),
+		ID:         uuid.New(),
+		Header:     params.Header,
+		Content:    params.Content,
+		CategoryID: params.CategoryID,
+		Tags:       entity.NormalizeTags(params.Tags),
+		Attributes: params.Attributes,
+		Lat:        params.Lat,
+		Lng:        params.Lng,
+		City:       entity.NormalizeCity(params.City),
+		AuthorID:   authorID,
+		CreatedAt:  time.Now(),
+		Status:     entity.PostPublished,
+		Version:    1,
 	}
+	if params.Draft {
+		post.Status = entity.PostDraft
+	}
+	if params.Price != nil {
+		post.Price = *params.Price
+	}
+
+	gallery, galleryErr := entity.NewGallery(params.ImageIDs, params.CoverImageID)
+	post.SetImages(gallery)
 
-	if err := post.Validate(); err != nil {
+	if err := apperror.Merge(galleryErr, post.Validate(), uc.checkCategory(ctx, post), uc.checkImages(ctx, authorID, params.ImageIDs)); err != nil {
 		return nil, fmt.Errorf("validate post: %w", err)
 	}
 
-	exist, err := uc.postRepo.GetByHeaderAndContent(ctx, header, content)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	exist, err := uc.postRepo.GetByHeaderAndContent(ctx, post.Header, post.Content)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
 		return nil, fmt.Errorf("check duplicate: %w", err)
 	}
 	if exist != nil {
-		return nil, fmt.Errorf("post with the same header and content already exists")
+		return nil, apperror.Conflict("post with the same header and content already exists")
 	}
 
-	if err := uc.postRepo.Create(ctx, post); err != nil {
+	if err := uc.postRepo.Create(ctx, post, entity.NewPostRevision(post, authorID)); err != nil {
 		return nil, fmt.Errorf("create post: %w", err)
 	}
+	if len(params.ImageIDs) > 0 {
+		uc.variants.Notify()
+	}
+	if post.Status == entity.PostPublished {
+		uc.matches.Notify()
+		uc.publishNew(ctx, post)
+	}
 
 	uc.logger.WithFields(logrus.Fields{
-		"header":    header,
-		"price":     price,
+		"header":    post.Header,
+		"price":     post.Price.String(),
 		"post_id":   post.ID,
 		"author_id": authorID,
+		"status":    post.Status,
 	}).Info("Post created")
 
 	return post, nil
 }
 
-func (uc *PostUsecase) Edit(ctx context.Context, postID uuid.UUID, header, content, image string, price float64) (*entity.Post, error) {
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	if !ok {
-		return nil, errors.New("unauthorized")
-	}
-
+// Edit применяет правку, только если пост всё ещё в версии version, которую
+// видел клиент. Иначе возвращается ErrPreconditionFailed.
+func (uc *PostUsecase) Edit(ctx context.Context, postID uuid.UUID, version int, params entity.PostParams) (*entity.Post, error) {
 	post, err := uc.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, fmt.Errorf("get post by id: %w", err)
 	}
 
-	if post.AuthorID != userID {
-		return nil, errors.New("forbidden: not the author of the post")
+	actor, err := policy.AuthorizeEditPost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Version != version {
+		return nil, apperror.PreconditionFailed("post has been modified: current version is %d", post.Version)
 	}
 
-	if header != "" {
-		post.Header = header
+	if params.Header != "" {
+		post.Header = params.Header
+	}
+	if params.Content != "" {
+		post.Content = params.Content
+	}
+	var galleryErr error
+	if params.ImageIDs != nil || params.CoverImageID != nil {
+		imageIDs := params.ImageIDs
+		if imageIDs == nil {
+			imageIDs = post.ImageIDs()
+		}
+		var gallery []entity.PostImage
+		gallery, galleryErr = entity.NewGallery(imageIDs, params.CoverImageID)
+		if galleryErr == nil {
+			post.SetImages(gallery)
+		}
+	}
+	if params.Price != nil {
+		post.Price = *params.Price
 	}
-	if content != "" {
-		post.Content = content
+	if params.CategoryID != nil {
+		post.CategoryID = params.CategoryID
 	}
-	if image != "" {
-		post.Image = image
+	if params.Tags != nil {
+		post.Tags = entity.NormalizeTags(params.Tags)
 	}
-	if price > 0 {
-		post.Price = price
+	if params.Attributes != nil {
+		post.Attributes = params.Attributes
+	}
+	if params.Lat != nil {
+		post.Lat = params.Lat
+	}
+	if params.Lng != nil {
+		post.Lng = params.Lng
+	}
+	if city := entity.NormalizeCity(params.City); city != "" {
+		post.City = city
 	}
 
-	if header != "" && content != "" {
-		existingPost, err := uc.postRepo.GetByHeaderAndContent(ctx, header, content)
-		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	if params.Header != "" && params.Content != "" {
+		existingPost, err := uc.postRepo.GetByHeaderAndContent(ctx, params.Header, params.Content)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
 			return nil, fmt.Errorf("check duplicate: %w", err)
 		}
 		if existingPost != nil && existingPost.ID != postID {
-			return nil, fmt.Errorf("post with the same header and content already exists")
+			return nil, apperror.Conflict("post with the same header and content already exists")
 		}
 	}
 
-	if err := post.Validate(); err != nil {
-		return nil, fmt.Errorf("validate post: %w", err)
+	if err := uc.save(ctx, post, actor, galleryErr, params.ImageIDs); err != nil {
+		return nil, err
 	}
+	return post, nil
+}
 
-	if err := uc.postRepo.Update(ctx, post); err != nil {
-		return nil, fmt.Errorf("update post: %w", err)
+// Patch применяет к посту JSON Merge Patch (RFC 7396). В отличие от Edit,
+// отсутствующее поле и явное значение различаются: null очищает категорию,
+// теги или обложку, а цена может стать нулевой.
+func (uc *PostUsecase) Patch(ctx context.Context, postID uuid.UUID, version int, patch []byte) (*entity.Post, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("get post by id: %w", err)
 	}
 
+	actor, err := policy.AuthorizeEditPost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Version != version {
+		return nil, apperror.PreconditionFailed("post has been modified: current version is %d", post.Version)
+	}
+
+	current := post.PatchDocument()
+	var patched entity.PostPatch
+	if err := entity.ApplyMergePatch(current, &patched, patch); err != nil {
+		return nil, fmt.Errorf("apply merge patch: %w", err)
+	}
+
+	if patched.Header != current.Header || patched.Content != current.Content {
+		existingPost, err := uc.postRepo.GetByHeaderAndContent(ctx, patched.Header, patched.Content)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return nil, fmt.Errorf("check duplicate: %w", err)
+		}
+		if existingPost != nil && existingPost.ID != postID {
+			return nil, apperror.Conflict("post with the same header and content already exists")
+		}
+	}
+
+	post.Header = patched.Header
+	post.Content = patched.Content
+	post.Price = patched.Price
+	post.CategoryID = patched.CategoryID
+	post.Tags = entity.NormalizeTags(patched.Tags)
+	post.Attributes = patched.Attributes
+	post.Lat = patched.Lat
+	post.Lng = patched.Lng
+	post.City = entity.NormalizeCity(patched.City)
+
+	// Галерею пересобираем, только если патч её затронул: у старых постов
+	// без галереи иначе пропала бы ссылка в Image.
+	var galleryErr error
+	var imageIDs []uuid.UUID
+	if !reflect.DeepEqual(patched.ImageIDs, current.ImageIDs) || !reflect.DeepEqual(patched.CoverImageID, current.CoverImageID) {
+		imageIDs = patched.ImageIDs
+		coverID := patched.CoverImageID
+		// Прежняя обложка, убранная из списка, не должна мешать замене галереи.
+		if reflect.DeepEqual(coverID, current.CoverImageID) && coverID != nil && !slices.Contains(imageIDs, *coverID) {
+			coverID = nil
+		}
+		var gallery []entity.PostImage
+		gallery, galleryErr = entity.NewGallery(imageIDs, coverID)
+		if galleryErr == nil {
+			post.SetImages(gallery)
+		}
+	}
+
+	if err := uc.save(ctx, post, actor, galleryErr, imageIDs); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// save проверяет изменённый пост и сохраняет его новой ревизией. imageIDs —
+// только изображения, которые правка поменяла: проверять их нужно заново.
+// Характеристики проверяются всегда: схема категории могла измениться.
+func (uc *PostUsecase) save(ctx context.Context, post *entity.Post, actor policy.Actor, galleryErr error, imageIDs []uuid.UUID) error {
+	if err := apperror.Merge(galleryErr, post.Validate(), uc.checkCategory(ctx, post), uc.checkImages(ctx, post.AuthorID, imageIDs)); err != nil {
+		return fmt.Errorf("validate post: %w", err)
+	}
+
+	revision := entity.NewPostRevision(post, actor.UserID)
+	if err := uc.postRepo.Update(ctx, post, revision); err != nil {
+		return fmt.Errorf("update post: %w", err)
+	}
+	if len(imageIDs) > 0 {
+		uc.variants.Notify()
+	}
+	uc.notifyModerated(ctx, post, actor, "edited")
+
 	uc.logger.WithFields(logrus.Fields{
-		"post_id":   postID,
-		"author_id": userID,
+		"post_id":   post.ID,
+		"author_id": post.AuthorID,
+		"editor_id": actor.UserID,
+		"revision":  revision.Revision,
 	}).Info("Post updated")
 
-	return post, nil
+	return nil
 }
 
-func (uc *PostUsecase) Delete(ctx context.Context, postID uuid.UUID) error {
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	if !ok {
-		return errors.New("unauthorized")
+// ChangeStatus переводит пост в новый статус по правилам entity.PostStatus.
+func (uc *PostUsecase) ChangeStatus(ctx context.Context, postID uuid.UUID, status entity.PostStatus) (*entity.Post, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("get post by id: %w", err)
+	}
+
+	actor, err := policy.AuthorizeChangePostStatus(ctx, post)
+	if err != nil {
+		return nil, err
 	}
 
+	if post.Status == status {
+		return nil, apperror.Conflict("post is already %s", status)
+	}
+	if !post.Status.CanTransitionTo(status) {
+		return nil, apperror.Conflict("can't change post status from %s to %s", post.Status, status)
+	}
+
+	if err := uc.postRepo.UpdateStatus(ctx, postID, post.Status, status); err != nil {
+		return nil, fmt.Errorf("update post status: %w", err)
+	}
+	// Воркер будит любая публикация: черновик мог попасть в архив, не
+	// побывав опубликованным, и тогда ещё не сверен с сохранёнными поисками.
+	// Уже сверенные посты воркер пропускает по searches_matched_at.
+	if status == entity.PostPublished {
+		uc.matches.Notify()
+	}
+	if post.Status == entity.PostDraft && status == entity.PostPublished {
+		uc.publishNew(ctx, post)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id":  postID,
+		"from":     post.Status,
+		"to":       status,
+		"actor_id": actor.UserID,
+	}).Info("Post status changed")
+
+	post.Status = status
+	post.Version++
+	post.IsOwnPost = post.AuthorID == actor.UserID
+	uc.notifyModerated(ctx, post, actor, "status_changed")
+	uc.publishStatus(ctx, post)
+	return post, nil
+}
+
+func (uc *PostUsecase) Delete(ctx context.Context, postID uuid.UUID) error {
 	post, err := uc.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return fmt.Errorf("get post by id: %w", err)
 	}
 
-	if post.AuthorID != userID {
-		return errors.New("forbidden: not the author of the post")
+	actor, err := policy.AuthorizeDeletePost(ctx, post)
+	if err != nil {
+		return err
 	}
 
 	if err := uc.postRepo.Delete(ctx, postID); err != nil {
 		return fmt.Errorf("delete post: %w", err)
 	}
+	uc.notifyModerated(ctx, post, actor, "deleted")
 
 	uc.logger.WithFields(logrus.Fields{
-		"post_id": postID,
+		"post_id":   postID,
+		"author_id": post.AuthorID,
+		"actor_id":  actor.UserID,
 	}).Info("Post deleted")
 
 	return nil
 }
 
-func (uc *PostUsecase) GetPost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
-	post, err := uc.postRepo.GetByID(ctx, postID)
+// Restore возвращает удалённый пост в том статусе, в котором его удалили.
+func (uc *PostUsecase) Restore(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := uc.postRepo.GetDeletedByID(ctx, postID)
 	if err != nil {
-		return nil, fmt.Errorf("get post by id: %w", err)
+		return nil, fmt.Errorf("get deleted post: %w", err)
 	}
 
-	user, err := uc.userRepo.GetByID(ctx, post.AuthorID)
+	actor, err := policy.AuthorizeRestorePost(ctx, post)
 	if err != nil {
-		return nil, fmt.Errorf("get user: %w", err)
+		return nil, err
 	}
-	post.AuthorUsername = user.Username
 
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	if ok {
-		post.IsOwnPost = post.AuthorID == userID
+	if err := uc.postRepo.Restore(ctx, postID); err != nil {
+		return nil, fmt.Errorf("restore post: %w", err)
+	}
+	uc.notifyModerated(ctx, post, actor, "restored")
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id":   postID,
+		"author_id": post.AuthorID,
+		"actor_id":  actor.UserID,
+	}).Info("Post restored")
+
+	post.IsOwnPost = post.AuthorID == actor.UserID
+	return post, nil
+}
+
+// GetPost возвращает пост; если задана currency, в DisplayPrice — цена в ней.
+func (uc *PostUsecase) GetPost(ctx context.Context, postID uuid.UUID, currency entity.Currency) (*entity.Post, error) {
+	post, err := uc.visiblePost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.markViewer(ctx, post); err != nil {
+		return nil, err
+	}
+	if err := uc.displayPrices(ctx, currency, post); err != nil {
+		return nil, err
 	}
 
 	uc.logger.WithFields(logrus.Fields{
@@ -176,31 +412,78 @@ func (uc *PostUsecase) GetPost(ctx context.Context, postID uuid.UUID) (*entity.P
 	return post, nil
 }
 
+// ListRevisions возвращает историю правок поста, начиная с последней.
+func (uc *PostUsecase) ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error) {
+	if _, err := uc.visiblePost(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := uc.postRepo.ListRevisions(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("get post revisions: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id":   postID,
+		"revisions": len(revisions),
+	}).Info("Post revisions listed")
+
+	return revisions, nil
+}
+
+// DiffRevisions сравнивает две ревизии поста по полям.
+func (uc *PostUsecase) DiffRevisions(ctx context.Context, postID uuid.UUID, from, to int) (*entity.RevisionDiff, error) {
+	if _, err := uc.visiblePost(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := uc.postRepo.GetRevision(ctx, postID, from)
+	if err != nil {
+		return nil, fmt.Errorf("get revision %d: %w", from, err)
+	}
+	toRevision, err := uc.postRepo.GetRevision(ctx, postID, to)
+	if err != nil {
+		return nil, fmt.Errorf("get revision %d: %w", to, err)
+	}
+
+	diff := entity.DiffRevisions(fromRevision, toRevision)
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+		"from":    from,
+		"to":      to,
+		"changes": len(diff.Changes),
+	}).Info("Post revisions compared")
+
+	return diff, nil
+}
+
 func (uc *PostUsecase) ListPostsByAuthor(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	_, err := uc.userRepo.GetByID(ctx, authorID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("user not found: %w", err)
+		return nil, 0, fmt.Errorf("get user: %w", err)
 	}
 
 	if sortBy == "" {
 		sortBy = "created_at DESC"
+		if filter["q"] != "" {
+			sortBy = "rank DESC"
+		}
 	}
 
+	display := entity.Currency(filter["currency"])
+	filter = uc.priceFilter(uc.authorFilter(ctx, authorID, filter))
 	posts, total, err := uc.postRepo.ListByAuthorID(ctx, authorID, page, pageSize, sortBy, filter)
 	if err != nil {
 		return nil, 0, fmt.Errorf("get posts: %w", err)
 	}
 
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	for _, post := range posts {
-		user, err := uc.userRepo.GetByID(ctx, post.AuthorID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("get user: %w", err)
-		}
-		post.AuthorUsername = user.Username
-		if ok {
-			post.IsOwnPost = post.AuthorID == userID
-		}
+	// Имя автора и галерея приходят из репозитория вместе с постами.
+	if err := uc.markViewer(ctx, posts...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.displayPrices(ctx, display, posts...); err != nil {
+		return nil, 0, err
 	}
 
 	uc.logger.WithFields(logrus.Fields{
@@ -218,23 +501,24 @@ func (uc *PostUsecase) ListPostsByAuthor(ctx context.Context, authorID uuid.UUID
 func (uc *PostUsecase) ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error) {
 	if sortBy == "" {
 		sortBy = "created_at DESC"
+		if filter["q"] != "" {
+			sortBy = "rank DESC"
+		}
 	}
 
+	display := entity.Currency(filter["currency"])
+	filter = uc.priceFilter(publishedOnly(filter))
 	posts, total, err := uc.postRepo.ListPosts(ctx, page, pageSize, sortBy, filter)
 	if err != nil {
 		return nil, 0, fmt.Errorf("get posts: %w", err)
 	}
 
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	for _, post := range posts {
-		user, err := uc.userRepo.GetByID(ctx, post.AuthorID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("get user: %w", err)
-		}
-		post.AuthorUsername = user.Username
-		if ok {
-			post.IsOwnPost = post.AuthorID == userID
-		}
+	// Имя автора и галерея приходят из репозитория вместе с постами.
+	if err := uc.markViewer(ctx, posts...); err != nil {
+		return nil, 0, err
+	}
+	if err := uc.displayPrices(ctx, display, posts...); err != nil {
+		return nil, 0, err
 	}
 
 	uc.logger.WithFields(logrus.Fields{
@@ -247,3 +531,306 @@ func (uc *PostUsecase) ListPosts(ctx context.Context, page, pageSize int, sortBy
 
 	return posts, total, nil
 }
+
+// StreamPosts подписывает на новые посты, подходящие под фильтры списка
+// постов. Фильтр по статусу не учитывается: в ленту попадают только
+// опубликованные посты. Цена в валюте currency и расстояние до точки фильтра
+// заполняются так же, как в списке.
+func (uc *PostUsecase) StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error) {
+	display := entity.Currency(filter["currency"])
+	stream, err := uc.feed.Subscribe(ctx, uc.priceFilter(publishedOnly(filter)), display, lastEventID)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to post feed: %w", err)
+	}
+	return stream, nil
+}
+
+// Favorite добавляет пост в избранное текущего пользователя. Повторное
+// добавление не считается ошибкой.
+func (uc *PostUsecase) Favorite(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := uc.visiblePost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := policy.AuthorizeFavoritePost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+
+	count, added, err := uc.postRepo.AddFavorite(ctx, actor.UserID, postID)
+	if err != nil {
+		return nil, fmt.Errorf("add favorite: %w", err)
+	}
+	post.FavoritesCount = count
+	post.IsFavorited = true
+	if added && actor.UserID != post.AuthorID {
+		uc.notify(ctx, post.AuthorID, entity.NotificationPostFavorited, entity.NotificationData{
+			"post_id":         post.ID,
+			"header":          post.Header,
+			"favorites_count": count,
+		})
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+		"user_id": actor.UserID,
+	}).Info("Post added to favorites")
+
+	return post, nil
+}
+
+// Unfavorite убирает пост из избранного текущего пользователя. Пост можно
+// убрать, даже если он уже скрыт автором.
+func (uc *PostUsecase) Unfavorite(ctx context.Context, postID uuid.UUID) error {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return apperror.Unauthorized("authentication required")
+	}
+
+	if _, err := uc.postRepo.RemoveFavorite(ctx, actor.UserID, postID); err != nil {
+		return fmt.Errorf("remove favorite: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"post_id": postID,
+		"user_id": actor.UserID,
+	}).Info("Post removed from favorites")
+
+	return nil
+}
+
+// ListFavorites возвращает избранное текущего пользователя, начиная с
+// последних добавленных постов.
+func (uc *PostUsecase) ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, 0, apperror.Unauthorized("authentication required")
+	}
+
+	posts, total, err := uc.postRepo.ListFavorites(ctx, actor.UserID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list favorites: %w", err)
+	}
+	for _, post := range posts {
+		post.IsFavorited = true
+	}
+	if err := uc.displayPrices(ctx, currency, posts...); err != nil {
+		return nil, 0, err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":     actor.UserID,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_posts": total,
+	}).Info("Favorites listed")
+
+	return posts, total, nil
+}
+
+func (uc *PostUsecase) CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	facets, err := uc.postRepo.CategoryFacets(ctx, uc.priceFilter(publishedOnly(filter)))
+	if err != nil {
+		return nil, fmt.Errorf("get category facets: %w", err)
+	}
+	return facets, nil
+}
+
+func (uc *PostUsecase) CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error) {
+	facets, err := uc.postRepo.CategoryFacetsByAuthorID(ctx, authorID, uc.priceFilter(uc.authorFilter(ctx, authorID, filter)))
+	if err != nil {
+		return nil, fmt.Errorf("get category facets: %w", err)
+	}
+	return facets, nil
+}
+
+// visiblePost загружает пост, если текущий пользователь может его видеть.
+// Чужие черновики и архив не отличаются от несуществующих постов.
+func (uc *PostUsecase) visiblePost(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("get post by id: %w", err)
+	}
+
+	actor, _ := policy.ActorFromContext(ctx)
+	if !actor.CanViewPost(post) {
+		return nil, apperror.NotFound("post not found")
+	}
+	return post, nil
+}
+
+// markViewer отмечает посты, которые текущий пользователь написал сам или
+// добавил в избранное. Для анонимного пользователя флаги остаются false.
+func (uc *PostUsecase) markViewer(ctx context.Context, posts ...*entity.Post) error {
+	userID, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok || len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		post.IsOwnPost = post.AuthorID == userID
+		postIDs = append(postIDs, post.ID)
+	}
+
+	favorites, err := uc.postRepo.FavoritePostIDs(ctx, userID, postIDs)
+	if err != nil {
+		return fmt.Errorf("get favorites: %w", err)
+	}
+	for _, post := range posts {
+		post.IsFavorited = favorites[post.ID]
+	}
+	return nil
+}
+
+// notify создаёт уведомление, не прерывая сценарий: изменение уже сохранено,
+// и ошибка уведомления только пишется в лог.
+func (uc *PostUsecase) notify(ctx context.Context, userID uuid.UUID, kind entity.NotificationKind, data entity.NotificationData) {
+	if err := uc.notifier.Notify(ctx, userID, kind, data); err != nil {
+		uc.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id": userID,
+			"kind":    kind,
+		}).Error("Failed to create notification")
+	}
+}
+
+// notifyModerated сообщает автору, что его пост изменил, удалил или
+// восстановил модератор. О собственных действиях автора не сообщается.
+func (uc *PostUsecase) notifyModerated(ctx context.Context, post *entity.Post, actor policy.Actor, action string) {
+	if actor.UserID == post.AuthorID {
+		return
+	}
+	uc.notify(ctx, post.AuthorID, entity.NotificationPostModerated, entity.NotificationData{
+		"post_id":      post.ID,
+		"header":       post.Header,
+		"action":       action,
+		"status":       post.Status,
+		"moderator_id": actor.UserID,
+	})
+}
+
+// publishStatus сообщает о смене статуса поста подключённым клиентам, у
+// которых пост в избранном.
+func (uc *PostUsecase) publishStatus(ctx context.Context, post *entity.Post) {
+	event, err := entity.NewPostEvent(entity.EventPostStatus, post.ID, nil)
+	if err == nil {
+		err = uc.events.Publish(ctx, event)
+	}
+	if err != nil {
+		uc.logger.WithError(err).WithField("post_id", post.ID).Error("Failed to publish post status event")
+	}
+}
+
+// publishNew сообщает всем экземплярам сервера о новом посте для ленты
+// GET /posts/stream.
+func (uc *PostUsecase) publishNew(ctx context.Context, post *entity.Post) {
+	event, err := entity.NewPostEvent(entity.EventPostPublished, post.ID, entity.PostPublishedData{PublishedAt: time.Now()})
+	if err == nil {
+		err = uc.events.Publish(ctx, event)
+	}
+	if err != nil {
+		uc.logger.WithError(err).WithField("post_id", post.ID).Error("Failed to publish new post event")
+	}
+}
+
+// publishedOnly возвращает копию фильтра, ограниченную опубликованными постами.
+func publishedOnly(filter map[string]string) map[string]string {
+	restricted := make(map[string]string, len(filter)+1)
+	for key, value := range filter {
+		restricted[key] = value
+	}
+	restricted["status"] = string(entity.PostPublished)
+	return restricted
+}
+
+// authorFilter оставляет фильтр по статусу автору и модераторам, остальным
+// показывает только опубликованные посты.
+func (uc *PostUsecase) authorFilter(ctx context.Context, authorID uuid.UUID, filter map[string]string) map[string]string {
+	if actor, ok := policy.ActorFromContext(ctx); ok && actor.CanViewAllPostsOf(authorID) {
+		return filter
+	}
+	return publishedOnly(filter)
+}
+
+// priceFilter возвращает копию фильтра с валютой, в которой сравниваются цены:
+// выбранной покупателем или валютой по умолчанию.
+func (uc *PostUsecase) priceFilter(filter map[string]string) map[string]string {
+	if filter["currency"] != "" {
+		return filter
+	}
+	withCurrency := make(map[string]string, len(filter)+1)
+	for key, value := range filter {
+		withCurrency[key] = value
+	}
+	withCurrency["currency"] = string(uc.currency)
+	return withCurrency
+}
+
+// displayPrices заполняет DisplayPrice постов ценой в валюте currency. У постов,
+// для валюты которых нет курса, DisplayPrice остаётся пустым.
+func (uc *PostUsecase) displayPrices(ctx context.Context, currency entity.Currency, posts ...*entity.Post) error {
+	if currency == "" || len(posts) == 0 {
+		return nil
+	}
+
+	rates, err := uc.rateRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list exchange rates: %w", err)
+	}
+	entity.ExchangeRates(rates).SetDisplayPrices(currency, posts...)
+	return nil
+}
+
+// checkCategory проверяет, что категория поста существует, и приводит
+// характеристики поста к схеме категории. Пост без категории не может иметь
+// характеристик.
+func (uc *PostUsecase) checkCategory(ctx context.Context, post *entity.Post) error {
+	var schema []entity.Attribute
+	if post.CategoryID != nil {
+		category, err := uc.categoryRepo.GetByID(ctx, *post.CategoryID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.InvalidField("category_id", "category not found")
+			}
+			return fmt.Errorf("get category: %w", err)
+		}
+		schema = category.Attributes
+	}
+
+	attributes, err := entity.ValidateAttributes(schema, post.Attributes)
+	if err != nil {
+		return err
+	}
+	post.Attributes = attributes
+	return nil
+}
+
+// checkImages проверяет одним запросом, что все изображения существуют и
+// загружены автором поста.
+func (uc *PostUsecase) checkImages(ctx context.Context, authorID uuid.UUID, imageIDs []uuid.UUID) error {
+	if len(imageIDs) == 0 {
+		return nil
+	}
+	images, err := uc.imageRepo.ListByIDs(ctx, imageIDs)
+	if err != nil {
+		return fmt.Errorf("get images: %w", err)
+	}
+
+	owners := make(map[uuid.UUID]uuid.UUID, len(images))
+	for _, image := range images {
+		owners[image.ID] = image.OwnerID
+	}
+
+	var v apperror.Violations
+	for _, id := range imageIDs {
+		owner, ok := owners[id]
+		switch {
+		case !ok:
+			v.Add("image_ids", "image %s not found", id)
+		case owner != authorID:
+			v.Add("image_ids", "image %s must be uploaded by the post author", id)
+		}
+	}
+	return v.Err()
+}
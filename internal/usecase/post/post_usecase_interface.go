@@ -3,15 +3,27 @@ package usecase
 import (
 	"context"
 	"marketplace/internal/entity"
+	usecaseRealtime "marketplace/internal/usecase/realtime"
 
 	"github.com/google/uuid"
 )
 
 type PostUseCaseRepo interface {
-	Publish(ctx context.Context, authorID uuid.UUID, header, content, image string, price float64) (*entity.Post, error)
-	Edit(ctx context.Context, postID uuid.UUID, header, content, image string, price float64) (*entity.Post, error)
+	Publish(ctx context.Context, authorID uuid.UUID, params entity.PostParams) (*entity.Post, error)
+	Edit(ctx context.Context, postID uuid.UUID, version int, params entity.PostParams) (*entity.Post, error)
+	Patch(ctx context.Context, postID uuid.UUID, version int, patch []byte) (*entity.Post, error)
+	ChangeStatus(ctx context.Context, postID uuid.UUID, status entity.PostStatus) (*entity.Post, error)
 	Delete(ctx context.Context, postID uuid.UUID) error
-	GetPost(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	Restore(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	GetPost(ctx context.Context, postID uuid.UUID, currency entity.Currency) (*entity.Post, error)
+	ListRevisions(ctx context.Context, postID uuid.UUID) ([]*entity.PostRevision, error)
+	DiffRevisions(ctx context.Context, postID uuid.UUID, from, to int) (*entity.RevisionDiff, error)
 	ListPostsByAuthor(ctx context.Context, authorID uuid.UUID, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
 	ListPosts(ctx context.Context, page, pageSize int, sortBy string, filter map[string]string) ([]*entity.Post, int, error)
+	StreamPosts(ctx context.Context, filter map[string]string, lastEventID string) (*usecaseRealtime.PostStream, error)
+	Favorite(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
+	Unfavorite(ctx context.Context, postID uuid.UUID) error
+	ListFavorites(ctx context.Context, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error)
+	CategoryFacets(ctx context.Context, filter map[string]string) ([]*entity.CategoryFacet, error)
+	CategoryFacetsByAuthor(ctx context.Context, authorID uuid.UUID, filter map[string]string) ([]*entity.CategoryFacet, error)
 }
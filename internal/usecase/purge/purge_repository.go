@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// PurgeRepository окончательно удаляет записи, помеченные удалёнными раньше before.
+type PurgeRepository interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
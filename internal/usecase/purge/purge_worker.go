@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PurgeWorker периодически удаляет навсегда посты и пользователей, которые
+// были мягко удалены дольше, чем retention назад. До этого их можно восстановить.
+type PurgeWorker struct {
+	postRepo  PurgeRepository
+	userRepo  PurgeRepository
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+	logger    *logrus.Logger
+}
+
+func NewPurgeWorker(postRepo, userRepo PurgeRepository, retention, interval time.Duration, logger *logrus.Logger) *PurgeWorker {
+	return &PurgeWorker{
+		postRepo:  postRepo,
+		userRepo:  userRepo,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// Run запускает очистку сразу и затем раз в interval, пока не будет отменён контекст.
+func (w *PurgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Purge(ctx); err != nil {
+			w.logger.WithError(err).Error("Failed to purge deleted records")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет сначала посты, затем пользователей вместе с оставшимися постами.
+func (w *PurgeWorker) Purge(ctx context.Context) error {
+	before := w.now().Add(-w.retention)
+
+	posts, err := w.postRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return fmt.Errorf("purge posts: %w", err)
+	}
+	users, err := w.userRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return fmt.Errorf("purge users: %w", err)
+	}
+
+	if posts > 0 || users > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"posts":  posts,
+			"users":  users,
+			"before": before,
+		}).Info("Deleted records purged")
+	}
+	return nil
+}
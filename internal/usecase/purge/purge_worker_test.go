@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPurgeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurgeRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestPurgeWorker_Purge(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-30 * 24 * time.Hour)
+
+	postRepo := new(MockPurgeRepository)
+	userRepo := new(MockPurgeRepository)
+	postRepo.On("PurgeDeleted", mock.Anything, before).Return(int64(3), nil)
+	userRepo.On("PurgeDeleted", mock.Anything, before).Return(int64(1), nil)
+
+	worker := NewPurgeWorker(postRepo, userRepo, 30*24*time.Hour, time.Hour, logrus.New())
+	worker.now = func() time.Time { return now }
+
+	assert.NoError(t, worker.Purge(context.Background()))
+	postRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestPurgeWorker_PurgeStopsOnError(t *testing.T) {
+	postRepo := new(MockPurgeRepository)
+	userRepo := new(MockPurgeRepository)
+	postRepo.On("PurgeDeleted", mock.Anything, mock.Anything).Return(int64(0), errors.New("connection refused"))
+
+	worker := NewPurgeWorker(postRepo, userRepo, time.Hour, time.Hour, logrus.New())
+
+	assert.Error(t, worker.Purge(context.Background()))
+	userRepo.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything)
+}
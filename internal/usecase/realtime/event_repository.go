@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type EventRepository interface {
+	Publish(ctx context.Context, event *entity.Event) error
+	Listen(ctx context.Context, handle func(event *entity.Event)) error
+}
+
+// PostRepository — кому из подключённых пользователей доставить событие поста
+// и что в нём отправить.
+type PostRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
+	FavoritedBy(ctx context.Context, postID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+}
+
+// MessageRepository загружает сообщение из события EventMessage.
+type MessageRepository interface {
+	GetMessage(ctx context.Context, id uuid.UUID) (*entity.Message, error)
+}
+
+// NotificationRepository загружает уведомление из события EventNotification.
+type NotificationRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
+}
+
+// Publisher отправляет события подключённым клиентам из других сценариев.
+type Publisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+}
+
+// FeedRepository — посты для ленты новых постов.
+type FeedRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
+	CheckFilter(filter map[string]string) error
+	MatchFeedFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) (map[uuid.UUID]*float64, error)
+}
+
+// RateRepository — курсы для цены постов ленты в валюте подписчика.
+type RateRepository interface {
+	List(ctx context.Context) ([]*entity.ExchangeRate, error)
+}
+
+// PostStreamer подписывает клиентов на ленту новых постов.
+type PostStreamer interface {
+	Subscribe(ctx context.Context, filter map[string]string, display entity.Currency, lastEventID string) (*PostStream, error)
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"marketplace/internal/usecase/policy"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// subscriptionBuffer — сколько событий ждут отправки клиенту. Клиента,
+	// который не успевает их забирать, хаб отключает.
+	subscriptionBuffer = 32
+	// listenRetryInterval — пауза перед повторной подпиской после обрыва
+	// соединения с базой.
+	listenRetryInterval = 5 * time.Second
+	// hubQueueSize — сколько полученных из базы событий ждут доставки.
+	hubQueueSize = 256
+)
+
+// Subscription — подключение клиента к хабу. Канал Events закрывается, когда
+// клиент отписался или хаб отключил его.
+type Subscription struct {
+	UserID uuid.UUID
+	events chan *entity.Event
+}
+
+func (s *Subscription) Events() <-chan *entity.Event {
+	return s.events
+}
+
+// Hub доставляет события клиентам, подключённым к этому экземпляру сервера.
+// События публикуются через базу, поэтому доходят до клиентов всех
+// экземпляров, включая тот, где событие возникло.
+type Hub struct {
+	eventRepo        EventRepository
+	postRepo         PostRepository
+	messageRepo      MessageRepository
+	notificationRepo NotificationRepository
+	feed             *PostFeed
+	queue            chan *entity.Event
+	mu               sync.Mutex
+	subscribers      map[uuid.UUID]map[*Subscription]struct{}
+	logger           *logrus.Logger
+}
+
+func NewHub(
+	eventRepo EventRepository,
+	postRepo PostRepository,
+	messageRepo MessageRepository,
+	notificationRepo NotificationRepository,
+	feed *PostFeed,
+	logger *logrus.Logger,
+) *Hub {
+	return &Hub{
+		eventRepo:        eventRepo,
+		postRepo:         postRepo,
+		messageRepo:      messageRepo,
+		notificationRepo: notificationRepo,
+		feed:             feed,
+		queue:            make(chan *entity.Event, hubQueueSize),
+		subscribers:      make(map[uuid.UUID]map[*Subscription]struct{}),
+		logger:           logger,
+	}
+}
+
+// Publish рассылает событие всем экземплярам сервера.
+func (h *Hub) Publish(ctx context.Context, event *entity.Event) error {
+	return h.eventRepo.Publish(ctx, event)
+}
+
+// Subscribe подключает текущего пользователя к хабу.
+func (h *Hub) Subscribe(ctx context.Context) (*Subscription, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	subscription := &Subscription{
+		UserID: actor.UserID,
+		events: make(chan *entity.Event, subscriptionBuffer),
+	}
+
+	h.mu.Lock()
+	if h.subscribers[actor.UserID] == nil {
+		h.subscribers[actor.UserID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[actor.UserID][subscription] = struct{}{}
+	h.mu.Unlock()
+
+	h.logger.WithField("user_id", actor.UserID).Info("Client subscribed to events")
+	return subscription, nil
+}
+
+// Unsubscribe отключает клиента. Повторный вызов ничего не делает.
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(subscription)
+}
+
+// remove вызывается под h.mu.
+func (h *Hub) remove(subscription *Subscription) {
+	subscriptions := h.subscribers[subscription.UserID]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(h.subscribers, subscription.UserID)
+	}
+	close(subscription.events)
+}
+
+// Run слушает события из базы, пока не будет отменён контекст. После обрыва
+// соединения подписка возобновляется; события, опубликованные за это время,
+// теряются. Слушатель только ставит события в очередь, а получателей ищет и
+// содержимое загружает отдельная горутина, чтобы медленный запрос не
+// задерживал чтение уведомлений из базы.
+func (h *Hub) Run(ctx context.Context) {
+	go h.process(ctx)
+
+	for {
+		err := h.eventRepo.Listen(ctx, h.enqueue)
+		if ctx.Err() != nil {
+			return
+		}
+		h.logger.WithError(err).Error("Event listener stopped, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+// enqueue ставит событие в очередь доставки. Если очередь переполнена,
+// событие теряется, как и при обрыве соединения с базой.
+func (h *Hub) enqueue(event *entity.Event) {
+	select {
+	case h.queue <- event:
+	default:
+		h.logger.WithField("type", event.Type).Warn("Event queue is full, dropping event")
+	}
+}
+
+// process доставляет события из очереди, пока не будет отменён контекст.
+func (h *Hub) process(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-h.queue:
+			h.Dispatch(ctx, event)
+		}
+	}
+}
+
+// Dispatch доставляет событие подключённым получателям. Новые посты уходят в
+// ленту GET /posts/stream. Содержимое события загружается из базы, только
+// если на этом экземпляре есть кому его отправить.
+func (h *Hub) Dispatch(ctx context.Context, event *entity.Event) {
+	if event.Type == entity.EventPostPublished {
+		h.feed.Add(event)
+		return
+	}
+
+	recipients, err := h.recipients(ctx, event)
+	if err != nil {
+		h.logger.WithError(err).WithField("type", event.Type).Error("Failed to find event recipients")
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	data, err := h.loadData(ctx, event)
+	if err != nil {
+		h.logger.WithError(err).WithField("type", event.Type).Error("Failed to load event data")
+		return
+	}
+	delivered := *event
+	delivered.Data = data
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, userID := range recipients {
+		for subscription := range h.subscribers[userID] {
+			select {
+			case subscription.events <- &delivered:
+			default:
+				h.logger.WithField("user_id", userID).Warn("Dropping slow event subscriber")
+				h.remove(subscription)
+			}
+		}
+	}
+}
+
+// recipients возвращает подключённых к этому экземпляру получателей события.
+func (h *Hub) recipients(ctx context.Context, event *entity.Event) ([]uuid.UUID, error) {
+	connected := h.connectedUsers()
+	if len(connected) == 0 {
+		return nil, nil
+	}
+	if event.PostID != nil {
+		return h.postRepo.FavoritedBy(ctx, *event.PostID, connected)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var recipients []uuid.UUID
+	for _, userID := range event.UserIDs {
+		if len(h.subscribers[userID]) > 0 {
+			recipients = append(recipients, userID)
+		}
+	}
+	return recipients, nil
+}
+
+// loadData загружает содержимое события по его ID. Событие, уже несущее
+// данные, отправляется как есть.
+func (h *Hub) loadData(ctx context.Context, event *entity.Event) (json.RawMessage, error) {
+	if event.Data != nil {
+		return event.Data, nil
+	}
+
+	var data interface{}
+	switch {
+	case event.Type == entity.EventMessage && event.ObjectID != nil:
+		message, err := h.messageRepo.GetMessage(ctx, *event.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get message: %w", err)
+		}
+		data = message
+	case event.Type == entity.EventNotification && event.ObjectID != nil:
+		notification, err := h.notificationRepo.GetByID(ctx, *event.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get notification: %w", err)
+		}
+		data = notification
+	case event.Type == entity.EventPostStatus && event.PostID != nil:
+		post, err := h.postRepo.GetByID(ctx, *event.PostID)
+		if err != nil {
+			return nil, fmt.Errorf("get post: %w", err)
+		}
+		data = map[string]interface{}{
+			"post_id": post.ID,
+			"header":  post.Header,
+			"status":  post.Status,
+		}
+	default:
+		return nil, fmt.Errorf("event %q has no data", event.Type)
+	}
+	return json.Marshal(data)
+}
+
+func (h *Hub) connectedUsers() []uuid.UUID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userIDs := make([]uuid.UUID, 0, len(h.subscribers))
+	for userID := range h.subscribers {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
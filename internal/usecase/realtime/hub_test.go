@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventRepository) Publish(ctx context.Context, event *entity.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockEventRepository) Listen(ctx context.Context, handle func(event *entity.Event)) error {
+	args := m.Called(ctx, handle)
+	return args.Error(0)
+}
+
+type MockPostRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	post, _ := args.Get(0).(*entity.Post)
+	return post, args.Error(1)
+}
+
+func (m *MockPostRepository) FavoritedBy(ctx context.Context, postID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, postID, userIDs)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+type MockMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepository) GetMessage(ctx context.Context, id uuid.UUID) (*entity.Message, error) {
+	args := m.Called(ctx, id)
+	message, _ := args.Get(0).(*entity.Message)
+	return message, args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	args := m.Called(ctx, id)
+	notification, _ := args.Get(0).(*entity.Notification)
+	return notification, args.Error(1)
+}
+
+func userContext(userID uuid.UUID) context.Context {
+	return context.WithValue(context.Background(), "user_id", userID)
+}
+
+func TestHub_DispatchUserEvent(t *testing.T) {
+	notificationRepo := new(MockNotificationRepository)
+	hub := NewHub(new(MockEventRepository), new(MockPostRepository), nil, notificationRepo, nil, logrus.New())
+	aliceID, bobID := uuid.New(), uuid.New()
+
+	alice, err := hub.Subscribe(userContext(aliceID))
+	require.NoError(t, err)
+	bob, err := hub.Subscribe(userContext(bobID))
+	require.NoError(t, err)
+
+	notification := &entity.Notification{ID: uuid.New(), UserID: aliceID, Kind: entity.NotificationPostFavorited}
+	notificationRepo.On("GetByID", mock.Anything, notification.ID).Return(notification, nil).Once()
+
+	event := entity.NewUserEvent(entity.EventNotification, notification.ID, aliceID)
+	hub.Dispatch(context.Background(), event)
+
+	delivered := <-alice.Events()
+	assert.Equal(t, entity.EventNotification, delivered.Type)
+	var data entity.Notification
+	require.NoError(t, json.Unmarshal(delivered.Data, &data))
+	assert.Equal(t, notification.ID, data.ID)
+	assert.Equal(t, entity.NotificationPostFavorited, data.Kind)
+	assert.Empty(t, bob.Events())
+	assert.Nil(t, event.Data)
+	notificationRepo.AssertExpectations(t)
+
+	hub.Unsubscribe(alice)
+	hub.Unsubscribe(alice)
+	_, open := <-alice.Events()
+	assert.False(t, open)
+
+	_, err = hub.Subscribe(context.Background())
+	assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+}
+
+func TestHub_DispatchSkipsDisconnectedUsers(t *testing.T) {
+	notificationRepo := new(MockNotificationRepository)
+	hub := NewHub(new(MockEventRepository), new(MockPostRepository), nil, notificationRepo, nil, logrus.New())
+
+	subscription, err := hub.Subscribe(userContext(uuid.New()))
+	require.NoError(t, err)
+
+	// Получатель подключён к другому экземпляру: уведомление не загружается.
+	hub.Dispatch(context.Background(), entity.NewUserEvent(entity.EventNotification, uuid.New(), uuid.New()))
+	assert.Empty(t, subscription.Events())
+	notificationRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestHub_DispatchPostEvent(t *testing.T) {
+	postRepo := new(MockPostRepository)
+	hub := NewHub(new(MockEventRepository), postRepo, nil, nil, nil, logrus.New())
+	aliceID := uuid.New()
+	post := &entity.Post{ID: uuid.New(), Header: "Велосипед", Status: entity.PostSold}
+
+	alice, err := hub.Subscribe(userContext(aliceID))
+	require.NoError(t, err)
+	postRepo.On("FavoritedBy", mock.Anything, post.ID, []uuid.UUID{aliceID}).Return([]uuid.UUID{aliceID}, nil)
+	postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+
+	event, err := entity.NewPostEvent(entity.EventPostStatus, post.ID, nil)
+	require.NoError(t, err)
+	hub.Dispatch(context.Background(), event)
+
+	delivered := <-alice.Events()
+	var data map[string]string
+	require.NoError(t, json.Unmarshal(delivered.Data, &data))
+	assert.Equal(t, map[string]string{"post_id": post.ID.String(), "header": "Велосипед", "status": string(entity.PostSold)}, data)
+	postRepo.AssertExpectations(t)
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	messageRepo := new(MockMessageRepository)
+	hub := NewHub(new(MockEventRepository), new(MockPostRepository), messageRepo, nil, nil, logrus.New())
+	userID := uuid.New()
+
+	subscription, err := hub.Subscribe(userContext(userID))
+	require.NoError(t, err)
+
+	message := &entity.Message{ID: uuid.New(), Body: "Привет"}
+	messageRepo.On("GetMessage", mock.Anything, message.ID).Return(message, nil)
+	event := entity.NewUserEvent(entity.EventMessage, message.ID, userID)
+	for i := 0; i <= subscriptionBuffer; i++ {
+		hub.Dispatch(context.Background(), event)
+	}
+
+	received := 0
+	for range subscription.Events() {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received)
+	assert.Empty(t, hub.connectedUsers())
+}
+
+func TestHub_RunDoesNotBlockListener(t *testing.T) {
+	eventRepo := new(MockEventRepository)
+	messageRepo := new(MockMessageRepository)
+	hub := NewHub(eventRepo, new(MockPostRepository), messageRepo, nil, nil, logrus.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	userID := uuid.New()
+	subscription, err := hub.Subscribe(userContext(userID))
+	require.NoError(t, err)
+
+	message := &entity.Message{ID: uuid.New(), Body: "Привет"}
+	release := make(chan time.Time)
+	messageRepo.On("GetMessage", mock.Anything, message.ID).WaitUntil(release).Return(message, nil)
+
+	listened := make(chan struct{})
+	eventRepo.On("Listen", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handle := args.Get(1).(func(event *entity.Event))
+		for i := 0; i < 3; i++ {
+			handle(entity.NewUserEvent(entity.EventMessage, message.ID, userID))
+		}
+		close(listened)
+		<-ctx.Done()
+	}).Return(context.Canceled)
+
+	go hub.Run(ctx)
+
+	// Слушатель принимает следующие события, пока загрузка первого ещё идёт.
+	select {
+	case <-listened:
+	case <-time.After(time.Second):
+		t.Fatal("listener blocked by event delivery")
+	}
+	close(release)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-subscription.Events():
+			assert.Equal(t, entity.EventMessage, event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("event was not delivered")
+		}
+	}
+}
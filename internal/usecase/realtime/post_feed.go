@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// FeedEntry — пост в ленте новых постов. ID служит id события SSE: по нему
+// переподключившийся клиент получает пропущенные посты.
+type FeedEntry struct {
+	ID          string
+	Post        *entity.Post
+	publishedAt time.Time
+}
+
+// after сообщает, опубликован ли пост позже поста с курсором cursor.
+func (e *FeedEntry) after(cursor feedCursor) bool {
+	if !e.publishedAt.Equal(cursor.publishedAt) {
+		return e.publishedAt.After(cursor.publishedAt)
+	}
+	return strings.Compare(e.Post.ID.String(), cursor.postID.String()) > 0
+}
+
+// feedCursor — разобранный ID записи ленты: время публикации в микросекундах
+// и ID поста.
+type feedCursor struct {
+	publishedAt time.Time
+	postID      uuid.UUID
+}
+
+func newFeedEntry(post *entity.Post, publishedAt time.Time) *FeedEntry {
+	publishedAt = publishedAt.Truncate(time.Microsecond)
+	return &FeedEntry{
+		ID:          fmt.Sprintf("%d-%s", publishedAt.UnixMicro(), post.ID),
+		Post:        post,
+		publishedAt: publishedAt,
+	}
+}
+
+func parseFeedCursor(id string) (feedCursor, error) {
+	micros, postID, ok := strings.Cut(id, "-")
+	if !ok {
+		return feedCursor{}, fmt.Errorf("malformed event ID %q", id)
+	}
+	at, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return feedCursor{}, fmt.Errorf("malformed event time: %w", err)
+	}
+	parsedID, err := uuid.Parse(postID)
+	if err != nil {
+		return feedCursor{}, fmt.Errorf("malformed event post ID: %w", err)
+	}
+	return feedCursor{publishedAt: time.UnixMicro(at), postID: parsedID}, nil
+}
+
+// PostStream — подписка клиента на ленту. Replay — посты после Last-Event-ID,
+// которые ещё есть в буфере ленты. Посты приходят с ценой в валюте Display и
+// расстоянием до точки фильтра, как в списке постов. Канал Events
+// закрывается, когда отменён контекст подписки или лента отключила клиента,
+// не успевающего читать.
+type PostStream struct {
+	Filter  map[string]string
+	Display entity.Currency
+	Replay  []*FeedEntry
+	key     string
+	events  chan *FeedEntry
+}
+
+func (s *PostStream) Events() <-chan *FeedEntry {
+	return s.events
+}
+
+// feedQueueSize — сколько новых постов ждут сверки с фильтрами подписок.
+const feedQueueSize = 64
+
+// PostFeed рассылает новые посты клиентам, подписанным с фильтрами списка
+// постов. Последние replaySize постов хранятся в памяти для повторной отправки
+// после переподключения, одновременно подписано не больше maxStreams клиентов.
+type PostFeed struct {
+	postRepo   FeedRepository
+	rateRepo   RateRepository
+	replaySize int
+	maxStreams int
+	queue      chan *entity.Event
+	mu         sync.Mutex
+	entries    []*FeedEntry
+	streams    map[*PostStream]struct{}
+	logger     *logrus.Logger
+}
+
+func NewPostFeed(postRepo FeedRepository, rateRepo RateRepository, replaySize, maxStreams int, logger *logrus.Logger) *PostFeed {
+	return &PostFeed{
+		postRepo:   postRepo,
+		rateRepo:   rateRepo,
+		replaySize: replaySize,
+		maxStreams: maxStreams,
+		queue:      make(chan *entity.Event, feedQueueSize),
+		streams:    make(map[*PostStream]struct{}),
+		logger:     logger,
+	}
+}
+
+// Subscribe подписывает клиента на посты, подходящие под filter. Подписка
+// действует, пока не отменён ctx.
+func (f *PostFeed) Subscribe(ctx context.Context, filter map[string]string, display entity.Currency, lastEventID string) (*PostStream, error) {
+	if err := f.postRepo.CheckFilter(filter); err != nil {
+		return nil, err
+	}
+
+	var since *feedCursor
+	if lastEventID != "" {
+		cursor, err := parseFeedCursor(lastEventID)
+		if err != nil {
+			return nil, apperror.InvalidField("Last-Event-ID", "invalid event ID")
+		}
+		since = &cursor
+	}
+
+	stream := &PostStream{
+		Filter:  filter,
+		Display: display,
+		key:     streamKey(filter, display),
+		events:  make(chan *FeedEntry, subscriptionBuffer),
+	}
+
+	// Пропущенные посты берутся из буфера под той же блокировкой, под которой
+	// подписка начинает получать новые, поэтому пост не теряется и не
+	// приходит дважды.
+	var missed []*FeedEntry
+	f.mu.Lock()
+	if len(f.streams) >= f.maxStreams {
+		f.mu.Unlock()
+		f.logger.WithField("subscribers", f.maxStreams).Warn("Post feed subscriber limit reached")
+		return nil, apperror.Unavailable("too many post stream subscribers, retry later")
+	}
+	if since != nil {
+		for _, entry := range f.entries {
+			if entry.after(*since) {
+				missed = append(missed, entry)
+			}
+		}
+	}
+	f.streams[stream] = struct{}{}
+	f.mu.Unlock()
+
+	if len(missed) > 0 {
+		replay, err := f.match(ctx, missed, filter, display)
+		if err != nil {
+			f.unsubscribe(stream)
+			return nil, fmt.Errorf("match missed posts: %w", err)
+		}
+		stream.Replay = replay
+	}
+
+	go func() {
+		<-ctx.Done()
+		f.unsubscribe(stream)
+	}()
+
+	f.logger.WithFields(logrus.Fields{
+		"filter": filter,
+		"replay": len(stream.Replay),
+	}).Info("Client subscribed to post feed")
+	return stream, nil
+}
+
+// Add ставит пост из события EventPostPublished в очередь ленты. Хаб вызывает
+// его из горутины, слушающей базу, поэтому пост загружается и сверяется с
+// фильтрами в Run. Если очередь переполнена, пост в ленту не попадает.
+func (f *PostFeed) Add(event *entity.Event) {
+	select {
+	case f.queue <- event:
+	default:
+		f.logger.WithField("post_id", event.PostID).Warn("Post feed queue is full, dropping post")
+	}
+}
+
+// Run обрабатывает очередь ленты, пока не будет отменён контекст.
+func (f *PostFeed) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-f.queue:
+			f.add(ctx, event)
+		}
+	}
+}
+
+// add добавляет пост в ленту и отправляет его подписчикам, чей фильтр он
+// проходит. Фильтр проверяется одним запросом на все подписки с одинаковым
+// фильтром и валютой цены.
+func (f *PostFeed) add(ctx context.Context, event *entity.Event) {
+	if event.PostID == nil {
+		return
+	}
+	var data entity.PostPublishedData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		f.logger.WithError(err).WithField("post_id", *event.PostID).Error("Failed to decode post published event")
+		return
+	}
+
+	post, err := f.postRepo.GetByID(ctx, *event.PostID)
+	if err != nil {
+		f.logger.WithError(err).WithField("post_id", *event.PostID).Error("Failed to load published post")
+		return
+	}
+	entry := newFeedEntry(post, data.PublishedAt)
+
+	f.mu.Lock()
+	f.insert(entry)
+	groups := make(map[string][]*PostStream)
+	for stream := range f.streams {
+		groups[stream.key] = append(groups[stream.key], stream)
+	}
+	f.mu.Unlock()
+
+	for _, streams := range groups {
+		matched, err := f.match(ctx, []*FeedEntry{entry}, streams[0].Filter, streams[0].Display)
+		if err != nil {
+			f.logger.WithError(err).WithField("post_id", post.ID).Error("Failed to match post against feed filter")
+			continue
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		f.deliver(matched[0], streams)
+	}
+}
+
+// insert вызывается под f.mu. Записи хранятся по порядку публикации; самые
+// старые вытесняются, когда буфер заполнен.
+func (f *PostFeed) insert(entry *FeedEntry) {
+	i := sort.Search(len(f.entries), func(i int) bool {
+		return f.entries[i].publishedAt.After(entry.publishedAt)
+	})
+	f.entries = append(f.entries, nil)
+	copy(f.entries[i+1:], f.entries[i:])
+	f.entries[i] = entry
+
+	if len(f.entries) > f.replaySize {
+		f.entries = f.entries[len(f.entries)-f.replaySize:]
+	}
+}
+
+func (f *PostFeed) deliver(entry *FeedEntry, streams []*PostStream) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, stream := range streams {
+		if _, ok := f.streams[stream]; !ok {
+			continue
+		}
+		select {
+		case stream.events <- entry:
+		default:
+			f.logger.WithField("filter", stream.Filter).Warn("Dropping slow post feed subscriber")
+			f.remove(stream)
+		}
+	}
+}
+
+// match оставляет записи, посты которых проходят filter. Возвращаются копии
+// записей с ценой в валюте display и расстоянием до точки фильтра, как в
+// списке постов; записи в буфере ленты не меняются.
+func (f *PostFeed) match(ctx context.Context, entries []*FeedEntry, filter map[string]string, display entity.Currency) ([]*FeedEntry, error) {
+	postIDs := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		postIDs[i] = entry.Post.ID
+	}
+
+	distances, err := f.postRepo.MatchFeedFilter(ctx, postIDs, filter)
+	if err != nil {
+		return nil, err
+	}
+	var rates entity.ExchangeRates
+	if display != "" && len(distances) > 0 {
+		rates, err = f.rateRepo.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list exchange rates: %w", err)
+		}
+	}
+
+	var result []*FeedEntry
+	for _, entry := range entries {
+		distance, ok := distances[entry.Post.ID]
+		if !ok {
+			continue
+		}
+		post := *entry.Post
+		post.Distance = distance
+		rates.SetDisplayPrices(display, &post)
+		result = append(result, &FeedEntry{ID: entry.ID, Post: &post, publishedAt: entry.publishedAt})
+	}
+	return result, nil
+}
+
+func (f *PostFeed) unsubscribe(stream *PostStream) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.remove(stream)
+}
+
+// remove вызывается под f.mu.
+func (f *PostFeed) remove(stream *PostStream) {
+	if _, ok := f.streams[stream]; !ok {
+		return
+	}
+	delete(f.streams, stream)
+	close(stream.events)
+}
+
+// streamKey — одинаковая строка для подписок с равными фильтрами и валютой
+// цены.
+func streamKey(filter map[string]string, display entity.Currency) string {
+	values := make(url.Values, len(filter)+1)
+	for key, value := range filter {
+		values.Set(key, value)
+	}
+	return values.Encode() + "|" + string(display)
+}
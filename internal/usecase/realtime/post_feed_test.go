@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	post, _ := args.Get(0).(*entity.Post)
+	return post, args.Error(1)
+}
+
+func (m *MockFeedRepository) CheckFilter(filter map[string]string) error {
+	args := m.Called(filter)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) MatchFeedFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) (map[uuid.UUID]*float64, error) {
+	args := m.Called(ctx, postIDs, filter)
+	matched, _ := args.Get(0).(map[uuid.UUID]*float64)
+	return matched, args.Error(1)
+}
+
+type MockRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateRepository) List(ctx context.Context) ([]*entity.ExchangeRate, error) {
+	args := m.Called(ctx)
+	rates, _ := args.Get(0).([]*entity.ExchangeRate)
+	return rates, args.Error(1)
+}
+
+func publishedEvent(t *testing.T, postID uuid.UUID, at time.Time) *entity.Event {
+	event, err := entity.NewPostEvent(entity.EventPostPublished, postID, entity.PostPublishedData{PublishedAt: at})
+	require.NoError(t, err)
+	return event
+}
+
+func TestPostFeed_DeliversMatchingPosts(t *testing.T) {
+	postRepo := new(MockFeedRepository)
+	feed := NewPostFeed(postRepo, new(MockRateRepository), 10, 10, logrus.New())
+	hub := NewHub(new(MockEventRepository), new(MockPostRepository), nil, nil, feed, logrus.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go feed.Run(ctx)
+
+	bikes := map[string]string{"tag": "bike"}
+	cars := map[string]string{"tag": "car"}
+	postRepo.On("CheckFilter", mock.Anything).Return(nil)
+
+	first, err := feed.Subscribe(ctx, bikes, "", "")
+	require.NoError(t, err)
+	second, err := feed.Subscribe(ctx, map[string]string{"tag": "bike"}, "", "")
+	require.NoError(t, err)
+	other, err := feed.Subscribe(ctx, cars, "", "")
+	require.NoError(t, err)
+
+	post := &entity.Post{ID: uuid.New(), Header: "Bike"}
+	postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	// Одинаковые фильтры проверяются одним запросом.
+	postRepo.On("MatchFeedFilter", mock.Anything, []uuid.UUID{post.ID}, bikes).Return(map[uuid.UUID]*float64{post.ID: nil}, nil).Once()
+	postRepo.On("MatchFeedFilter", mock.Anything, []uuid.UUID{post.ID}, cars).Return(map[uuid.UUID]*float64{}, nil).Once()
+
+	hub.Dispatch(ctx, publishedEvent(t, post.ID, time.Now()))
+
+	entry := <-first.Events()
+	assert.Equal(t, post, entry.Post)
+	assert.Equal(t, entry, <-second.Events())
+	assert.Empty(t, other.Events())
+	postRepo.AssertExpectations(t)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		select {
+		case _, open := <-first.Events():
+			return !open
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPostFeed_ReplaysMissedPosts(t *testing.T) {
+	postRepo := new(MockFeedRepository)
+	feed := NewPostFeed(postRepo, new(MockRateRepository), 2, 10, logrus.New())
+	ctx := context.Background()
+	filter := map[string]string{"tag": "bike"}
+
+	start := time.Now()
+	posts := make([]*entity.Post, 3)
+	for i := range posts {
+		posts[i] = &entity.Post{ID: uuid.New()}
+		postRepo.On("GetByID", mock.Anything, posts[i].ID).Return(posts[i], nil)
+		feed.add(ctx, publishedEvent(t, posts[i].ID, start.Add(time.Duration(i)*time.Second)))
+	}
+	firstID := newFeedEntry(posts[0], start).ID
+
+	// Буфер хранит два последних поста; третий не проходит фильтр.
+	postRepo.On("CheckFilter", filter).Return(nil)
+	postRepo.On("MatchFeedFilter", mock.Anything, []uuid.UUID{posts[1].ID, posts[2].ID}, filter).
+		Return(map[uuid.UUID]*float64{posts[1].ID: nil}, nil)
+
+	stream, err := feed.Subscribe(ctx, filter, "", firstID)
+	require.NoError(t, err)
+	require.Len(t, stream.Replay, 1)
+	assert.Equal(t, posts[1], stream.Replay[0].Post)
+
+	stream, err = feed.Subscribe(ctx, filter, "", newFeedEntry(posts[2], start.Add(2*time.Second)).ID)
+	require.NoError(t, err)
+	assert.Empty(t, stream.Replay)
+
+	_, err = feed.Subscribe(ctx, filter, "", "not-an-id")
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func TestPostFeed_LimitsSubscribers(t *testing.T) {
+	postRepo := new(MockFeedRepository)
+	feed := NewPostFeed(postRepo, new(MockRateRepository), 10, 1, logrus.New())
+	postRepo.On("CheckFilter", mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := feed.Subscribe(ctx, map[string]string{}, "", "")
+	require.NoError(t, err)
+
+	_, err = feed.Subscribe(context.Background(), map[string]string{}, "", "")
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+
+	// Место освобождается, когда первый клиент отключается.
+	cancel()
+	assert.Eventually(t, func() bool {
+		stream, err := feed.Subscribe(context.Background(), map[string]string{}, "", "")
+		return err == nil && stream != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPostFeed_FillsDisplayPriceAndDistance(t *testing.T) {
+	postRepo := new(MockFeedRepository)
+	rateRepo := new(MockRateRepository)
+	feed := NewPostFeed(postRepo, rateRepo, 10, 10, logrus.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nearby := map[string]string{"lat": "55.75", "lng": "37.61", "radius_km": "10", "currency": "USD"}
+	plain := map[string]string{"currency": "RUB"}
+	postRepo.On("CheckFilter", mock.Anything).Return(nil)
+	rateRepo.On("List", mock.Anything).Return([]*entity.ExchangeRate{{Base: "USD", Quote: "RUB", Rate: "100"}}, nil)
+
+	geo, err := feed.Subscribe(ctx, nearby, "USD", "")
+	require.NoError(t, err)
+	// Валюта задана только для сравнения цен, как в списке без currency.
+	other, err := feed.Subscribe(ctx, plain, "", "")
+	require.NoError(t, err)
+
+	post := &entity.Post{ID: uuid.New(), Price: entity.Money{Amount: 250000, Currency: "RUB"}}
+	distance := 1.25
+	postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	postRepo.On("MatchFeedFilter", mock.Anything, []uuid.UUID{post.ID}, nearby).Return(map[uuid.UUID]*float64{post.ID: &distance}, nil)
+	postRepo.On("MatchFeedFilter", mock.Anything, []uuid.UUID{post.ID}, plain).Return(map[uuid.UUID]*float64{post.ID: nil}, nil)
+
+	feed.add(ctx, publishedEvent(t, post.ID, time.Now()))
+
+	entry := <-geo.Events()
+	require.NotNil(t, entry.Post.Distance)
+	assert.Equal(t, 1.25, *entry.Post.Distance)
+	require.NotNil(t, entry.Post.DisplayPrice)
+	assert.Equal(t, entity.Money{Amount: 2500, Currency: "USD"}, *entry.Post.DisplayPrice)
+
+	entry = <-other.Events()
+	assert.Nil(t, entry.Post.Distance)
+	assert.Nil(t, entry.Post.DisplayPrice)
+	// Пост в буфере ленты остаётся без данных подписчиков.
+	assert.Nil(t, post.Distance)
+	assert.Nil(t, post.DisplayPrice)
+	rateRepo.AssertNumberOfCalls(t, "List", 1)
+}
@@ -0,0 +1,8 @@
+package usecase
+
+import "context"
+
+type RealtimeUseCaseRepo interface {
+	Subscribe(ctx context.Context) (*Subscription, error)
+	Unsubscribe(subscription *Subscription)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseNotification "marketplace/internal/usecase/notification"
+	"marketplace/pkg/poller"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	matchBatchSize = 100
+	// matchSearchPageSize — сколько сохранённых поисков загружается за раз.
+	matchSearchPageSize = 500
+)
+
+// MatchWorker в фоне сверяет новые опубликованные посты с сохранёнными
+// поисками, записывает совпадения и уведомляет о них владельцев поисков.
+// Очередь — посты без searches_matched_at в базе.
+type MatchWorker struct {
+	searchRepo SavedSearchRepository
+	postRepo   PostRepository
+	notifier   usecaseNotification.Notifier
+	poller     *poller.Poller
+	logger     *logrus.Logger
+}
+
+func NewMatchWorker(searchRepo SavedSearchRepository, postRepo PostRepository, notifier usecaseNotification.Notifier, interval time.Duration, logger *logrus.Logger) *MatchWorker {
+	return &MatchWorker{
+		searchRepo: searchRepo,
+		postRepo:   postRepo,
+		notifier:   notifier,
+		poller:     poller.New(interval),
+		logger:     logger,
+	}
+}
+
+// Notify сообщает воркеру, что опубликованы новые посты. Не блокируется.
+func (w *MatchWorker) Notify() {
+	w.poller.Notify()
+}
+
+// Run сверяет посты, пока не будет отменён контекст.
+func (w *MatchWorker) Run(ctx context.Context) {
+	w.poller.Run(ctx, w.processPending)
+}
+
+func (w *MatchWorker) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		postIDs, err := w.postRepo.ListUnmatched(ctx, matchBatchSize)
+		if err != nil {
+			w.logger.WithError(err).Error("Failed to list posts pending saved search matching")
+			return
+		}
+		if len(postIDs) == 0 {
+			return
+		}
+
+		if err := w.Match(ctx, postIDs); err != nil {
+			// Ошибка базы: повторим при следующем опросе. Уже записанные
+			// совпадения повторно не добавятся.
+			w.logger.WithError(err).Error("Failed to match posts with saved searches")
+			return
+		}
+		if err := w.postRepo.MarkSearchesMatched(ctx, postIDs); err != nil {
+			w.logger.WithError(err).Error("Failed to mark posts matched with saved searches")
+			return
+		}
+
+		if len(postIDs) < matchBatchSize {
+			return
+		}
+	}
+}
+
+// Match сверяет посты со всеми сохранёнными поисками. Поиски загружаются
+// страницами в порядке фильтра, и поиски с одинаковыми фильтрами проверяются
+// одним запросом. Поиск, фильтры которого стали некорректными, например из-за
+// удалённой валюты, пропускается. Свои посты владельцу поиска не
+// засчитываются.
+func (w *MatchWorker) Match(ctx context.Context, postIDs []uuid.UUID) error {
+	authors, err := w.postRepo.PostAuthors(ctx, postIDs)
+	if err != nil {
+		return fmt.Errorf("get post authors: %w", err)
+	}
+
+	var total int64
+	var searchCount int
+	var after *entity.SavedSearch
+	for {
+		searches, err := w.searchRepo.ListAfter(ctx, after, matchSearchPageSize)
+		if err != nil {
+			return fmt.Errorf("list saved searches: %w", err)
+		}
+		searchCount += len(searches)
+
+		for _, group := range groupByFilter(searches) {
+			added, err := w.matchGroup(ctx, postIDs, authors, group)
+			if err != nil {
+				return err
+			}
+			total += added
+		}
+
+		if len(searches) < matchSearchPageSize {
+			break
+		}
+		after = searches[len(searches)-1]
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"posts":          len(postIDs),
+		"saved_searches": searchCount,
+		"matches":        total,
+	}).Info("Posts matched with saved searches")
+	return nil
+}
+
+// matchGroup сверяет посты с поисками, у которых одинаковый фильтр, и
+// возвращает число новых совпадений.
+func (w *MatchWorker) matchGroup(ctx context.Context, postIDs []uuid.UUID, authors map[uuid.UUID]uuid.UUID, searches []*entity.SavedSearch) (int64, error) {
+	matched, err := w.postRepo.MatchFilter(ctx, postIDs, searches[0].Filter)
+	if errors.Is(err, apperror.ErrValidation) {
+		w.logger.WithError(err).WithField("filter", searches[0].Filter).Warn("Skipping saved searches with invalid filter")
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("match saved search %s: %w", searches[0].ID, err)
+	}
+
+	var total int64
+	for _, search := range searches {
+		var own []uuid.UUID
+		for _, postID := range matched {
+			if authors[postID] != search.UserID {
+				own = append(own, postID)
+			}
+		}
+		if len(own) == 0 {
+			continue
+		}
+
+		added, err := w.searchRepo.AddMatches(ctx, search.ID, own)
+		if err != nil {
+			return total, fmt.Errorf("add saved search matches: %w", err)
+		}
+		total += added
+
+		if added > 0 {
+			w.notify(ctx, search, added)
+		}
+	}
+	return total, nil
+}
+
+// groupByFilter разбивает поиски, упорядоченные по фильтру, на группы с
+// одинаковым фильтром.
+func groupByFilter(searches []*entity.SavedSearch) [][]*entity.SavedSearch {
+	var groups [][]*entity.SavedSearch
+	for i, search := range searches {
+		if i > 0 && maps.Equal(search.Filter, searches[i-1].Filter) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], search)
+			continue
+		}
+		groups = append(groups, []*entity.SavedSearch{search})
+	}
+	return groups
+}
+
+// notify сообщает владельцу поиска о новых совпадениях. Ошибка только пишется
+// в лог: совпадения уже записаны и видны в списке.
+func (w *MatchWorker) notify(ctx context.Context, search *entity.SavedSearch, added int64) {
+	err := w.notifier.Notify(ctx, search.UserID, entity.NotificationSavedSearchMatch, entity.NotificationData{
+		"saved_search_id": search.ID,
+		"name":            search.Name,
+		"matches":         added,
+	})
+	if err != nil {
+		w.logger.WithError(err).WithField("saved_search_id", search.ID).Error("Failed to notify about saved search matches")
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, userID uuid.UUID, kind entity.NotificationKind, data entity.NotificationData) error {
+	args := m.Called(ctx, userID, kind, data)
+	return args.Error(0)
+}
+
+func TestMatchWorker_ProcessPending(t *testing.T) {
+	postIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	bikes := &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Name: "Велосипеды", Filter: map[string]string{"q": "велосипед"}}
+	broken := &entity.SavedSearch{ID: uuid.New(), Filter: map[string]string{"currency": "XXX", "max_price": "10"}}
+	nothing := &entity.SavedSearch{ID: uuid.New(), Filter: map[string]string{"q": "самокат"}}
+
+	searchRepo := new(MockSavedSearchRepository)
+	postRepo := new(MockPostRepository)
+	postRepo.On("ListUnmatched", mock.Anything, matchBatchSize).Return(postIDs, nil)
+	postRepo.On("PostAuthors", mock.Anything, postIDs).Return(map[uuid.UUID]uuid.UUID{postIDs[0]: uuid.New(), postIDs[1]: uuid.New()}, nil)
+	searchRepo.On("ListAfter", mock.Anything, (*entity.SavedSearch)(nil), matchSearchPageSize).Return([]*entity.SavedSearch{bikes, broken, nothing}, nil)
+	postRepo.On("MatchFilter", mock.Anything, postIDs, bikes.Filter).Return(postIDs[:1], nil)
+	postRepo.On("MatchFilter", mock.Anything, postIDs, broken.Filter).Return(nil, apperror.InvalidField("currency", "unsupported currency"))
+	postRepo.On("MatchFilter", mock.Anything, postIDs, nothing.Filter).Return([]uuid.UUID(nil), nil)
+	searchRepo.On("AddMatches", mock.Anything, bikes.ID, postIDs[:1]).Return(int64(1), nil)
+	postRepo.On("MarkSearchesMatched", mock.Anything, postIDs).Return(nil)
+	// Уведомление получает только владелец поиска с новыми совпадениями.
+	notifier := new(MockNotifier)
+	notifier.On("Notify", mock.Anything, bikes.UserID, entity.NotificationSavedSearchMatch, entity.NotificationData{
+		"saved_search_id": bikes.ID,
+		"name":            "Велосипеды",
+		"matches":         int64(1),
+	}).Return(nil)
+
+	worker := NewMatchWorker(searchRepo, postRepo, notifier, 0, logrus.New())
+	worker.processPending(context.Background())
+
+	searchRepo.AssertExpectations(t)
+	postRepo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+	notifier.AssertNumberOfCalls(t, "Notify", 1)
+	searchRepo.AssertNotCalled(t, "AddMatches", mock.Anything, broken.ID, mock.Anything)
+	searchRepo.AssertNotCalled(t, "AddMatches", mock.Anything, nothing.ID, mock.Anything)
+}
+
+func TestMatchWorker_GroupsFiltersAndSkipsOwnPosts(t *testing.T) {
+	authorID := uuid.New()
+	postIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	filter := map[string]string{"q": "велосипед"}
+	// Автор поста тоже ищет велосипеды: свой пост ему не засчитывается.
+	own := &entity.SavedSearch{ID: uuid.New(), UserID: authorID, Filter: map[string]string{"q": "велосипед"}}
+	other := &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Filter: map[string]string{"q": "велосипед"}}
+	scooters := &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Filter: map[string]string{"q": "самокат"}}
+
+	searchRepo := new(MockSavedSearchRepository)
+	postRepo := new(MockPostRepository)
+	postRepo.On("PostAuthors", mock.Anything, postIDs).Return(map[uuid.UUID]uuid.UUID{postIDs[0]: authorID, postIDs[1]: uuid.New()}, nil)
+	// Первая страница заполнена целиком, поэтому запрашивается следующая.
+	firstPage := make([]*entity.SavedSearch, 0, matchSearchPageSize)
+	firstPage = append(firstPage, own, other)
+	for len(firstPage) < matchSearchPageSize {
+		firstPage = append(firstPage, scooters)
+	}
+	searchRepo.On("ListAfter", mock.Anything, (*entity.SavedSearch)(nil), matchSearchPageSize).Return(firstPage, nil)
+	searchRepo.On("ListAfter", mock.Anything, scooters, matchSearchPageSize).Return([]*entity.SavedSearch{}, nil)
+	postRepo.On("MatchFilter", mock.Anything, postIDs, filter).Return(postIDs, nil).Once()
+	postRepo.On("MatchFilter", mock.Anything, postIDs, scooters.Filter).Return([]uuid.UUID(nil), nil).Once()
+	searchRepo.On("AddMatches", mock.Anything, own.ID, postIDs[1:]).Return(int64(1), nil)
+	searchRepo.On("AddMatches", mock.Anything, other.ID, postIDs).Return(int64(2), nil)
+	notifier := new(MockNotifier)
+	notifier.On("Notify", mock.Anything, mock.Anything, entity.NotificationSavedSearchMatch, mock.Anything).Return(nil)
+
+	worker := NewMatchWorker(searchRepo, postRepo, notifier, 0, logrus.New())
+	require.NoError(t, worker.Match(context.Background(), postIDs))
+
+	searchRepo.AssertExpectations(t)
+	postRepo.AssertExpectations(t)
+	notifier.AssertNumberOfCalls(t, "Notify", 2)
+}
+
+func TestMatchWorker_RetriesOnError(t *testing.T) {
+	postIDs := []uuid.UUID{uuid.New()}
+	search := &entity.SavedSearch{ID: uuid.New(), Filter: map[string]string{"q": "велосипед"}}
+
+	searchRepo := new(MockSavedSearchRepository)
+	postRepo := new(MockPostRepository)
+	postRepo.On("ListUnmatched", mock.Anything, matchBatchSize).Return(postIDs, nil)
+	postRepo.On("PostAuthors", mock.Anything, postIDs).Return(map[uuid.UUID]uuid.UUID{}, nil)
+	searchRepo.On("ListAfter", mock.Anything, (*entity.SavedSearch)(nil), matchSearchPageSize).Return([]*entity.SavedSearch{search}, nil)
+	postRepo.On("MatchFilter", mock.Anything, postIDs, search.Filter).Return(nil, errors.New("connection reset"))
+
+	worker := NewMatchWorker(searchRepo, postRepo, new(MockNotifier), 0, logrus.New())
+	worker.processPending(context.Background())
+
+	// Посты остаются несверенными и будут обработаны при следующем опросе.
+	postRepo.AssertNotCalled(t, "MarkSearchesMatched", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *entity.SavedSearch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavedSearch, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	ListAfter(ctx context.Context, after *entity.SavedSearch, limit int) ([]*entity.SavedSearch, error)
+	Update(ctx context.Context, search *entity.SavedSearch) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddMatches(ctx context.Context, searchID uuid.UUID, postIDs []uuid.UUID) (int64, error)
+	MarkSeen(ctx context.Context, searchID uuid.UUID) (int64, error)
+}
+
+// PostRepository — выборка постов по фильтрам сохранённых поисков.
+type PostRepository interface {
+	CheckFilter(filter map[string]string) error
+	ListUnmatched(ctx context.Context, limit int) ([]uuid.UUID, error)
+	MatchFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) ([]uuid.UUID, error)
+	PostAuthors(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	MarkSearchesMatched(ctx context.Context, postIDs []uuid.UUID) error
+	ListSavedSearchMatches(ctx context.Context, searchID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error)
+	FavoritePostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+// MatchNotifier будит фоновый подбор совпадений, когда появляются новые
+// опубликованные посты.
+type MatchNotifier interface {
+	Notify()
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+	usecaseExchangeRate "marketplace/internal/usecase/exchangerate"
+	"marketplace/internal/usecase/policy"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchUsecase struct {
+	searchRepo SavedSearchRepository
+	postRepo   PostRepository
+	rateRepo   usecaseExchangeRate.ExchangeRateRepository
+	// currency — валюта границ цены, если при сохранении поиска её не указали.
+	currency entity.Currency
+	logger   *logrus.Logger
+}
+
+func NewSavedSearchUsecase(searchRepo SavedSearchRepository, postRepo PostRepository, rateRepo usecaseExchangeRate.ExchangeRateRepository, currency entity.Currency, logger *logrus.Logger) *SavedSearchUsecase {
+	return &SavedSearchUsecase{
+		searchRepo: searchRepo,
+		postRepo:   postRepo,
+		rateRepo:   rateRepo,
+		currency:   currency,
+		logger:     logger,
+	}
+}
+
+func (uc *SavedSearchUsecase) Create(ctx context.Context, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	search := &entity.SavedSearch{
+		ID:        uuid.New(),
+		UserID:    actor.UserID,
+		Name:      name,
+		Filter:    uc.searchFilter(filter),
+		CreatedAt: time.Now(),
+	}
+	if err := uc.validate(search); err != nil {
+		return nil, err
+	}
+
+	count, err := uc.searchRepo.CountByUserID(ctx, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("count saved searches: %w", err)
+	}
+	if count >= entity.MaxSavedSearches {
+		return nil, apperror.Conflict("no more than %d saved searches are allowed", entity.MaxSavedSearches)
+	}
+
+	if err := uc.searchRepo.Create(ctx, search); err != nil {
+		return nil, fmt.Errorf("create saved search: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+		"user_id":         actor.UserID,
+		"filter":          search.Filter,
+	}).Info("Saved search created")
+
+	return search, nil
+}
+
+func (uc *SavedSearchUsecase) Get(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error) {
+	search, err := uc.searchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get saved search: %w", err)
+	}
+	if _, err := policy.AuthorizeManageSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (uc *SavedSearchUsecase) List(ctx context.Context) ([]*entity.SavedSearch, error) {
+	actor, ok := policy.ActorFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthorized("authentication required")
+	}
+
+	searches, err := uc.searchRepo.ListByUserID(ctx, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("list saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+// Update заменяет название и фильтры поиска. Уже найденные совпадения
+// остаются, новые посты сверяются с новыми фильтрами.
+func (uc *SavedSearchUsecase) Update(ctx context.Context, id uuid.UUID, name string, filter map[string]string) (*entity.SavedSearch, error) {
+	search, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	search.Name = name
+	search.Filter = uc.searchFilter(filter)
+	if err := uc.validate(search); err != nil {
+		return nil, err
+	}
+
+	if err := uc.searchRepo.Update(ctx, search); err != nil {
+		return nil, fmt.Errorf("update saved search: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+		"filter":          search.Filter,
+	}).Info("Saved search updated")
+
+	return search, nil
+}
+
+func (uc *SavedSearchUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.Get(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.searchRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+
+	uc.logger.WithField("saved_search_id", id).Info("Saved search deleted")
+	return nil
+}
+
+// ListMatches возвращает непросмотренные совпадения поиска, начиная с
+// последних. Цены показываются в валюте currency, если она задана.
+func (uc *SavedSearchUsecase) ListMatches(ctx context.Context, id uuid.UUID, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error) {
+	search, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts, total, err := uc.postRepo.ListSavedSearchMatches(ctx, search.ID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list saved search matches: %w", err)
+	}
+
+	// Собственные посты в совпадения не попадают, поэтому IsOwnPost не нужен.
+	postIDs := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	favorites, err := uc.postRepo.FavoritePostIDs(ctx, search.UserID, postIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get favorites: %w", err)
+	}
+	for _, post := range posts {
+		post.IsFavorited = favorites[post.ID]
+	}
+
+	if currency != "" && len(posts) > 0 {
+		rates, err := uc.rateRepo.List(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("list exchange rates: %w", err)
+		}
+		entity.ExchangeRates(rates).SetDisplayPrices(currency, posts...)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+		"page":            page,
+		"page_size":       pageSize,
+		"total_posts":     total,
+	}).Info("Saved search matches listed")
+
+	return posts, total, nil
+}
+
+// MarkSeen отмечает все совпадения поиска просмотренными и возвращает их число.
+func (uc *SavedSearchUsecase) MarkSeen(ctx context.Context, id uuid.UUID) (int64, error) {
+	search, err := uc.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	seen, err := uc.searchRepo.MarkSeen(ctx, search.ID)
+	if err != nil {
+		return 0, fmt.Errorf("mark saved search matches seen: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"saved_search_id": search.ID,
+		"seen":            seen,
+	}).Info("Saved search matches marked as seen")
+
+	return seen, nil
+}
+
+// searchFilter убирает из фильтров статус и закрепляет валюту границ цены,
+// чтобы смена валюты по умолчанию не меняла смысл сохранённого поиска.
+func (uc *SavedSearchUsecase) searchFilter(filter map[string]string) map[string]string {
+	saved := entity.SavedSearchFilter(filter)
+	_, hasMin := saved["min_price"]
+	_, hasMax := saved["max_price"]
+	if (hasMin || hasMax) && saved["currency"] == "" {
+		saved["currency"] = string(uc.currency)
+	}
+	return saved
+}
+
+func (uc *SavedSearchUsecase) validate(search *entity.SavedSearch) error {
+	if err := apperror.Merge(search.Validate(), uc.postRepo.CheckFilter(search.Filter)); err != nil {
+		return fmt.Errorf("validate saved search: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+type SavedSearchUseCaseRepo interface {
+	Create(ctx context.Context, name string, filter map[string]string) (*entity.SavedSearch, error)
+	Get(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error)
+	List(ctx context.Context) ([]*entity.SavedSearch, error)
+	Update(ctx context.Context, id uuid.UUID, name string, filter map[string]string) (*entity.SavedSearch, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListMatches(ctx context.Context, id uuid.UUID, page, pageSize int, currency entity.Currency) ([]*entity.Post, int, error)
+	MarkSeen(ctx context.Context, id uuid.UUID) (int64, error)
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"marketplace/internal/apperror"
+	"marketplace/internal/entity"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepository) Create(ctx context.Context, search *entity.SavedSearch) error {
+	args := m.Called(ctx, search)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SavedSearch, error) {
+	args := m.Called(ctx, id)
+	search, _ := args.Get(0).(*entity.SavedSearch)
+	return search, args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*entity.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) ListAfter(ctx context.Context, after *entity.SavedSearch, limit int) ([]*entity.SavedSearch, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]*entity.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) Update(ctx context.Context, search *entity.SavedSearch) error {
+	args := m.Called(ctx, search)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) AddMatches(ctx context.Context, searchID uuid.UUID, postIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, searchID, postIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) MarkSeen(ctx context.Context, searchID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, searchID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockPostRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRepository) CheckFilter(filter map[string]string) error {
+	args := m.Called(filter)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) ListUnmatched(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPostRepository) MatchFilter(ctx context.Context, postIDs []uuid.UUID, filter map[string]string) ([]uuid.UUID, error) {
+	args := m.Called(ctx, postIDs, filter)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func (m *MockPostRepository) PostAuthors(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	args := m.Called(ctx, postIDs)
+	authors, _ := args.Get(0).(map[uuid.UUID]uuid.UUID)
+	return authors, args.Error(1)
+}
+
+func (m *MockPostRepository) MarkSearchesMatched(ctx context.Context, postIDs []uuid.UUID) error {
+	args := m.Called(ctx, postIDs)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) ListSavedSearchMatches(ctx context.Context, searchID uuid.UUID, page, pageSize int) ([]*entity.Post, int, error) {
+	args := m.Called(ctx, searchID, page, pageSize)
+	return args.Get(0).([]*entity.Post), args.Int(1), args.Error(2)
+}
+
+func (m *MockPostRepository) FavoritePostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	args := m.Called(ctx, userID, postIDs)
+	return args.Get(0).(map[uuid.UUID]bool), args.Error(1)
+}
+
+func actorContext(userID uuid.UUID) context.Context {
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	return context.WithValue(ctx, "user_role", entity.RoleUser)
+}
+
+func TestSavedSearchUsecase_Create(t *testing.T) {
+	userID := uuid.New()
+	searchRepo := new(MockSavedSearchRepository)
+	postRepo := new(MockPostRepository)
+	uc := NewSavedSearchUsecase(searchRepo, postRepo, nil, entity.Currency("RUB"), logrus.New())
+
+	// Границы цены без валюты закрепляются в валюте по умолчанию, статус
+	// отбрасывается.
+	want := map[string]string{"q": "велосипед", "max_price": "5000", "currency": "RUB"}
+	postRepo.On("CheckFilter", want).Return(nil)
+	searchRepo.On("CountByUserID", mock.Anything, userID).Return(0, nil)
+	searchRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.SavedSearch")).Return(nil)
+
+	search, err := uc.Create(actorContext(userID), "Велосипеды", map[string]string{"q": "велосипед", "max_price": "5000", "status": "sold"})
+	require.NoError(t, err)
+	assert.Equal(t, userID, search.UserID)
+	assert.Equal(t, want, search.Filter)
+	searchRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchUsecase_CreateRejects(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("limit reached", func(t *testing.T) {
+		searchRepo := new(MockSavedSearchRepository)
+		postRepo := new(MockPostRepository)
+		postRepo.On("CheckFilter", mock.Anything).Return(nil)
+		searchRepo.On("CountByUserID", mock.Anything, userID).Return(entity.MaxSavedSearches, nil)
+
+		uc := NewSavedSearchUsecase(searchRepo, postRepo, nil, entity.Currency("RUB"), logrus.New())
+		_, err := uc.Create(actorContext(userID), "Велосипеды", map[string]string{"q": "велосипед"})
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+		searchRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		postRepo.On("CheckFilter", mock.Anything).Return(apperror.InvalidField("max_price", "max_price must be an amount in RUB"))
+
+		uc := NewSavedSearchUsecase(new(MockSavedSearchRepository), postRepo, nil, entity.Currency("RUB"), logrus.New())
+		_, err := uc.Create(actorContext(userID), "", map[string]string{"max_price": "abc"})
+		require.ErrorIs(t, err, apperror.ErrValidation)
+		assert.Len(t, apperror.ViolationsOf(err), 2)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		uc := NewSavedSearchUsecase(new(MockSavedSearchRepository), new(MockPostRepository), nil, entity.Currency("RUB"), logrus.New())
+		_, err := uc.Create(context.Background(), "Велосипеды", map[string]string{"q": "велосипед"})
+		assert.ErrorIs(t, err, apperror.ErrUnauthorized)
+	})
+}
+
+func TestSavedSearchUsecase_GetForeign(t *testing.T) {
+	search := &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New()}
+	searchRepo := new(MockSavedSearchRepository)
+	searchRepo.On("GetByID", mock.Anything, search.ID).Return(search, nil)
+
+	uc := NewSavedSearchUsecase(searchRepo, new(MockPostRepository), nil, entity.Currency("RUB"), logrus.New())
+	_, err := uc.MarkSeen(actorContext(uuid.New()), search.ID)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	searchRepo.AssertNotCalled(t, "MarkSeen", mock.Anything, mock.Anything)
+}
@@ -13,4 +13,6 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByUsername(ctx context.Context, username string) (*entity.User, error)
+	Restore(ctx context.Context, id uuid.UUID) error
 }
@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"marketplace/internal/apperror"
 	"marketplace/internal/entity"
 	usecaseAuth "marketplace/internal/usecase/auth"
+	"marketplace/internal/usecase/policy"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,50 +15,51 @@ import (
 )
 
 type UserUseCase struct {
-	userRepo UserRepository
-	authRepo usecaseAuth.AuthService
-	logger   *logrus.Logger
+	userRepo    UserRepository
+	authRepo    usecaseAuth.AuthService
+	sessionRepo usecaseAuth.SessionUseCaseRepo
+	logger      *logrus.Logger
 }
 
-func NewUserUseCase(userRepo UserRepository, authRepo usecaseAuth.AuthService, logger *logrus.Logger) *UserUseCase {
+func NewUserUseCase(userRepo UserRepository, authRepo usecaseAuth.AuthService, sessionRepo usecaseAuth.SessionUseCaseRepo, logger *logrus.Logger) *UserUseCase {
 	return &UserUseCase{
-		userRepo: userRepo,
-		authRepo: authRepo,
-		logger:   logger,
+		userRepo:    userRepo,
+		authRepo:    authRepo,
+		sessionRepo: sessionRepo,
+		logger:      logger,
 	}
 }
 
-func (uc *UserUseCase) Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
-	if err := ValidatePassword(password); err != nil {
-		return nil, "", fmt.Errorf("validate password: %w", err)
+func (uc *UserUseCase) Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
+	user := &entity.User{
+		ID:        uuid.New(),
+		Username:  username,
+		Role:      entity.RoleUser,
+		CreatedAt: time.Now(),
+	}
+	if err := apperror.Merge(user.Validate(), ValidatePassword(password)); err != nil {
+		return nil, nil, fmt.Errorf("validate user: %w", err)
 	}
+
 	if _, err := uc.userRepo.GetByUsername(ctx, username); err == nil {
-		return nil, "", fmt.Errorf("username already exists")
+		return nil, nil, apperror.Conflict("username already exists")
+	} else if !errors.Is(err, apperror.ErrNotFound) {
+		return nil, nil, fmt.Errorf("check username: %w", err)
 	}
 
 	hashedPassword, err := uc.authRepo.GeneratePasswordHash(password)
 	if err != nil {
-		return nil, "", fmt.Errorf("hash password: %w", err)
-	}
-
-	user := &entity.User{
-		ID:             uuid.New(),
-		Username:       username,
-		HashedPassword: hashedPassword,
-		CreatedAt:      time.Now(),
-	}
-
-	if err := user.Validate(); err != nil {
-		return nil, "", fmt.Errorf("validate user: %w", err)
+		return nil, nil, fmt.Errorf("hash password: %w", err)
 	}
+	user.HashedPassword = hashedPassword
 
 	if err := uc.userRepo.Create(ctx, user); err != nil {
-		return nil, "", fmt.Errorf("create user: %w", err)
+		return nil, nil, fmt.Errorf("create user: %w", err)
 	}
 
-	token, err := uc.authRepo.GenerateJWT(user.ID)
+	tokens, err := uc.sessionRepo.Start(ctx, user)
 	if err != nil {
-		return nil, "", fmt.Errorf("generate jwt: %w", err)
+		return nil, nil, fmt.Errorf("start session: %w", err)
 	}
 
 	uc.logger.WithFields(logrus.Fields{
@@ -63,22 +67,25 @@ func (uc *UserUseCase) Register(ctx context.Context, username, password string)
 		"user_id":  user.ID,
 	}).Info("User registered")
 
-	return user.ToDTO(), token, nil
+	return user.ToDTO(), tokens, nil
 }
 
-func (uc *UserUseCase) Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error) {
+func (uc *UserUseCase) Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
 	user, err := uc.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, "", fmt.Errorf("get user: %w", err)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, nil, apperror.Unauthorized("invalid username or password")
+		}
+		return nil, nil, fmt.Errorf("get user: %w", err)
 	}
 
 	if err := uc.authRepo.VerifyPassword(user.HashedPassword, password); err != nil {
-		return nil, "", fmt.Errorf("verify password: %w", err)
+		return nil, nil, apperror.Unauthorized("invalid username or password")
 	}
 
-	token, err := uc.authRepo.GenerateJWT(user.ID)
+	tokens, err := uc.sessionRepo.Start(ctx, user)
 	if err != nil {
-		return nil, "", fmt.Errorf("generate jwt: %w", err)
+		return nil, nil, fmt.Errorf("start session: %w", err)
 	}
 
 	uc.logger.WithFields(logrus.Fields{
@@ -86,7 +93,7 @@ func (uc *UserUseCase) Login(ctx context.Context, username, password string) (*e
 		"user_id":  user.ID,
 	}).Info("User logged in")
 
-	return user.ToDTO(), token, nil
+	return user.ToDTO(), tokens, nil
 }
 
 func (uc *UserUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
@@ -103,9 +110,9 @@ func (uc *UserUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.User,
 }
 
 func (uc *UserUseCase) Update(ctx context.Context, id uuid.UUID, username, password string) error {
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	if !ok || userID != id {
-		return fmt.Errorf("unauthorized")
+	actor, err := policy.AuthorizeManageUser(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	user, err := uc.userRepo.GetByID(ctx, id)
@@ -116,12 +123,18 @@ func (uc *UserUseCase) Update(ctx context.Context, id uuid.UUID, username, passw
 	if username != "" && username != user.Username {
 		_, err := uc.userRepo.GetByUsername(ctx, username)
 		if err == nil {
-			return fmt.Errorf("username %s already exists", username)
+			return apperror.Conflict("username %s already exists", username)
+		}
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return fmt.Errorf("check username: %w", err)
 		}
 		user.Username = username
 	}
 
 	if password != "" {
+		if err := ValidatePassword(password); err != nil {
+			return fmt.Errorf("validate password: %w", err)
+		}
 		hashedPassword, err := uc.authRepo.GeneratePasswordHash(password)
 		if err != nil {
 			return fmt.Errorf("hash password: %w", err)
@@ -130,7 +143,7 @@ func (uc *UserUseCase) Update(ctx context.Context, id uuid.UUID, username, passw
 	}
 
 	if username == "" && password == "" {
-		return fmt.Errorf("nothing to update")
+		return apperror.Validation("nothing to update")
 	}
 
 	if err := user.Validate(); err != nil {
@@ -142,16 +155,76 @@ func (uc *UserUseCase) Update(ctx context.Context, id uuid.UUID, username, passw
 	}
 
 	uc.logger.WithFields(logrus.Fields{
-		"user_id": user.ID,
+		"user_id":  user.ID,
+		"actor_id": actor.UserID,
 	}).Info("User updated")
 
 	return nil
 }
 
+// Patch применяет к пользователю JSON Merge Patch (RFC 7396): менять можно
+// имя и пароль.
+func (uc *UserUseCase) Patch(ctx context.Context, id uuid.UUID, patch []byte) error {
+	actor, err := policy.AuthorizeManageUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	var patched entity.UserPatch
+	if err := entity.ApplyMergePatch(user.PatchDocument(), &patched, patch); err != nil {
+		return fmt.Errorf("apply merge patch: %w", err)
+	}
+
+	var passwordErr error
+	if patched.Password != nil {
+		passwordErr = ValidatePassword(*patched.Password)
+	}
+
+	usernameChanged := patched.Username != user.Username
+	user.Username = patched.Username
+	if err := apperror.Merge(user.Validate(), passwordErr); err != nil {
+		return fmt.Errorf("validate user: %w", err)
+	}
+
+	if usernameChanged {
+		_, err := uc.userRepo.GetByUsername(ctx, user.Username)
+		if err == nil {
+			return apperror.Conflict("username %s already exists", user.Username)
+		}
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return fmt.Errorf("check username: %w", err)
+		}
+	}
+
+	if patched.Password != nil {
+		hashedPassword, err := uc.authRepo.GeneratePasswordHash(*patched.Password)
+		if err != nil {
+			return fmt.Errorf("hash password: %w", err)
+		}
+		user.HashedPassword = hashedPassword
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":  user.ID,
+		"actor_id": actor.UserID,
+	}).Info("User patched")
+
+	return nil
+}
+
 func (uc *UserUseCase) Delete(ctx context.Context, id uuid.UUID) error {
-	userID, ok := ctx.Value("user_id").(uuid.UUID)
-	if !ok || userID != id {
-		return fmt.Errorf("unauthorized")
+	actor, err := policy.AuthorizeManageUser(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	if err := uc.userRepo.Delete(ctx, id); err != nil {
@@ -159,8 +232,91 @@ func (uc *UserUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	uc.logger.WithFields(logrus.Fields{
-		"user_id": id,
+		"user_id":  id,
+		"actor_id": actor.UserID,
 	}).Info("User deleted")
 
 	return nil
 }
+
+// Restore восстанавливает удалённого пользователя по ID (администратор).
+func (uc *UserUseCase) Restore(ctx context.Context, id uuid.UUID) error {
+	actor, err := policy.AuthorizeManageUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.Restore(ctx, id); err != nil {
+		return fmt.Errorf("restore user: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":  id,
+		"actor_id": actor.UserID,
+	}).Info("User restored")
+
+	return nil
+}
+
+// RestoreAccount позволяет владельцу восстановить удалённый аккаунт по логину
+// и паролю: после удаления все его сессии отозваны, поэтому JWT у него нет.
+func (uc *UserUseCase) RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error) {
+	user, err := uc.userRepo.GetDeletedByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, nil, apperror.Unauthorized("invalid username or password")
+		}
+		return nil, nil, fmt.Errorf("get deleted user: %w", err)
+	}
+
+	if err := uc.authRepo.VerifyPassword(user.HashedPassword, password); err != nil {
+		return nil, nil, apperror.Unauthorized("invalid username or password")
+	}
+
+	if err := uc.userRepo.Restore(ctx, user.ID); err != nil {
+		return nil, nil, fmt.Errorf("restore user: %w", err)
+	}
+
+	tokens, err := uc.sessionRepo.Start(ctx, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("start session: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"username": username,
+		"user_id":  user.ID,
+	}).Info("User account restored")
+
+	return user.ToDTO(), tokens, nil
+}
+
+func (uc *UserUseCase) ChangeRole(ctx context.Context, id uuid.UUID, role entity.Role) error {
+	actor, err := policy.AuthorizeChangeRoles(ctx)
+	if err != nil {
+		return err
+	}
+	if !role.IsValid() {
+		return apperror.Validation("invalid role %q", role)
+	}
+	if actor.UserID == id && role != entity.RoleAdmin {
+		return apperror.Forbidden("admins can't demote themselves")
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	user.Role = role
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"user_id":  id,
+		"role":     role,
+		"actor_id": actor.UserID,
+	}).Info("User role changed")
+
+	return nil
+}
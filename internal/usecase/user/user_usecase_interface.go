@@ -8,9 +8,13 @@ import (
 )
 
 type UserUseCaseRepo interface {
-	Register(ctx context.Context, username, password string) (*entity.UserDTO, string, error)
-	Login(ctx context.Context, username, password string) (*entity.UserDTO, string, error)
+	Register(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
+	Login(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	Update(ctx context.Context, id uuid.UUID, username, password string) error
+	Patch(ctx context.Context, id uuid.UUID, patch []byte) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	RestoreAccount(ctx context.Context, username, password string) (*entity.UserDTO, *entity.TokenPair, error)
+	ChangeRole(ctx context.Context, id uuid.UUID, role entity.Role) error
 }
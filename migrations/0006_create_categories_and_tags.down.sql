@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_tags;
+DROP INDEX IF EXISTS idx_posts_category_id;
+ALTER TABLE posts DROP COLUMN tags, DROP COLUMN category_id;
+DROP TABLE IF EXISTS categories;
\ No newline at end of file
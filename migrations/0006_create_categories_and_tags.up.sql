@@ -0,0 +1,16 @@
+CREATE TABLE categories (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+ALTER TABLE posts
+    ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_posts_category_id ON posts(category_id);
+CREATE INDEX idx_posts_tags ON posts USING GIN (tags);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_image_id;
+ALTER TABLE posts DROP COLUMN image_id;
+DROP TABLE IF EXISTS images;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE images (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_images_owner_id ON images(owner_id);
+
+ALTER TABLE posts ADD COLUMN image_id UUID REFERENCES images(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_posts_image_id ON posts(image_id);
@@ -0,0 +1,8 @@
+ALTER TABLE posts ADD COLUMN image_id UUID REFERENCES images(id) ON DELETE SET NULL;
+CREATE INDEX idx_posts_image_id ON posts(image_id);
+
+UPDATE posts p SET image_id = pi.image_id
+FROM post_images pi
+WHERE pi.post_id = p.id AND pi.is_cover;
+
+DROP TABLE IF EXISTS post_images;
\ No newline at end of file
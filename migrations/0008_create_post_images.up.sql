@@ -0,0 +1,18 @@
+CREATE TABLE post_images (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (post_id, image_id),
+    UNIQUE (post_id, position)
+);
+
+-- У поста не больше одной обложки.
+CREATE UNIQUE INDEX idx_post_images_cover ON post_images(post_id) WHERE is_cover;
+CREATE INDEX idx_post_images_image_id ON post_images(image_id);
+
+INSERT INTO post_images (post_id, image_id, position, is_cover)
+SELECT id, image_id, 0, TRUE FROM posts WHERE image_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_posts_image_id;
+ALTER TABLE posts DROP COLUMN image_id;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_images_variants_pending;
+ALTER TABLE images DROP COLUMN variants_generated_at;
+DROP TABLE IF EXISTS image_variants;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE image_variants (
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    width INT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    height INT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (image_id, width, format)
+);
+
+-- NULL означает, что варианты изображения ещё не построены.
+ALTER TABLE images ADD COLUMN variants_generated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_images_variants_pending ON images(created_at) WHERE variants_generated_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_status_created_at;
+ALTER TABLE posts DROP COLUMN status;
\ No newline at end of file
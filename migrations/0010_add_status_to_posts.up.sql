@@ -0,0 +1,6 @@
+-- Уже опубликованные посты остаются видимыми, новые по умолчанию — черновики.
+ALTER TABLE posts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'reserved', 'sold', 'archived'));
+ALTER TABLE posts ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX idx_posts_status_created_at ON posts(status, created_at DESC);
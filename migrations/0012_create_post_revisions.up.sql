@@ -0,0 +1,22 @@
+CREATE TABLE post_revisions (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    header VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    image VARCHAR(255),
+    price FLOAT8 NOT NULL,
+    category_id UUID,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    image_ids UUID[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (post_id, revision)
+);
+
+-- Первая ревизия существующих постов — их текущее состояние от имени автора.
+INSERT INTO post_revisions (id, post_id, revision, editor_id, header, content, image, price, category_id, tags, image_ids, created_at)
+SELECT gen_random_uuid(), p.id, 1, p.author_id, p.header, p.content, p.image, p.price, p.category_id, p.tags,
+       COALESCE((SELECT array_agg(pi.image_id ORDER BY pi.position) FROM post_images pi WHERE pi.post_id = p.id), '{}'),
+       p.created_at
+FROM posts p;
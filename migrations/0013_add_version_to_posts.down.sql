@@ -0,0 +1 @@
+ALTER TABLE posts DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Версия увеличивается при каждом изменении поста и служит ETag.
+ALTER TABLE posts ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_posts_currency_price;
+
+ALTER TABLE post_revisions DROP COLUMN currency;
+ALTER TABLE post_revisions ALTER COLUMN price TYPE FLOAT8 USING price / 100.0;
+
+ALTER TABLE posts DROP COLUMN currency;
+ALTER TABLE posts ALTER COLUMN price TYPE FLOAT8 USING price / 100.0;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Цена хранится целым числом в минимальных единицах валюты (копейках, центах)
+-- вместе с кодом валюты ISO 4217. Существующие цены считаются рублёвыми.
+ALTER TABLE posts ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::BIGINT;
+ALTER TABLE posts ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE posts ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE post_revisions ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::BIGINT;
+ALTER TABLE post_revisions ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE post_revisions ALTER COLUMN currency DROP DEFAULT;
+
+CREATE INDEX idx_posts_currency_price ON posts (currency, price) WHERE deleted_at IS NULL;
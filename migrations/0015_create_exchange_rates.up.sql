@@ -0,0 +1,10 @@
+-- Курсы валют, которые ведут администраторы: 1 base = rate quote.
+-- Для пересчёта в обратную сторону используется 1 / rate.
+CREATE TABLE exchange_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (base, quote),
+    CHECK (base <> quote)
+);
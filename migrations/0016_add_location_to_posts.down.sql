@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_lat_lng;
+ALTER TABLE posts DROP CONSTRAINT posts_location_check;
+ALTER TABLE posts DROP COLUMN city;
+ALTER TABLE posts DROP COLUMN lng;
+ALTER TABLE posts DROP COLUMN lat;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Координаты места, где находится товар, в градусах WGS 84, и город
+-- в свободной форме. Координаты задаются обе или ни одной.
+ALTER TABLE posts ADD COLUMN lat DOUBLE PRECISION CHECK (lat BETWEEN -90 AND 90);
+ALTER TABLE posts ADD COLUMN lng DOUBLE PRECISION CHECK (lng BETWEEN -180 AND 180);
+ALTER TABLE posts ADD COLUMN city TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD CONSTRAINT posts_location_check CHECK ((lat IS NULL) = (lng IS NULL));
+
+-- Поиск по радиусу и прямоугольнику сначала отбирает посты по диапазону широт.
+CREATE INDEX idx_posts_lat_lng ON posts (lat, lng) WHERE deleted_at IS NULL AND lat IS NOT NULL;
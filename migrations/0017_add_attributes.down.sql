@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_attributes;
+ALTER TABLE posts DROP COLUMN attributes;
+ALTER TABLE categories DROP COLUMN attributes;
\ No newline at end of file
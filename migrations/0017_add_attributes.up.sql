@@ -0,0 +1,6 @@
+-- Схема характеристик товара задаётся категорией, значения хранятся в посте.
+ALTER TABLE categories ADD COLUMN attributes JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE posts ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Фильтры attr.* проверяют вхождение документа оператором @>.
+CREATE INDEX idx_posts_attributes ON posts USING GIN (attributes jsonb_path_ops);
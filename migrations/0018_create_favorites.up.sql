@@ -0,0 +1,11 @@
+CREATE TABLE favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- Подсчёт добавлений поста в избранное.
+CREATE INDEX idx_favorites_post_id ON favorites(post_id);
+-- Избранное пользователя, начиная с последних.
+CREATE INDEX idx_favorites_user_id_created_at ON favorites(user_id, created_at DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_searches_unmatched;
+ALTER TABLE posts DROP COLUMN IF EXISTS searches_matched_at;
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
\ No newline at end of file
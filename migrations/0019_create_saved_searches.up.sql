@@ -0,0 +1,28 @@
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_saved_searches_user_id ON saved_searches(user_id, created_at DESC);
+
+CREATE TABLE saved_search_matches (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    seen_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (saved_search_id, post_id)
+);
+
+-- Непросмотренные совпадения поиска, начиная с последних.
+CREATE INDEX idx_saved_search_matches_unseen ON saved_search_matches(saved_search_id, created_at DESC) WHERE seen_at IS NULL;
+CREATE INDEX idx_saved_search_matches_post_id ON saved_search_matches(post_id);
+
+-- Время, когда пост сверили с сохранёнными поисками. Уже существующие посты
+-- считаются сверенными, новые ждут фонового подбора.
+ALTER TABLE posts ADD COLUMN searches_matched_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+ALTER TABLE posts ALTER COLUMN searches_matched_at DROP DEFAULT;
+
+CREATE INDEX idx_posts_searches_unmatched ON posts(created_at) WHERE searches_matched_at IS NULL;
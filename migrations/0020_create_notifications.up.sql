@@ -0,0 +1,21 @@
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('new_message', 'post_favorited', 'saved_search_match', 'post_moderated')),
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Уведомления пользователя, начиная с последних.
+CREATE INDEX idx_notifications_user_id_created_at ON notifications(user_id, created_at DESC);
+-- Подсчёт непрочитанных.
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Хранятся только явно заданные настройки; остальные виды включены.
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, kind)
+);
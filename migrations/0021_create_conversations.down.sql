@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS conversations;
\ No newline at end of file
@@ -0,0 +1,36 @@
+CREATE TABLE conversations (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_message_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (post_id, buyer_id),
+    CHECK (buyer_id <> seller_id)
+);
+
+-- Переписки участника, начиная с последних.
+CREATE INDEX idx_conversations_buyer_id ON conversations(buyer_id, last_message_at DESC);
+CREATE INDEX idx_conversations_seller_id ON conversations(seller_id, last_message_at DESC);
+
+CREATE TABLE messages (
+    id UUID PRIMARY KEY,
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Страницы переписки по курсору (created_at, id).
+CREATE INDEX idx_messages_conversation_id ON messages(conversation_id, created_at DESC, id DESC);
+-- Подсчёт непрочитанных.
+CREATE INDEX idx_messages_unread ON messages(conversation_id, sender_id) WHERE read_at IS NULL;
+
+CREATE TABLE user_blocks (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, blocked_user_id),
+    CHECK (user_id <> blocked_user_id)
+);
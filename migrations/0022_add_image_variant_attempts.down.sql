@@ -0,0 +1,2 @@
+ALTER TABLE images DROP COLUMN variants_retry_at;
+ALTER TABLE images DROP COLUMN variant_attempts;
\ No newline at end of file
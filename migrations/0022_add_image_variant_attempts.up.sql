@@ -0,0 +1,4 @@
+-- Неудачная генерация копий откладывается с растущей паузой, чтобы одно
+-- изображение не задерживало очередь.
+ALTER TABLE images ADD COLUMN variant_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN variants_retry_at TIMESTAMP WITH TIME ZONE;
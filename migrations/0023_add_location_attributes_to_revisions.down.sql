@@ -0,0 +1,4 @@
+ALTER TABLE post_revisions DROP COLUMN city;
+ALTER TABLE post_revisions DROP COLUMN lng;
+ALTER TABLE post_revisions DROP COLUMN lat;
+ALTER TABLE post_revisions DROP COLUMN attributes;
\ No newline at end of file
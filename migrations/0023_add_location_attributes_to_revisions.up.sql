@@ -0,0 +1,12 @@
+ALTER TABLE post_revisions ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE post_revisions ADD COLUMN lat DOUBLE PRECISION;
+ALTER TABLE post_revisions ADD COLUMN lng DOUBLE PRECISION;
+ALTER TABLE post_revisions ADD COLUMN city TEXT NOT NULL DEFAULT '';
+
+-- Последняя ревизия совпадает с текущим состоянием поста; более ранние
+-- значения этих полей не сохранились.
+UPDATE post_revisions r
+SET attributes = p.attributes, lat = p.lat, lng = p.lng, city = p.city
+FROM posts p
+WHERE p.id = r.post_id
+  AND r.revision = (SELECT MAX(revision) FROM post_revisions WHERE post_id = r.post_id);
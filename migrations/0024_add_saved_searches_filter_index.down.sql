@@ -0,0 +1 @@
+DROP INDEX idx_saved_searches_filter_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Фоновый подбор совпадений обходит поиски страницами в порядке фильтра,
+-- чтобы поиски с одинаковыми фильтрами проверялись одним запросом.
+CREATE INDEX idx_saved_searches_filter_id ON saved_searches(filter, id);
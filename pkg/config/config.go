@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"marketplace/pkg/migrate"
+	"marketplace/pkg/storage"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -30,8 +32,39 @@ type Config struct {
 		Enabled bool   `yaml:"enabled"`
 	} `yaml:"migrations"`
 	JWT struct {
-		SecretKey string `yaml:"secret_key"`
+		SecretKey    string        `yaml:"secret_key"`
+		KeysDir      string        `yaml:"keys_dir"`
+		SigningKeyID string        `yaml:"signing_key_id"`
+		AccessTTL    time.Duration `yaml:"access_ttl"`
+		RefreshTTL   time.Duration `yaml:"refresh_ttl"`
 	} `yaml:"jwt"`
+	Images struct {
+		MaxSize   int64 `yaml:"max_size"`
+		MaxPixels int   `yaml:"max_pixels"`
+		Storage   struct {
+			Driver   string           `yaml:"driver"`
+			LocalDir string           `yaml:"local_dir"`
+			S3       storage.S3Config `yaml:"s3"`
+		} `yaml:"storage"`
+		Variants struct {
+			Widths       []int         `yaml:"widths"`
+			PollInterval time.Duration `yaml:"poll_interval"`
+		} `yaml:"variants"`
+	} `yaml:"images"`
+	Purge struct {
+		Retention time.Duration `yaml:"retention"`
+		Interval  time.Duration `yaml:"interval"`
+	} `yaml:"purge"`
+	Currency struct {
+		Default string `yaml:"default"`
+	} `yaml:"currency"`
+	SavedSearches struct {
+		PollInterval time.Duration `yaml:"poll_interval"`
+	} `yaml:"saved_searches"`
+	PostStream struct {
+		ReplaySize     int `yaml:"replay_size"`
+		MaxSubscribers int `yaml:"max_subscribers"`
+	} `yaml:"post_stream"`
 	DatabaseDSN string
 }
 
@@ -58,9 +91,60 @@ func LoadConfig() (*Config, error) {
 		cfg.Database.SSLMode,
 	)
 
-	if cfg.JWT.SecretKey == "" {
-		logrus.Error("JWT secret key is required in config.yaml")
-		return nil, fmt.Errorf("jwt.secret_key cannot be empty")
+	if cfg.JWT.SecretKey == "" && cfg.JWT.KeysDir == "" {
+		logrus.Error("JWT secret key or keys directory is required in config.yaml")
+		return nil, fmt.Errorf("jwt.secret_key or jwt.keys_dir must be set")
+	}
+	if cfg.JWT.AccessTTL <= 0 {
+		cfg.JWT.AccessTTL = 15 * time.Minute
+	}
+	if cfg.JWT.RefreshTTL <= 0 {
+		cfg.JWT.RefreshTTL = 30 * 24 * time.Hour
+	}
+
+	if cfg.Images.MaxSize <= 0 {
+		cfg.Images.MaxSize = 10 << 20
+	}
+	if cfg.Images.MaxPixels <= 0 {
+		cfg.Images.MaxPixels = 40_000_000
+	}
+	switch cfg.Images.Storage.Driver {
+	case "":
+		cfg.Images.Storage.Driver = "local"
+	case "local", "s3":
+	default:
+		return nil, fmt.Errorf("unknown images.storage.driver %q", cfg.Images.Storage.Driver)
+	}
+	if cfg.Images.Storage.LocalDir == "" {
+		cfg.Images.Storage.LocalDir = "./uploads"
+	}
+	if len(cfg.Images.Variants.Widths) == 0 {
+		cfg.Images.Variants.Widths = []int{320, 640, 1280}
+	}
+	if cfg.Images.Variants.PollInterval <= 0 {
+		cfg.Images.Variants.PollInterval = time.Minute
+	}
+
+	if cfg.Purge.Retention <= 0 {
+		cfg.Purge.Retention = 30 * 24 * time.Hour
+	}
+	if cfg.Purge.Interval <= 0 {
+		cfg.Purge.Interval = time.Hour
+	}
+
+	if cfg.Currency.Default == "" {
+		cfg.Currency.Default = "RUB"
+	}
+
+	if cfg.SavedSearches.PollInterval <= 0 {
+		cfg.SavedSearches.PollInterval = time.Minute
+	}
+
+	if cfg.PostStream.ReplaySize <= 0 {
+		cfg.PostStream.ReplaySize = 200
+	}
+	if cfg.PostStream.MaxSubscribers <= 0 {
+		cfg.PostStream.MaxSubscribers = 1000
 	}
 
 	if cfg.Migrations.Enabled {
@@ -0,0 +1,75 @@
+// Package mergepatch реализует JSON Merge Patch (RFC 7396).
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ContentType — медиатип документа JSON Merge Patch.
+const ContentType = "application/merge-patch+json"
+
+// Apply применяет patch к документу target и возвращает результат.
+// null в патче удаляет ключ, объекты сливаются рекурсивно, любые другие
+// значения заменяют исходные целиком.
+func Apply(target, patch []byte) ([]byte, error) {
+	patchValue, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("decode patch: %w", err)
+	}
+
+	var targetValue interface{}
+	if len(bytes.TrimSpace(target)) > 0 {
+		if targetValue, err = decode(target); err != nil {
+			return nil, fmt.Errorf("decode target: %w", err)
+		}
+	}
+
+	return json.Marshal(merge(targetValue, patchValue))
+}
+
+// IsObject сообщает, является ли документ JSON-объектом.
+func IsObject(doc []byte) bool {
+	value, err := decode(doc)
+	if err != nil {
+		return false
+	}
+	_, ok := value.(map[string]interface{})
+	return ok
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{}, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// decode разбирает документ, сохраняя числа как есть, без потери точности.
+func decode(doc []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
@@ -0,0 +1,112 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Примеры из приложения A RFC 7396.
+func TestApply(t *testing.T) {
+	tests := []struct {
+		target string
+		patch  string
+		want   string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{``, `{"price":0}`, `{"price":0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" + "+tt.patch, func(t *testing.T) {
+			got, err := Apply([]byte(tt.target), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_InvalidPatch(t *testing.T) {
+	for _, patch := range []string{``, `{`, `{"a":1} {}`} {
+		if _, err := Apply([]byte(`{}`), []byte(patch)); err == nil {
+			t.Errorf("Apply(%q) error = nil, want error", patch)
+		}
+	}
+}
+
+func TestIsObject(t *testing.T) {
+	tests := map[string]bool{
+		`{"a":1}`: true,
+		`{}`:      true,
+		`[]`:      false,
+		`null`:    false,
+		`"a"`:     false,
+		`{`:       false,
+	}
+	for doc, want := range tests {
+		if got := IsObject([]byte(doc)); got != want {
+			t.Errorf("IsObject(%s) = %v, want %v", doc, got, want)
+		}
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("unmarshal %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestReadRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{"merge patch", "application/merge-patch+json", `{"a":1}`, nil},
+		{"json with charset", "application/json; charset=utf-8", `{"a":1}`, nil},
+		{"form", "application/x-www-form-urlencoded", `a=1`, ErrUnsupportedMediaType},
+		{"too large", ContentType, `"` + strings.Repeat("a", MaxSize) + `"`, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PATCH", "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			patch, err := ReadRequest(httptest.NewRecorder(), r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(patch) != tt.body {
+				t.Errorf("ReadRequest() = %s, want %s", patch, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package mergepatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// MaxSize ограничивает размер документа, который читает ReadRequest.
+const MaxSize = 1 << 20
+
+var (
+	// ErrUnsupportedMediaType — тело запроса пришло не в формате JSON.
+	ErrUnsupportedMediaType = errors.New("unsupported merge patch media type")
+	// ErrTooLarge — документ больше MaxSize.
+	ErrTooLarge = errors.New("merge patch too large")
+)
+
+// ReadRequest читает из тела запроса документ JSON Merge Patch. Кроме
+// application/merge-patch+json принимается и обычный application/json.
+func ReadRequest(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case ContentType, "application/json":
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrTooLarge
+		}
+		return nil, fmt.Errorf("read merge patch: %w", err)
+	}
+	return patch, nil
+}
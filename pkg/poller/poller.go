@@ -0,0 +1,48 @@
+// Package poller запускает фоновую обработку по таймеру и по сигналу.
+package poller
+
+import (
+	"context"
+	"time"
+)
+
+// Poller вызывает обработку при запуске, затем раз в интервал и после каждого
+// Notify. Работа должна браться из базы: тогда после перезапуска ничего не
+// теряется, а Notify лишь ускоряет обработку.
+type Poller struct {
+	interval time.Duration
+	wake     chan struct{}
+}
+
+func New(interval time.Duration) *Poller {
+	return &Poller{
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Notify будит обработку, не дожидаясь таймера. Не блокируется; сигналы,
+// пришедшие во время обработки, сливаются в один.
+func (p *Poller) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run вызывает process, пока не будет отменён контекст.
+func (p *Poller) Run(ctx context.Context, process func(ctx context.Context)) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		process(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+	}
+}
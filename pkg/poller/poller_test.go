@@ -0,0 +1,36 @@
+package poller
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	p := New(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx, func(context.Context) { calls <- struct{}{} })
+		close(done)
+	}()
+
+	// Первая обработка — сразу при запуске, следующая — по Notify.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatalf("process call %d did not happen", i+1)
+		}
+		p.Notify()
+		p.Notify()
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after cancel")
+	}
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	testStorage(t, s)
+
+	assert.Error(t, s.Put(context.Background(), "../escape.jpg", []byte("x"), "image/jpeg"))
+}
+
+func TestS3Storage(t *testing.T) {
+	server := newFakeS3(t, "minioadmin", "minioadmin")
+	defer server.Close()
+
+	s, err := NewS3Storage(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "marketplace",
+		AccessKey: "minioadmin",
+		SecretKey: "minioadmin",
+	}, server.Client())
+	require.NoError(t, err)
+
+	testStorage(t, s)
+}
+
+func TestS3Storage_WrongCredentials(t *testing.T) {
+	server := newFakeS3(t, "minioadmin", "minioadmin")
+	defer server.Close()
+
+	s, err := NewS3Storage(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "marketplace",
+		AccessKey: "minioadmin",
+		SecretKey: "wrong",
+	}, server.Client())
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "images/a.jpg", []byte("data"), "image/jpeg")
+	assert.ErrorContains(t, err, "unexpected status 403")
+}
+
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "images/missing.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Put(ctx, "images/a.jpg", []byte("jpeg data"), "image/jpeg"))
+
+	reader, err := s.Get(ctx, "images/a.jpg")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg data", string(data))
+
+	require.NoError(t, s.Delete(ctx, "images/a.jpg"))
+	_, err = s.Get(ctx, "images/a.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Повторное удаление не считается ошибкой.
+	assert.NoError(t, s.Delete(ctx, "images/a.jpg"))
+}
+
+// newFakeS3 поднимает минимальную замену MinIO: хранит объекты в памяти и
+// проверяет подпись SigV4 каждого запроса.
+func newFakeS3(t *testing.T, accessKey, secretKey string) *httptest.Server {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if !validSignature(r, body, accessKey, secretKey) {
+			http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/marketplace/") {
+			http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+// validSignature пересчитывает подпись запроса так же, как это делает сервер S3.
+func validSignature(r *http.Request, body []byte, accessKey, secretKey string) bool {
+	if r.Header.Get("X-Amz-Content-Sha256") != hashHex(body) {
+		return false
+	}
+	signedAt, err := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	check, _ := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	check.Header.Set("X-Amz-Content-Sha256", r.Header.Get("X-Amz-Content-Sha256"))
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		check.Header.Set("Content-Type", contentType)
+	}
+	signV4(check, hashHex(body), credentials{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Region:    "us-east-1",
+		Service:   "s3",
+	}, signedAt)
+
+	return check.Header.Get("Authorization") == r.Header.Get("Authorization")
+}